@@ -0,0 +1,126 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package graphql parses and executes GraphQL queries against objects that resolve their own fields
+// It supports the query language needed to read from a schema (operations, variables, aliases, arguments, fragments
+// and the @skip and @include directives) but not mutations, subscriptions or introspection
+package graphql
+
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+type Operation struct {
+	Type         string // query, mutation or subscription
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet []Selection
+}
+
+type VariableDefinition struct {
+	Name         string
+	Type         string
+	DefaultValue Value
+}
+
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+}
+
+// A Selection is a *Field, *FragmentSpread or *InlineFragment
+type Selection interface {
+	directives() []*Directive
+}
+
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    map[string]Value
+	Directives   []*Directive
+	SelectionSet []Selection
+}
+
+// Returns the key the field's value is given under in the response
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+}
+
+type Directive struct {
+	Name      string
+	Arguments map[string]Value
+}
+
+func (f *Field) directives() []*Directive          { return f.Directives }
+func (f *FragmentSpread) directives() []*Directive { return f.Directives }
+func (f *InlineFragment) directives() []*Directive { return f.Directives }
+
+// A Value is an argument or default value, resolved against the variables of a request
+// Ints resolve to int64, floats to float64, enums to their name as a string, lists to []interface{} and objects to map[string]interface{}
+type Value interface {
+	Resolve(variables map[string]interface{}) interface{}
+}
+
+type Variable string
+
+type Literal struct {
+	Value interface{}
+}
+
+type ListValue []Value
+
+type ObjectValue map[string]Value
+
+func (v Variable) Resolve(variables map[string]interface{}) interface{} {
+	return variables[string(v)]
+}
+
+func (l Literal) Resolve(variables map[string]interface{}) interface{} {
+	return l.Value
+}
+
+func (l ListValue) Resolve(variables map[string]interface{}) interface{} {
+	list := make([]interface{}, 0, len(l))
+	for _, value := range l {
+		list = append(list, value.Resolve(variables))
+	}
+	return list
+}
+
+func (o ObjectValue) Resolve(variables map[string]interface{}) interface{} {
+	object := make(map[string]interface{}, len(o))
+	for name, value := range o {
+		object[name] = value.Resolve(variables)
+	}
+	return object
+}
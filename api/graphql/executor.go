@@ -0,0 +1,366 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// An Object is a value of a composite type that resolves the fields selected from it
+// Resolve returns nil, a scalar that can be marshalled to JSON, another Object or a slice of either
+type Object interface {
+	TypeName() string
+	Resolve(field string, arguments map[string]interface{}) (interface{}, error)
+}
+
+// Objects that implement interfaces report them so that fragments on those interfaces apply to them
+type Implementer interface {
+	Interfaces() []string
+}
+
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []Error     `json:"errors,omitempty"`
+}
+
+// Errors resolving a field carry the path to that field; the field is returned as null
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Bounds the work a single request can cause; a limit of 0 is not enforced
+type Limits struct {
+	// The deepest nesting of selection sets an operation may have, counting fragments as part of the selection they are spread in
+	MaxDepth int
+	// The number of fields with a selection of subfields that may be resolved, across every object of the response
+	MaxObjectFields int
+}
+
+// Parses the request's query and executes the selected operation against the root object, within the limits
+func Execute(root Object, request Request, limits Limits) *Response {
+	document, err := Parse(request.Query)
+	if err != nil {
+		return &Response{Errors: []Error{{Message: err.Error()}}}
+	}
+	operation, err := selectOperation(document, request.OperationName)
+	if err != nil {
+		return &Response{Errors: []Error{{Message: err.Error()}}}
+	}
+	variables, err := coerceVariables(operation, request.Variables)
+	if err != nil {
+		return &Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{document: document, variables: variables, limits: limits, fragmentDepths: map[string]int{}}
+	if depth := e.depth(operation.SelectionSet, map[string]bool{}); limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return &Response{Errors: []Error{{Message: fmt.Sprintf("query depth %d exceeds the maximum of %d", depth, limits.MaxDepth)}}}
+	}
+	data := e.selectionSet(root, []*Field{{SelectionSet: operation.SelectionSet}}, nil)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func selectOperation(document *Document, name string) (*Operation, error) {
+	var operation *Operation
+	if name == "" {
+		if len(document.Operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document contains more than one operation")
+		}
+		operation = document.Operations[0]
+	} else {
+		for _, candidate := range document.Operations {
+			if candidate.Name == name {
+				operation = candidate
+			}
+		}
+		if operation == nil {
+			return nil, fmt.Errorf("unknown operation %q", name)
+		}
+	}
+	if operation.Type != "query" {
+		return nil, fmt.Errorf("%s operations are not supported", operation.Type)
+	}
+
+	return operation, nil
+}
+
+// Applies the operation's variable defaults and converts JSON numbers into int64 or float64 values
+func coerceVariables(operation *Operation, provided map[string]interface{}) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	for _, definition := range operation.Variables {
+		value, ok := provided[definition.Name]
+		if !ok && definition.DefaultValue != nil {
+			value, ok = definition.DefaultValue.Resolve(nil), true
+		}
+		if (!ok || value == nil) && strings.HasSuffix(definition.Type, "!") {
+			return nil, fmt.Errorf("variable $%s of required type %s was not provided", definition.Name, definition.Type)
+		}
+		variables[definition.Name] = coerceValue(value)
+	}
+
+	return variables, nil
+}
+
+func coerceValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, element := range v {
+			list = append(list, coerceValue(element))
+		}
+		return list
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			object[key] = coerceValue(element)
+		}
+		return object
+	}
+	return value
+}
+
+type executor struct {
+	document       *Document
+	variables      map[string]interface{}
+	limits         Limits
+	fragmentDepths map[string]int
+	objectFields   int
+	errors         []Error
+}
+
+// Returns the deepest nesting of selection sets in the selections, following fragment spreads that are not already being followed
+func (e *executor) depth(selections []Selection, following map[string]bool) int {
+	deepest := 0
+	for _, selection := range selections {
+		depth := 0
+		switch s := selection.(type) {
+		case *Field:
+			if len(s.SelectionSet) > 0 {
+				depth = 1 + e.depth(s.SelectionSet, following)
+			}
+		case *FragmentSpread:
+			fragment, ok := e.document.Fragments[s.Name]
+			if !ok || following[s.Name] {
+				continue
+			}
+			depth, ok = e.fragmentDepths[s.Name]
+			if !ok {
+				following[s.Name] = true
+				depth = e.depth(fragment.SelectionSet, following)
+				delete(following, s.Name)
+				e.fragmentDepths[s.Name] = depth
+			}
+		case *InlineFragment:
+			depth = e.depth(s.SelectionSet, following)
+		}
+		if depth > deepest {
+			deepest = depth
+		}
+	}
+
+	return deepest
+}
+
+// Resolves the merged selection sets of the fields against an object
+func (e *executor) selectionSet(object Object, fields []*Field, path []interface{}) orderedObject {
+	var selections []Selection
+	for _, field := range fields {
+		selections = append(selections, field.SelectionSet...)
+	}
+	keys, grouped := e.collectFields(object, selections, map[string]bool{}, nil, map[string][]*Field{})
+
+	result := make(orderedObject, 0, len(keys))
+	for _, key := range keys {
+		fieldPath := append(append([]interface{}{}, path...), key)
+		result = append(result, member{Key: key, Value: e.field(object, grouped[key], fieldPath)})
+	}
+	return result
+}
+
+func (e *executor) collectFields(object Object, selections []Selection, visited map[string]bool, keys []string, grouped map[string][]*Field) ([]string, map[string][]*Field) {
+	for _, selection := range selections {
+		if !e.included(selection.directives()) {
+			continue
+		}
+		switch s := selection.(type) {
+		case *Field:
+			key := s.ResponseKey()
+			if _, ok := grouped[key]; !ok {
+				keys = append(keys, key)
+			}
+			grouped[key] = append(grouped[key], s)
+		case *FragmentSpread:
+			fragment, ok := e.document.Fragments[s.Name]
+			if visited[s.Name] || !ok || !applies(object, fragment.TypeCondition) {
+				continue
+			}
+			visited[s.Name] = true
+			keys, grouped = e.collectFields(object, fragment.SelectionSet, visited, keys, grouped)
+		case *InlineFragment:
+			if applies(object, s.TypeCondition) {
+				keys, grouped = e.collectFields(object, s.SelectionSet, visited, keys, grouped)
+			}
+		}
+	}
+
+	return keys, grouped
+}
+
+func (e *executor) included(directives []*Directive) bool {
+	for _, directive := range directives {
+		condition, ok := directive.Arguments["if"]
+		if !ok {
+			continue
+		}
+		value, _ := condition.Resolve(e.variables).(bool)
+		if (directive.Name == "skip" && value) || (directive.Name == "include" && !value) {
+			return false
+		}
+	}
+	return true
+}
+
+func applies(object Object, typeCondition string) bool {
+	if typeCondition == "" || typeCondition == object.TypeName() {
+		return true
+	}
+	if implementer, ok := object.(Implementer); ok {
+		for _, name := range implementer.Interfaces() {
+			if name == typeCondition {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *executor) field(object Object, fields []*Field, path []interface{}) interface{} {
+	field := fields[0]
+	if field.Name == "__typename" {
+		return object.TypeName()
+	}
+	if len(field.SelectionSet) > 0 {
+		e.objectFields++
+		if e.limits.MaxObjectFields > 0 && e.objectFields > e.limits.MaxObjectFields {
+			// Only the first field over the limit is reported; the rest are returned as null without being resolved
+			if e.objectFields == e.limits.MaxObjectFields+1 {
+				e.errorf(path, "query resolves more than the maximum of %d fields with subfields", e.limits.MaxObjectFields)
+			}
+			return nil
+		}
+	}
+	arguments := make(map[string]interface{}, len(field.Arguments))
+	for name, value := range field.Arguments {
+		arguments[name] = value.Resolve(e.variables)
+	}
+
+	value, err := object.Resolve(field.Name, arguments)
+	if err != nil {
+		e.errorf(path, "%s", err.Error())
+		return nil
+	}
+	return e.complete(value, fields, path)
+}
+
+func (e *executor) complete(value interface{}, fields []*Field, path []interface{}) interface{} {
+	hasSelections := false
+	for _, field := range fields {
+		hasSelections = hasSelections || len(field.SelectionSet) > 0
+	}
+
+	if object, ok := value.(Object); ok {
+		if reflect.ValueOf(object).Kind() == reflect.Ptr && reflect.ValueOf(object).IsNil() {
+			return nil
+		}
+		if !hasSelections {
+			e.errorf(path, "field %s of type %s must have a selection of subfields", fields[0].Name, object.TypeName())
+			return nil
+		}
+		return e.selectionSet(object, fields, path)
+	}
+
+	reflected := reflect.ValueOf(value)
+	if value != nil && reflected.Kind() == reflect.Slice && reflected.Type().Elem().Kind() != reflect.Uint8 {
+		list := make([]interface{}, 0, reflected.Len())
+		for i := 0; i < reflected.Len(); i++ {
+			elementPath := append(append([]interface{}{}, path...), i)
+			list = append(list, e.complete(reflected.Index(i).Interface(), fields, elementPath))
+		}
+		return list
+	}
+
+	if value != nil && hasSelections {
+		e.errorf(path, "field %s is a scalar and cannot have a selection of subfields", fields[0].Name)
+		return nil
+	}
+	return value
+}
+
+func (e *executor) errorf(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, Error{Message: fmt.Sprintf(format, args...), Path: path})
+}
+
+type member struct {
+	Key   string
+	Value interface{}
+}
+
+// An object whose fields are marshalled in the order they were selected
+type orderedObject []member
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
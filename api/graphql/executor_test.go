@@ -0,0 +1,170 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graphql_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/api/graphql"
+)
+
+type fakeQuery struct {
+	arguments map[string]interface{}
+}
+
+func (q *fakeQuery) TypeName() string { return "Query" }
+
+func (q *fakeQuery) Resolve(field string, arguments map[string]interface{}) (interface{}, error) {
+	q.arguments = arguments
+	switch field {
+	case "domains":
+		return []*fakeDomain{{name: "eth"}, {name: "xyz"}}, nil
+	case "domain":
+		if arguments["id"] == nil {
+			return (*fakeDomain)(nil), nil
+		}
+		return &fakeDomain{name: fmt.Sprint(arguments["id"])}, nil
+	case "count":
+		return 2, nil
+	case "broken":
+		return nil, errors.New("broken field")
+	}
+	return nil, fmt.Errorf("unknown field %s", field)
+}
+
+type fakeDomain struct {
+	name string
+}
+
+func (d *fakeDomain) TypeName() string     { return "Domain" }
+func (d *fakeDomain) Interfaces() []string { return []string{"Entity"} }
+
+func (d *fakeDomain) Resolve(field string, arguments map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "name":
+		return d.name, nil
+	case "labels":
+		return []string{d.name}, nil
+	case "parent":
+		return &fakeDomain{name: "root"}, nil
+	}
+	return nil, fmt.Errorf("unknown field %s", field)
+}
+
+var _ = Describe("Execute", func() {
+	execute := func(request graphql.Request) string {
+		response, err := json.Marshal(graphql.Execute(&fakeQuery{}, request, graphql.Limits{}))
+		Expect(err).NotTo(HaveOccurred())
+		return string(response)
+	}
+
+	It("Returns the selected fields in the order they were requested", func() {
+		response := execute(graphql.Request{Query: `{ count domains { name labels __typename } first: domain(id: "eth") { parent { name } } }`})
+
+		Expect(response).To(MatchJSON(`{"data": {
+			"count": 2,
+			"domains": [{"name": "eth", "labels": ["eth"], "__typename": "Domain"}, {"name": "xyz", "labels": ["xyz"], "__typename": "Domain"}],
+			"first": {"parent": {"name": "root"}}
+		}}`))
+		Expect(response).To(HavePrefix(`{"data":{"count":2,"domains":[{"name":"eth","labels"`))
+	})
+
+	It("Merges fields from fragments that apply to the object", func() {
+		response := execute(graphql.Request{Query: `
+			{ domain(id: "eth") { ...Names ... on Entity { parent { name } } ... on Account { id } } }
+			fragment Names on Domain { name parent { labels } }
+		`})
+
+		Expect(response).To(MatchJSON(`{"data": {"domain": {"name": "eth", "parent": {"labels": ["root"], "name": "root"}}}}`))
+	})
+
+	It("Resolves variables, defaults and directives", func() {
+		query := &fakeQuery{}
+		response, err := json.Marshal(graphql.Execute(query, graphql.Request{
+			Query: `query Domain($id: String!, $skip: Boolean = true, $first: Int) {
+				domain(id: $id) { name @skip(if: $skip) labels @include(if: false) }
+				domains(first: $first) { name }
+			}`,
+			Variables: map[string]interface{}{"id": "eth", "first": json.Number("5")},
+		}, graphql.Limits{}))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(response)).To(MatchJSON(`{"data": {"domain": {}, "domains": [{"name": "eth"}, {"name": "xyz"}]}}`))
+		Expect(query.arguments["first"]).To(Equal(int64(5)))
+	})
+
+	It("Returns null for fields that fail to resolve, with the path of the error", func() {
+		response := execute(graphql.Request{Query: `{ broken domain { name } domains { name unknown } }`})
+
+		Expect(response).To(MatchJSON(`{
+			"data": {"broken": null, "domain": null, "domains": [{"name": "eth", "unknown": null}, {"name": "xyz", "unknown": null}]},
+			"errors": [
+				{"message": "broken field", "path": ["broken"]},
+				{"message": "unknown field unknown", "path": ["domains", 0, "unknown"]},
+				{"message": "unknown field unknown", "path": ["domains", 1, "unknown"]}
+			]
+		}`))
+	})
+
+	It("Requires selections on objects and rejects them on scalars", func() {
+		response := execute(graphql.Request{Query: `{ domain(id: "eth") count { name } }`})
+
+		Expect(response).To(MatchJSON(`{
+			"data": {"domain": null, "count": null},
+			"errors": [
+				{"message": "field domain of type Domain must have a selection of subfields", "path": ["domain"]},
+				{"message": "field count is a scalar and cannot have a selection of subfields", "path": ["count"]}
+			]
+		}`))
+	})
+
+	It("Rejects operations nested deeper than the limit, following fragments", func() {
+		limits := graphql.Limits{MaxDepth: 1}
+		request := graphql.Request{Query: `
+			{ domain(id: "eth") { ...Parent } }
+			fragment Parent on Domain { parent { name } }
+		`}
+		response, err := json.Marshal(graphql.Execute(&fakeQuery{}, request, limits))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(response)).To(MatchJSON(`{"errors": [{"message": "query depth 2 exceeds the maximum of 1"}]}`))
+	})
+
+	It("Stops resolving fields with subfields once the limit is reached", func() {
+		limits := graphql.Limits{MaxObjectFields: 2}
+		response, err := json.Marshal(graphql.Execute(&fakeQuery{}, graphql.Request{Query: `{ domains { parent { name } } }`}, limits))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(response)).To(MatchJSON(`{
+			"data": {"domains": [{"parent": {"name": "root"}}, {"parent": null}]},
+			"errors": [{"message": "query resolves more than the maximum of 2 fields with subfields", "path": ["domains", 1, "parent"]}]
+		}`))
+	})
+
+	It("Returns request errors without data", func() {
+		Expect(execute(graphql.Request{Query: "{ domains "})).To(MatchJSON(`{"errors": [{"message": "syntax error at 1:11: unexpected <EOF>"}]}`))
+		Expect(execute(graphql.Request{Query: "query A { count } query B { count }"})).To(MatchJSON(
+			`{"errors": [{"message": "operationName is required when the document contains more than one operation"}]}`))
+		Expect(execute(graphql.Request{Query: "query A { count }", OperationName: "B"})).To(MatchJSON(`{"errors": [{"message": "unknown operation \"B\""}]}`))
+		Expect(execute(graphql.Request{Query: "mutation { count }"})).To(MatchJSON(`{"errors": [{"message": "mutation operations are not supported"}]}`))
+		Expect(execute(graphql.Request{Query: "query ($id: String!) { domain(id: $id) { name } }"})).To(MatchJSON(
+			`{"errors": [{"message": "variable $id of required type String! was not provided"}]}`))
+	})
+})
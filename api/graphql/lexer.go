@@ -0,0 +1,220 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "<EOF>"
+	}
+	if t.kind == tokenString {
+		return fmt.Sprintf("%q", t.value)
+	}
+	return t.value
+}
+
+type lexer struct {
+	source string
+	pos    int
+}
+
+// Returns the next token, skipping whitespace, commas and comments
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.pos >= len(l.source) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+	start := l.pos
+	c := l.source[l.pos]
+	switch {
+	case strings.IndexByte("!$()...:=@[]{}|&", c) >= 0:
+		if c == '.' {
+			if !strings.HasPrefix(l.source[l.pos:], "...") {
+				return token{}, l.errorf(start, "unexpected character %q", c)
+			}
+			l.pos += 3
+			return token{kind: tokenPunctuator, value: "...", pos: start}, nil
+		}
+		l.pos++
+		return token{kind: tokenPunctuator, value: string(c), pos: start}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.source) && (l.source[l.pos] == '_' || isLetter(l.source[l.pos]) || isDigit(l.source[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.source[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		return l.string()
+	}
+
+	return token{}, l.errorf(start, "unexpected character %q", c)
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.source) {
+		switch c := l.source[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' && l.source[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.source[l.pos:], "\ufeff"):
+			l.pos += len("\ufeff")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.source[l.pos] == '-' {
+		l.pos++
+	}
+	if !l.digits() {
+		return token{}, l.errorf(start, "invalid number")
+	}
+	if l.pos < len(l.source) && l.source[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		if !l.digits() {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+	if l.pos < len(l.source) && (l.source[l.pos] == 'e' || l.source[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.source) && (l.source[l.pos] == '+' || l.source[l.pos] == '-') {
+			l.pos++
+		}
+		if !l.digits() {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+
+	return token{kind: kind, value: l.source[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) digits() bool {
+	start := l.pos
+	for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+		l.pos++
+	}
+	return l.pos > start
+}
+
+// Reads a quoted string, decoding its escape sequences; block strings are not supported
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	l.pos++
+	var value strings.Builder
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: tokenString, value: value.String(), pos: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, l.errorf(start, "unterminated string")
+		case c == '\\':
+			if l.pos+1 >= len(l.source) {
+				return token{}, l.errorf(start, "unterminated string")
+			}
+			escaped := l.source[l.pos+1]
+			l.pos += 2
+			switch escaped {
+			case '"', '\\', '/':
+				value.WriteByte(escaped)
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.source) {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(l.source[l.pos:l.pos+4], 16, 16)
+				if err != nil {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+				value.WriteRune(rune(r))
+				l.pos += 4
+			default:
+				return token{}, l.errorf(start, "invalid escape sequence \\%c", escaped)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.source[l.pos:])
+			value.WriteRune(r)
+			l.pos += size
+		}
+	}
+
+	return token{}, l.errorf(start, "unterminated string")
+}
+
+func (l *lexer) errorf(pos int, format string, args ...interface{}) error {
+	line, column := 1, 1
+	for _, c := range l.source[:pos] {
+		if c == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return fmt.Errorf("syntax error at %d:%d: %s", line, column, fmt.Sprintf(format, args...))
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
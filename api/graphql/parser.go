@@ -0,0 +1,467 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type parser struct {
+	lexer *lexer
+	token token
+}
+
+// Parses a query document into its operations and fragments
+func Parse(source string) (*Document, error) {
+	p := &parser{lexer: &lexer{source: source}}
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+
+	document := &Document{Fragments: map[string]*Fragment{}}
+	for p.token.kind != tokenEOF {
+		switch {
+		case p.peek("{"):
+			selectionSet, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			document.Operations = append(document.Operations, &Operation{Type: "query", SelectionSet: selectionSet})
+		case p.peekName("query", "mutation", "subscription"):
+			operation, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			document.Operations = append(document.Operations, operation)
+		case p.peekName("fragment"):
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := document.Fragments[fragment.Name]; exists {
+				return nil, fmt.Errorf("fragment %q is defined more than once", fragment.Name)
+			}
+			document.Fragments[fragment.Name] = fragment
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(document.Operations) == 0 {
+		return nil, fmt.Errorf("document does not contain an operation")
+	}
+
+	return document, nil
+}
+
+func (p *parser) operation() (*Operation, error) {
+	operation := &Operation{Type: p.token.value}
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+	if p.token.kind == tokenName {
+		operation.Name = p.token.value
+		err = p.advance()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.peek("(") {
+		operation.Variables, err = p.variableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = p.directives()
+	if err != nil {
+		return nil, err
+	}
+	operation.SelectionSet, err = p.selectionSet()
+	return operation, err
+}
+
+func (p *parser) variableDefinitions() ([]*VariableDefinition, error) {
+	var definitions []*VariableDefinition
+	err := p.expect("(")
+	if err != nil {
+		return nil, err
+	}
+	for !p.peek(")") {
+		err = p.expect("$")
+		if err != nil {
+			return nil, err
+		}
+		definition := &VariableDefinition{}
+		definition.Name, err = p.name()
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(":")
+		if err != nil {
+			return nil, err
+		}
+		definition.Type, err = p.typeReference()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek("=") {
+			err = p.advance()
+			if err != nil {
+				return nil, err
+			}
+			definition.DefaultValue, err = p.value(true)
+			if err != nil {
+				return nil, err
+			}
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, p.expect(")")
+}
+
+// Reads a type such as [String!]! and returns it as written
+func (p *parser) typeReference() (string, error) {
+	var reference string
+	if p.peek("[") {
+		err := p.advance()
+		if err != nil {
+			return "", err
+		}
+		inner, err := p.typeReference()
+		if err != nil {
+			return "", err
+		}
+		err = p.expect("]")
+		if err != nil {
+			return "", err
+		}
+		reference = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		reference = name
+	}
+	if p.peek("!") {
+		reference += "!"
+		return reference, p.advance()
+	}
+
+	return reference, nil
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+	fragment := &Fragment{}
+	fragment.Name, err = p.name()
+	if err != nil {
+		return nil, err
+	}
+	if fragment.Name == "on" {
+		return nil, fmt.Errorf("fragment cannot be named \"on\"")
+	}
+	if !p.peekName("on") {
+		return nil, p.unexpected()
+	}
+	err = p.advance()
+	if err != nil {
+		return nil, err
+	}
+	fragment.TypeCondition, err = p.name()
+	if err != nil {
+		return nil, err
+	}
+	fragment.Directives, err = p.directives()
+	if err != nil {
+		return nil, err
+	}
+	fragment.SelectionSet, err = p.selectionSet()
+	return fragment, err
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	err := p.expect("{")
+	if err != nil {
+		return nil, err
+	}
+	var selections []Selection
+	for !p.peek("}") {
+		var selection Selection
+		if p.peek("...") {
+			selection, err = p.fragmentSelection()
+		} else {
+			selection, err = p.field()
+		}
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, p.unexpected()
+	}
+
+	return selections, p.expect("}")
+}
+
+func (p *parser) fragmentSelection() (Selection, error) {
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+	if p.token.kind == tokenName && p.token.value != "on" {
+		spread := &FragmentSpread{Name: p.token.value}
+		err = p.advance()
+		if err != nil {
+			return nil, err
+		}
+		spread.Directives, err = p.directives()
+		return spread, err
+	}
+
+	fragment := &InlineFragment{}
+	if p.peekName("on") {
+		err = p.advance()
+		if err != nil {
+			return nil, err
+		}
+		fragment.TypeCondition, err = p.name()
+		if err != nil {
+			return nil, err
+		}
+	}
+	fragment.Directives, err = p.directives()
+	if err != nil {
+		return nil, err
+	}
+	fragment.SelectionSet, err = p.selectionSet()
+	return fragment, err
+}
+
+func (p *parser) field() (*Field, error) {
+	field := &Field{}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek(":") {
+		err = p.advance()
+		if err != nil {
+			return nil, err
+		}
+		field.Alias = name
+		name, err = p.name()
+		if err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+	field.Arguments, err = p.arguments()
+	if err != nil {
+		return nil, err
+	}
+	field.Directives, err = p.directives()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		field.SelectionSet, err = p.selectionSet()
+	}
+
+	return field, err
+}
+
+func (p *parser) arguments() (map[string]Value, error) {
+	arguments := map[string]Value{}
+	if !p.peek("(") {
+		return arguments, nil
+	}
+	err := p.advance()
+	if err != nil {
+		return nil, err
+	}
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(":")
+		if err != nil {
+			return nil, err
+		}
+		arguments[name], err = p.value(false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return arguments, p.expect(")")
+}
+
+func (p *parser) directives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek("@") {
+		err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		directive := &Directive{}
+		directive.Name, err = p.name()
+		if err != nil {
+			return nil, err
+		}
+		directive.Arguments, err = p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, directive)
+	}
+
+	return directives, nil
+}
+
+// Reads an argument value; constant values such as variable defaults cannot refer to variables
+func (p *parser) value(constant bool) (Value, error) {
+	current := p.token
+	switch current.kind {
+	case tokenInt:
+		value, err := strconv.ParseInt(current.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %s", current.value)
+		}
+		return Literal{Value: value}, p.advance()
+	case tokenFloat:
+		value, err := strconv.ParseFloat(current.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %s", current.value)
+		}
+		return Literal{Value: value}, p.advance()
+	case tokenString:
+		return Literal{Value: current.value}, p.advance()
+	case tokenName:
+		switch current.value {
+		case "true":
+			return Literal{Value: true}, p.advance()
+		case "false":
+			return Literal{Value: false}, p.advance()
+		case "null":
+			return Literal{}, p.advance()
+		}
+		return Literal{Value: current.value}, p.advance()
+	}
+
+	switch current.value {
+	case "$":
+		if constant {
+			return nil, p.unexpected()
+		}
+		err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		return Variable(name), err
+	case "[":
+		err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		list := ListValue{}
+		for !p.peek("]") {
+			value, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, p.expect("]")
+	case "{":
+		err := p.advance()
+		if err != nil {
+			return nil, err
+		}
+		object := ObjectValue{}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			err = p.expect(":")
+			if err != nil {
+				return nil, err
+			}
+			object[name], err = p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return object, p.expect("}")
+	}
+
+	return nil, p.unexpected()
+}
+
+func (p *parser) name() (string, error) {
+	if p.token.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.token.value
+	return name, p.advance()
+}
+
+func (p *parser) expect(punctuator string) error {
+	if !p.peek(punctuator) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) peek(punctuator string) bool {
+	return p.token.kind == tokenPunctuator && p.token.value == punctuator
+}
+
+func (p *parser) peekName(names ...string) bool {
+	if p.token.kind != tokenName {
+		return false
+	}
+	for _, name := range names {
+		if p.token.value == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) advance() error {
+	next, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = next
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	return p.lexer.errorf(p.token.pos, "unexpected %s", p.token)
+}
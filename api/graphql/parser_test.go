@@ -0,0 +1,93 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graphql_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/api/graphql"
+)
+
+var _ = Describe("Parse", func() {
+	It("Parses shorthand queries with aliases, arguments and nested selections", func() {
+		document, err := graphql.Parse(`{
+			first: domains(first: 2, skip: 1.5, where: {name_in: ["eth", "xyz"], owner: null}, orderBy: name) {
+				id # the namehash
+				owner { id }
+			}
+		}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(len(document.Operations)).To(Equal(1))
+		Expect(document.Operations[0].Type).To(Equal("query"))
+		field := document.Operations[0].SelectionSet[0].(*graphql.Field)
+		Expect(field.ResponseKey()).To(Equal("first"))
+		Expect(field.Name).To(Equal("domains"))
+		Expect(field.Arguments["first"].Resolve(nil)).To(Equal(int64(2)))
+		Expect(field.Arguments["skip"].Resolve(nil)).To(Equal(1.5))
+		Expect(field.Arguments["orderBy"].Resolve(nil)).To(Equal("name"))
+		Expect(field.Arguments["where"].Resolve(nil)).To(Equal(map[string]interface{}{
+			"name_in": []interface{}{"eth", "xyz"},
+			"owner":   nil,
+		}))
+		Expect(len(field.SelectionSet)).To(Equal(2))
+		Expect(field.SelectionSet[1].(*graphql.Field).SelectionSet[0].(*graphql.Field).Name).To(Equal("id"))
+	})
+
+	It("Parses named operations with variables, fragments and directives", func() {
+		document, err := graphql.Parse(`
+			query Domains($first: Int = 10, $names: [String!]!) {
+				domains(first: $first, where: {name_in: $names}) { ...DomainFields @include(if: true) }
+			}
+			fragment DomainFields on Domain { name ... on Domain { labelName } }
+		`)
+		Expect(err).NotTo(HaveOccurred())
+
+		operation := document.Operations[0]
+		Expect(operation.Name).To(Equal("Domains"))
+		Expect(operation.Variables[0].Type).To(Equal("Int"))
+		Expect(operation.Variables[0].DefaultValue.Resolve(nil)).To(Equal(int64(10)))
+		Expect(operation.Variables[1].Type).To(Equal("[String!]!"))
+		field := operation.SelectionSet[0].(*graphql.Field)
+		Expect(field.Arguments["first"].Resolve(map[string]interface{}{"first": int64(3)})).To(Equal(int64(3)))
+		spread := field.SelectionSet[0].(*graphql.FragmentSpread)
+		Expect(spread.Name).To(Equal("DomainFields"))
+		Expect(spread.Directives[0].Name).To(Equal("include"))
+		Expect(document.Fragments["DomainFields"].TypeCondition).To(Equal("Domain"))
+		Expect(document.Fragments["DomainFields"].SelectionSet[1].(*graphql.InlineFragment).TypeCondition).To(Equal("Domain"))
+	})
+
+	It("Decodes string escapes", func() {
+		document, err := graphql.Parse(`{ domain(id: "a\"b\u00e9\n") { id } }`)
+		Expect(err).NotTo(HaveOccurred())
+
+		field := document.Operations[0].SelectionSet[0].(*graphql.Field)
+		Expect(field.Arguments["id"].Resolve(nil)).To(Equal("a\"bé\n"))
+	})
+
+	It("Reports the position of syntax errors", func() {
+		_, err := graphql.Parse("{\n  domains(first: ) { id }\n}")
+		Expect(err).To(MatchError("syntax error at 2:18: unexpected )"))
+
+		_, err = graphql.Parse(`{ domain(id: "unterminated) { id } }`)
+		Expect(err).To(MatchError("syntax error at 1:14: unterminated string"))
+
+		_, err = graphql.Parse("fragment F on Domain { id }")
+		Expect(err).To(MatchError("document does not contain an operation"))
+	})
+})
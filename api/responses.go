@@ -0,0 +1,118 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+)
+
+// The state of a node from a domain record
+type Record struct {
+	Name            string `json:"name"`
+	NameHash        string `json:"namehash"`
+	LabelHash       string `json:"labelhash"`
+	ParentHash      string `json:"parent"`
+	BlockNumber     int64  `json:"blockNumber"`
+	Owner           string `json:"owner"`
+	Resolver        string `json:"resolver"`
+	Addr            string `json:"addr"`
+	ResolvedName    string `json:"resolvedName"`
+	TTL             string `json:"ttl"`
+	Contenthash     string `json:"contenthash"`
+	ContentProtocol string `json:"contentProtocol"`
+	ContentURI      string `json:"contentURI"`
+	PubKeyX         string `json:"pubkeyX"`
+	PubKeyY         string `json:"pubkeyY"`
+	Migrated        bool   `json:"migrated"`
+}
+
+type NameResponse struct {
+	Record
+	Texts map[string]string `json:"texts"`
+	Coins map[string]string `json:"coins"`
+}
+
+type HistoryResponse struct {
+	NameHash string   `json:"namehash"`
+	History  []Record `json:"history"`
+}
+
+// A name owned by or resolving to an address, and the block it last changed at
+type Name struct {
+	Name        string `json:"name"`
+	NameHash    string `json:"namehash"`
+	Owner       string `json:"owner"`
+	Resolver    string `json:"resolver"`
+	Addr        string `json:"addr"`
+	BlockNumber int64  `json:"blockNumber"`
+}
+
+type AddressNamesResponse struct {
+	Address   string `json:"address"`
+	Owned     []Name `json:"owned"`
+	Resolving []Name `json:"resolving"`
+}
+
+type PrimaryNameResponse struct {
+	Address     string `json:"address"`
+	ReverseNode string `json:"reverseNode"`
+	Name        string `json:"name"`
+	Verified    bool   `json:"verified"`
+	BlockNumber int64  `json:"blockNumber"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// The content protocol and uri are decoded from whichever of the content, multihash and contenthash was set most recently
+func newRecord(record models.DomainModel) Record {
+	return Record{
+		Name:            record.FullName,
+		NameHash:        record.NameHash,
+		LabelHash:       record.LabelHash,
+		ParentHash:      record.ParentHash,
+		BlockNumber:     record.BlockNumber,
+		Owner:           record.Owner,
+		Resolver:        record.ResolverAddr,
+		Addr:            record.PointsToAddr,
+		ResolvedName:    record.Name,
+		TTL:             record.TTL,
+		Contenthash:     record.Contenthash,
+		ContentProtocol: record.EffectiveContentProtocol,
+		ContentURI:      record.EffectiveContentURI,
+		PubKeyX:         record.PubKeyX,
+		PubKeyY:         record.PubKeyY,
+		Migrated:        record.Migrated,
+	}
+}
+
+func newNames(snapshot []models.SnapshotModel) []Name {
+	names := make([]Name, 0, len(snapshot))
+	for _, node := range snapshot {
+		names = append(names, Name{
+			Name:        node.FullName,
+			NameHash:    node.NameHash,
+			Owner:       node.Owner,
+			Resolver:    node.ResolverAddr,
+			Addr:        node.PointsToAddr,
+			BlockNumber: node.BlockNumber,
+		})
+	}
+
+	return names
+}
@@ -0,0 +1,308 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package api serves read-only ENS resolution queries over HTTP/JSON, answered from the indexed tables rather than an eth node
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+// Every endpoint answers as of the latest synced header, or as of the block given by the optional ?block= parameter
+type Server struct {
+	db *postgres.DB
+	repository.ENSRepository
+	repository.SnapshotRepository
+	repository.ReverseRepository
+	repository.SubgraphRepository
+}
+
+func NewServer(db *postgres.DB) *Server {
+	return &Server{
+		db:                 db,
+		ENSRepository:      repository.NewENSRepository(db),
+		SnapshotRepository: repository.NewSnapshotRepository(db),
+		ReverseRepository:  repository.NewReverseRepository(db),
+		SubgraphRepository: repository.NewSubgraphRepository(db),
+	}
+}
+
+// Routes:
+// GET /v1/name/{name}               the record, text records and coin addresses of a name
+// GET /v1/node/{namehash}/history   every record of a node
+// GET /v1/address/{addr}/names      the names owned by and resolving to an address
+// GET /v1/address/{addr}/primary    the primary name of an address, and whether it is verified
+// GET|POST /graphql                 GraphQL queries shaped like the ENS subgraph's
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/name/", s.handleName)
+	mux.HandleFunc("/v1/node/", s.handleNode)
+	mux.HandleFunc("/v1/address/", s.handleAddress)
+	mux.HandleFunc("/graphql", s.handleGraphQL)
+
+	return mux
+}
+
+func (s *Server) handleName(w http.ResponseWriter, r *http.Request) {
+	name, ok := pathParams(w, r, "/v1/name/", "")
+	if !ok {
+		return
+	}
+	blockNumber, ok := s.blockParam(w, r)
+	if !ok {
+		return
+	}
+	name = utils.NormalizeName(name)
+	node := utils.NameHash(name)
+	record, err := s.GetRecord(node, blockNumber)
+	if err == sql.ErrNoRows || (err == nil && record.BlockNumber == 0) {
+		writeError(w, http.StatusNotFound, "name not found: "+name)
+		return
+	}
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	textRecords, err := s.GetTextRecords(node, blockNumber)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	coinAddresses, err := s.GetCoinAddresses(node, blockNumber)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	response := NameResponse{
+		Record: newRecord(*record),
+		Texts:  map[string]string{},
+		Coins:  map[string]string{},
+	}
+	for _, textRecord := range textRecords {
+		if textRecord.Value != "" {
+			response.Texts[textRecord.Key] = textRecord.Value
+		}
+	}
+	for _, coinAddress := range coinAddresses {
+		if coinAddress.Address != "" {
+			response.Coins[coinAddress.CoinType] = coinAddress.Address
+		}
+	}
+	writeJSON(w, response)
+}
+
+func (s *Server) handleNode(w http.ResponseWriter, r *http.Request) {
+	node, ok := pathParams(w, r, "/v1/node/", "/history")
+	if !ok {
+		return
+	}
+	blockNumber, ok := s.blockParam(w, r)
+	if !ok {
+		return
+	}
+	node = strings.ToLower(node)
+	records, err := s.GetRecordHistory(node, blockNumber)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	if len(records) == 0 {
+		writeError(w, http.StatusNotFound, "node not found: "+node)
+		return
+	}
+
+	response := HistoryResponse{
+		NameHash: node,
+		History:  make([]Record, 0, len(records)),
+	}
+	for _, record := range records {
+		response.History = append(response.History, newRecord(record))
+	}
+	writeJSON(w, response)
+}
+
+func (s *Server) handleAddress(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/names") {
+		s.handleAddressNames(w, r)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/primary") {
+		s.handlePrimaryName(w, r)
+		return
+	}
+	writeError(w, http.StatusNotFound, "not found: "+r.URL.Path)
+}
+
+// Names are taken from ens.current_domains unless a block is given, in which case they come from the address's snapshot at that block
+// Both lists come from a single query for the nodes owned by or resolving to the address
+func (s *Server) handleAddressNames(w http.ResponseWriter, r *http.Request) {
+	address, ok := pathParams(w, r, "/v1/address/", "/names")
+	if !ok {
+		return
+	}
+	if !validAddress(w, address) {
+		return
+	}
+	snapshot := s.GetCurrentAddressSnapshot
+	if r.URL.Query().Get("block") != "" {
+		blockNumber, ok := s.blockParam(w, r)
+		if !ok {
+			return
+		}
+		snapshot = func(address string) ([]models.SnapshotModel, error) {
+			return s.GetAddressSnapshot(address, blockNumber)
+		}
+	}
+	nodes, err := snapshot(address)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	var owned, resolving []models.SnapshotModel
+	for _, node := range nodes {
+		if strings.EqualFold(node.Owner, address) {
+			owned = append(owned, node)
+		}
+		if strings.EqualFold(node.PointsToAddr, address) {
+			resolving = append(resolving, node)
+		}
+	}
+
+	writeJSON(w, AddressNamesResponse{
+		Address:   address,
+		Owned:     newNames(owned),
+		Resolving: newNames(resolving),
+	})
+}
+
+func (s *Server) handlePrimaryName(w http.ResponseWriter, r *http.Request) {
+	address, ok := pathParams(w, r, "/v1/address/", "/primary")
+	if !ok {
+		return
+	}
+	if !validAddress(w, address) {
+		return
+	}
+	blockNumber, ok := s.blockParam(w, r)
+	if !ok {
+		return
+	}
+	primaryName, err := s.GetPrimaryName(address, blockNumber)
+	if err == sql.ErrNoRows {
+		writeError(w, http.StatusNotFound, "no reverse record for address: "+address)
+		return
+	}
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	writeJSON(w, PrimaryNameResponse{
+		Address:     address,
+		ReverseNode: primaryName.Node,
+		Name:        primaryName.Name,
+		Verified:    primaryName.Verified,
+		BlockNumber: primaryName.BlockNumber,
+	})
+}
+
+// Strips the prefix and suffix from the request path to get its single path parameter
+// Writes an error response and returns false if the request isn't a GET or the parameter is missing
+func pathParams(w http.ResponseWriter, r *http.Request, prefix, suffix string) (string, bool) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed: "+r.Method)
+		return "", false
+	}
+	if !strings.HasSuffix(r.URL.Path, suffix) {
+		writeError(w, http.StatusNotFound, "not found: "+r.URL.Path)
+		return "", false
+	}
+	param := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), suffix)
+	if param == "" || strings.Contains(param, "/") {
+		writeError(w, http.StatusNotFound, "not found: "+r.URL.Path)
+		return "", false
+	}
+
+	return param, true
+}
+
+// Parses the ?block= parameter, defaulting to the latest synced header
+// Writes an error response and returns false if it is not a non-negative block number
+func (s *Server) blockParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	param := r.URL.Query().Get("block")
+	if param == "" {
+		var latest int64
+		err := s.db.Get(&latest, `SELECT COALESCE(MAX(block_number), 0) FROM public.headers`)
+		if err != nil {
+			writeServerError(w, err)
+			return 0, false
+		}
+		return latest, true
+	}
+	blockNumber, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || blockNumber < 0 {
+		writeError(w, http.StatusBadRequest, "invalid block: "+param)
+		return 0, false
+	}
+
+	return blockNumber, true
+}
+
+func validAddress(w http.ResponseWriter, address string) bool {
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, "invalid address: "+address)
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		log.Error("failed to write response ", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+	if err != nil {
+		log.Error("failed to write response ", err)
+	}
+}
+
+// Database errors are logged rather than returned to the client
+func writeServerError(w http.ResponseWriter, err error) {
+	log.Error("failed to answer request ", err)
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
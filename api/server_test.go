@@ -0,0 +1,221 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/api"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+var _ = Describe("Server", func() {
+	var db *postgres.DB
+	var handler http.Handler
+	address := "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"
+	ethNode := utils.NameHash("eth")
+	vitalikNode := utils.NameHash("vitalik.eth")
+	reverseNode := utils.ReverseNodeOf(address)
+	ethRecord := models.DomainModel{
+		NameHash:    ethNode,
+		BlockNumber: 3327420,
+		LabelHash:   utils.LabelHash("eth"),
+		ParentHash:  utils.RootNode,
+		Owner:       address,
+		FullName:    "eth",
+	}
+	vitalikRecord := models.DomainModel{
+		NameHash:     vitalikNode,
+		BlockNumber:  3327421,
+		LabelHash:    utils.LabelHash("vitalik"),
+		ParentHash:   ethNode,
+		Owner:        address,
+		ResolverAddr: "0x5FfC014343cd971B7eb70732021E26C35B744cc4",
+		FullName:     "vitalik.eth",
+	}
+	var pointedVitalikRecord models.DomainModel
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		headerIds := test_helpers.CreateHeaders(db, 3327420, 3327423)
+		ensRepo := repository.NewENSRepository(db)
+		ethRecord.HeaderID = headerIds[3327420]
+		vitalikRecord.HeaderID = headerIds[3327421]
+		pointedVitalikRecord = vitalikRecord
+		pointedVitalikRecord.BlockNumber = 3327422
+		pointedVitalikRecord.HeaderID = headerIds[3327422]
+		pointedVitalikRecord.PointsToAddr = address
+		for _, record := range []models.DomainModel{ethRecord, vitalikRecord, pointedVitalikRecord} {
+			err := ensRepo.CreateRecord(record)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		err := ensRepo.CreateTextRecord(models.TextRecordModel{
+			BlockNumber:  3327422,
+			HeaderID:     headerIds[3327422],
+			NameHash:     vitalikNode,
+			Key:          "url",
+			Value:        "https://vitalik.ca",
+			ResolverAddr: vitalikRecord.ResolverAddr,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		err = repository.NewReverseRepository(db).CreateReverseRecord(models.ReverseRecordModel{
+			HeaderID: headerIds[3327422],
+			Address:  address,
+			Node:     reverseNode,
+			Source:   models.ReverseSourceRegistrar,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		err = ensRepo.CreateRecord(models.DomainModel{
+			NameHash:    reverseNode,
+			BlockNumber: 3327423,
+			HeaderID:    headerIds[3327423],
+			LabelHash:   utils.LabelHash(utils.ReverseLabel(address)),
+			ParentHash:  utils.ReverseNode,
+			Owner:       address,
+			Name:        "vitalik.eth",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		handler = api.NewServer(db).Handler()
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	get := func(path string, response interface{}) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		err := json.Unmarshal(recorder.Body.Bytes(), response)
+		Expect(err).NotTo(HaveOccurred())
+
+		return recorder.Code
+	}
+
+	Describe("GET /v1/name/{name}", func() {
+		It("Returns the latest record of the name with its text records", func() {
+			var response api.NameResponse
+			status := get("/v1/name/Vitalik.eth", &response)
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response.NameHash).To(Equal(vitalikNode))
+			Expect(response.Name).To(Equal("vitalik.eth"))
+			Expect(response.Addr).To(Equal(address))
+			Expect(response.BlockNumber).To(Equal(int64(3327422)))
+			Expect(response.Texts).To(Equal(map[string]string{"url": "https://vitalik.ca"}))
+			Expect(response.Coins).To(Equal(map[string]string{}))
+		})
+
+		It("Returns the record of the name as of the given block", func() {
+			var response api.NameResponse
+			status := get("/v1/name/vitalik.eth?block=3327421", &response)
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response.Addr).To(Equal(""))
+			Expect(response.BlockNumber).To(Equal(int64(3327421)))
+			Expect(response.Texts).To(Equal(map[string]string{}))
+		})
+
+		It("Returns not found for names that did not exist at the block", func() {
+			var response api.ErrorResponse
+			Expect(get("/v1/name/vitalik.eth?block=3327420", &response)).To(Equal(http.StatusNotFound))
+			Expect(get("/v1/name/unknown.eth", &response)).To(Equal(http.StatusNotFound))
+		})
+
+		It("Rejects invalid blocks", func() {
+			var response api.ErrorResponse
+			status := get("/v1/name/vitalik.eth?block=latest", &response)
+
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(response.Error).To(Equal("invalid block: latest"))
+		})
+	})
+
+	Describe("GET /v1/node/{namehash}/history", func() {
+		It("Returns every record of the node up to the block, oldest first", func() {
+			var response api.HistoryResponse
+			status := get("/v1/node/"+vitalikNode+"/history", &response)
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(len(response.History)).To(Equal(2))
+			Expect(response.History[0].BlockNumber).To(Equal(int64(3327421)))
+			Expect(response.History[1].Addr).To(Equal(address))
+
+			status = get("/v1/node/"+vitalikNode+"/history?block=3327421", &response)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(len(response.History)).To(Equal(1))
+		})
+
+		It("Returns not found for unknown nodes", func() {
+			var response api.ErrorResponse
+			Expect(get("/v1/node/0x1234/history", &response)).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("GET /v1/address/{addr}/names", func() {
+		It("Returns the names owned by and resolving to the address", func() {
+			var response api.AddressNamesResponse
+			status := get("/v1/address/"+address+"/names", &response)
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(len(response.Owned)).To(Equal(3))
+			Expect(len(response.Resolving)).To(Equal(1))
+			Expect(response.Resolving[0].Name).To(Equal("vitalik.eth"))
+		})
+
+		It("Returns the names as of the given block", func() {
+			var response api.AddressNamesResponse
+			status := get("/v1/address/"+address+"/names?block=3327421", &response)
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(len(response.Owned)).To(Equal(2))
+			Expect(len(response.Resolving)).To(Equal(0))
+		})
+
+		It("Rejects invalid addresses", func() {
+			var response api.ErrorResponse
+			Expect(get("/v1/address/vitalik/names", &response)).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("GET /v1/address/{addr}/primary", func() {
+		It("Returns the verified primary name of the address", func() {
+			var response api.PrimaryNameResponse
+			status := get("/v1/address/"+address+"/primary", &response)
+
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response.ReverseNode).To(Equal(reverseNode))
+			Expect(response.Name).To(Equal("vitalik.eth"))
+			Expect(response.Verified).To(BeTrue())
+		})
+
+		It("Returns not found before the address claimed its reverse node", func() {
+			var response api.ErrorResponse
+			Expect(get("/v1/address/"+address+"/primary?block=3327421", &response)).To(Equal(http.StatusNotFound))
+		})
+	})
+})
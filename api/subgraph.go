@@ -0,0 +1,582 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/ens_transformers/api/graphql"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+// List fields return the first 100 entities unless asked for more, and at most 1000, like the subgraph's
+const (
+	defaultFirst = 100
+	maxFirst     = 1000
+	maxQuerySize = 1 << 20
+	zeroAddress  = "0x0000000000000000000000000000000000000000"
+)
+
+// Every entity and list field nested in an entity is read with its own query as of the entity's block, so the nesting of a
+// query and the number of those fields it resolves are capped
+var subgraphLimits = graphql.Limits{
+	MaxDepth:        8,
+	MaxObjectFields: 2000,
+}
+
+// Answers GraphQL queries shaped like the ENS subgraph's, either POSTed as JSON or given by the query, operationName and
+// variables parameters of a GET
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var request graphql.Request
+	switch r.Method {
+	case http.MethodGet:
+		request.Query = r.URL.Query().Get("query")
+		request.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			decoder := json.NewDecoder(strings.NewReader(variables))
+			decoder.UseNumber()
+			err := decoder.Decode(&request.Variables)
+			if err != nil {
+				writeGraphQLError(w, http.StatusBadRequest, "invalid variables: "+err.Error())
+				return
+			}
+		}
+	case http.MethodPost:
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxQuerySize))
+		decoder.UseNumber()
+		err := decoder.Decode(&request)
+		if err != nil {
+			writeGraphQLError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	default:
+		writeGraphQLError(w, http.StatusMethodNotAllowed, "method not allowed: "+r.Method)
+		return
+	}
+	if request.Query == "" {
+		writeGraphQLError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	writeJSON(w, graphql.Execute(&subgraphQuery{s: s}, request, subgraphLimits))
+}
+
+func writeGraphQLError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(graphql.Response{Errors: []graphql.Error{{Message: message}}})
+	if err != nil {
+		log.Error("failed to write response ", err)
+	}
+}
+
+// Database errors are logged rather than returned to the client, while invalid filters and orderings are returned as they are
+func resolveError(err error) error {
+	if _, ok := err.(repository.QueryError); ok {
+		return err
+	}
+	log.Error("failed to resolve field ", err)
+	return errors.New("internal error")
+}
+
+// The root of the schema; every field takes an optional block argument and the entities it returns, and the entities
+// reachable from them, are read as of that block
+type subgraphQuery struct {
+	s *Server
+}
+
+type entityList func(s *Server, query models.EntityQuery) ([]graphql.Object, error)
+
+var entityLists = map[string]entityList{
+	"domains":       (*Server).domains,
+	"accounts":      (*Server).accounts,
+	"resolvers":     (*Server).resolvers,
+	"registrations": (*Server).registrations,
+	"domainEvents":  domainEvents(""),
+	"transfers":     domainEvents(models.DomainEventTransfer),
+	"newOwners":     domainEvents(models.DomainEventNewOwner),
+	"newResolvers":  domainEvents(models.DomainEventNewResolver),
+	"newTTLs":       domainEvents(models.DomainEventNewTTL),
+}
+
+// Fields that look up a single entity by its id, and the list they look it up in
+var entityLookups = map[string]string{
+	"domain":       "domains",
+	"account":      "accounts",
+	"resolver":     "resolvers",
+	"registration": "registrations",
+	"domainEvent":  "domainEvents",
+	"transfer":     "transfers",
+	"newOwner":     "newOwners",
+	"newResolver":  "newResolvers",
+	"newTTL":       "newTTLs",
+}
+
+func (q *subgraphQuery) TypeName() string {
+	return "Query"
+}
+
+func (q *subgraphQuery) Resolve(field string, arguments map[string]interface{}) (interface{}, error) {
+	if list, ok := entityLists[field]; ok {
+		blockNumber, err := q.s.blockArgument(arguments)
+		if err != nil {
+			return nil, err
+		}
+		query, err := entityQuery(arguments, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return list(q.s, query)
+	}
+	if listField, ok := entityLookups[field]; ok {
+		blockNumber, err := q.s.blockArgument(arguments)
+		if err != nil {
+			return nil, err
+		}
+		id, ok := arguments["id"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s requires a string id", field)
+		}
+		return lookup(q.s, entityLists[listField], id, blockNumber)
+	}
+
+	return nil, fmt.Errorf("unknown field %s on Query", field)
+}
+
+// Reads the block argument, {number: N}, {hash: "0x..."} or {number_gte: N}, returning 0 for the latest synced header
+func (s *Server) blockArgument(arguments map[string]interface{}) (int64, error) {
+	argument, ok := arguments["block"]
+	if !ok || argument == nil {
+		return 0, nil
+	}
+	block, ok := argument.(map[string]interface{})
+	if !ok || len(block) != 1 {
+		return 0, errors.New("block must be one of {number: N}, {hash: \"0x...\"} or {number_gte: N}")
+	}
+	if hash, ok := block["hash"]; ok {
+		hashString, _ := hash.(string)
+		var blockNumber int64
+		err := s.db.Get(&blockNumber, `SELECT block_number FROM public.headers WHERE hash = $1`, hashString)
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("block %v has not been indexed", hash)
+		}
+		if err != nil {
+			return 0, resolveError(err)
+		}
+		return blockNumber, nil
+	}
+	var latest int64
+	err := s.db.Get(&latest, `SELECT COALESCE(MAX(block_number), 0) FROM public.headers`)
+	if err != nil {
+		return 0, resolveError(err)
+	}
+	if number, ok := block["number_gte"]; ok {
+		minimum, ok := number.(int64)
+		if !ok {
+			return 0, errors.New("block number_gte must be an integer")
+		}
+		if minimum > latest {
+			return 0, fmt.Errorf("block %d has not been indexed, the latest indexed block is %d", minimum, latest)
+		}
+		return 0, nil
+	}
+	number, ok := block["number"].(int64)
+	if !ok || number < 1 {
+		return 0, errors.New("block number must be a positive integer")
+	}
+	if number > latest {
+		return 0, fmt.Errorf("block %d has not been indexed, the latest indexed block is %d", number, latest)
+	}
+
+	return number, nil
+}
+
+// Reads the where, orderBy, orderDirection, first and skip arguments of a list field
+func entityQuery(arguments map[string]interface{}, blockNumber int64) (models.EntityQuery, error) {
+	query := models.EntityQuery{
+		First:       defaultFirst,
+		BlockNumber: blockNumber,
+	}
+	if where, ok := arguments["where"]; ok && where != nil {
+		query.Where, ok = where.(map[string]interface{})
+		if !ok {
+			return query, errors.New("where must be an object")
+		}
+	}
+	if orderBy, ok := arguments["orderBy"]; ok && orderBy != nil {
+		query.OrderBy, ok = orderBy.(string)
+		if !ok {
+			return query, errors.New("orderBy must be a field name")
+		}
+	}
+	if orderDirection, ok := arguments["orderDirection"]; ok && orderDirection != nil {
+		query.OrderDirection, ok = orderDirection.(string)
+		if !ok {
+			return query, errors.New("orderDirection must be asc or desc")
+		}
+	}
+	if first, ok := arguments["first"]; ok && first != nil {
+		n, ok := first.(int64)
+		if !ok || n < 0 || n > maxFirst {
+			return query, fmt.Errorf("first must be an integer between 0 and %d", maxFirst)
+		}
+		query.First = int(n)
+	}
+	if skip, ok := arguments["skip"]; ok && skip != nil {
+		n, ok := skip.(int64)
+		if !ok || n < 0 {
+			return query, errors.New("skip must be a non-negative integer")
+		}
+		query.Skip = int(n)
+	}
+
+	return query, nil
+}
+
+// Reads the arguments of a list field nested in an entity, which lists the entities related to it as of the entity's block
+func relatedQuery(arguments map[string]interface{}, blockNumber int64, field string, id string) (models.EntityQuery, error) {
+	query, err := entityQuery(arguments, blockNumber)
+	if err != nil {
+		return query, err
+	}
+	where := map[string]interface{}{field: id}
+	for key, value := range query.Where {
+		if key != field {
+			where[key] = value
+		}
+	}
+	query.Where = where
+
+	return query, nil
+}
+
+func lookup(s *Server, list entityList, id string, blockNumber int64) (interface{}, error) {
+	entities, err := list(s, models.EntityQuery{
+		Where:       map[string]interface{}{"id": id},
+		First:       1,
+		BlockNumber: blockNumber,
+	})
+	if err != nil || len(entities) == 0 {
+		return nil, err
+	}
+
+	return entities[0], nil
+}
+
+func nullable(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (s *Server) account(address string, blockNumber int64) interface{} {
+	if address == "" || address == zeroAddress {
+		return nil
+	}
+	return &accountObject{s: s, id: strings.ToLower(address), blockNumber: blockNumber}
+}
+
+func (s *Server) domains(query models.EntityQuery) ([]graphql.Object, error) {
+	domains, err := s.GetDomains(query)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	objects := make([]graphql.Object, 0, len(domains))
+	for _, domain := range domains {
+		objects = append(objects, &domainObject{s: s, model: domain, blockNumber: query.BlockNumber})
+	}
+
+	return objects, nil
+}
+
+func (s *Server) accounts(query models.EntityQuery) ([]graphql.Object, error) {
+	accounts, err := s.GetAccounts(query)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	objects := make([]graphql.Object, 0, len(accounts))
+	for _, account := range accounts {
+		objects = append(objects, &accountObject{s: s, id: account.Address, blockNumber: query.BlockNumber})
+	}
+
+	return objects, nil
+}
+
+func (s *Server) resolvers(query models.EntityQuery) ([]graphql.Object, error) {
+	resolvers, err := s.GetResolvers(query)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	objects := make([]graphql.Object, 0, len(resolvers))
+	for _, resolver := range resolvers {
+		objects = append(objects, &resolverObject{s: s, model: resolver, blockNumber: query.BlockNumber})
+	}
+
+	return objects, nil
+}
+
+func (s *Server) registrations(query models.EntityQuery) ([]graphql.Object, error) {
+	registrations, err := s.GetRegistrations(query)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	objects := make([]graphql.Object, 0, len(registrations))
+	for _, registration := range registrations {
+		objects = append(objects, &registrationObject{s: s, model: registration, blockNumber: query.BlockNumber})
+	}
+
+	return objects, nil
+}
+
+func domainEvents(eventType string) entityList {
+	return func(s *Server, query models.EntityQuery) ([]graphql.Object, error) {
+		events, err := s.GetDomainEvents(eventType, query)
+		if err != nil {
+			return nil, resolveError(err)
+		}
+		objects := make([]graphql.Object, 0, len(events))
+		for _, event := range events {
+			objects = append(objects, &domainEventObject{s: s, model: event, blockNumber: query.BlockNumber})
+		}
+
+		return objects, nil
+	}
+}
+
+type domainObject struct {
+	s           *Server
+	model       models.SubgraphDomainModel
+	blockNumber int64
+}
+
+func (d *domainObject) TypeName() string {
+	return "Domain"
+}
+
+func (d *domainObject) Resolve(field string, arguments map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "id":
+		return d.model.NameHash, nil
+	case "name":
+		return nullable(d.model.FullName), nil
+	case "labelName":
+		return nullable(d.model.Label), nil
+	case "labelhash":
+		return d.model.LabelHash, nil
+	case "parent":
+		return lookup(d.s, (*Server).domains, d.model.ParentHash, d.blockNumber)
+	case "subdomains":
+		query, err := relatedQuery(arguments, d.blockNumber, "parent", d.model.NameHash)
+		if err != nil {
+			return nil, err
+		}
+		return d.s.domains(query)
+	case "subdomainCount":
+		count, err := d.s.CountDomains(models.EntityQuery{
+			Where:       map[string]interface{}{"parent": d.model.NameHash},
+			BlockNumber: d.blockNumber,
+		})
+		if err != nil {
+			return nil, resolveError(err)
+		}
+		return count, nil
+	case "resolvedAddress":
+		return d.s.account(d.model.PointsToAddr, d.blockNumber), nil
+	case "owner":
+		return d.s.account(d.model.Owner, d.blockNumber), nil
+	case "resolver":
+		if d.model.ResolverAddr == "" || d.model.ResolverAddr == zeroAddress {
+			return nil, nil
+		}
+		return lookup(d.s, (*Server).resolvers, resolverID(d.model.ResolverAddr, d.model.NameHash), d.blockNumber)
+	case "ttl":
+		return nullable(d.model.TTL), nil
+	case "isMigrated":
+		return d.model.Migrated, nil
+	case "events":
+		query, err := relatedQuery(arguments, d.blockNumber, "domain", d.model.NameHash)
+		if err != nil {
+			return nil, err
+		}
+		return domainEvents("")(d.s, query)
+	}
+
+	return nil, fmt.Errorf("unknown field %s on Domain", field)
+}
+
+// Resolvers are identified by their lower cased address and the node they were set on, like the subgraph's
+func resolverID(resolverAddr, node string) string {
+	return strings.ToLower(resolverAddr) + "-" + node
+}
+
+type accountObject struct {
+	s           *Server
+	id          string
+	blockNumber int64
+}
+
+func (a *accountObject) TypeName() string {
+	return "Account"
+}
+
+func (a *accountObject) Resolve(field string, arguments map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "id":
+		return a.id, nil
+	case "domains":
+		query, err := relatedQuery(arguments, a.blockNumber, "owner", a.id)
+		if err != nil {
+			return nil, err
+		}
+		return a.s.domains(query)
+	case "registrations":
+		query, err := relatedQuery(arguments, a.blockNumber, "registrant", a.id)
+		if err != nil {
+			return nil, err
+		}
+		return a.s.registrations(query)
+	}
+
+	return nil, fmt.Errorf("unknown field %s on Account", field)
+}
+
+type resolverObject struct {
+	s           *Server
+	model       models.SubgraphResolverModel
+	blockNumber int64
+}
+
+func (r *resolverObject) TypeName() string {
+	return "Resolver"
+}
+
+func (r *resolverObject) Resolve(field string, arguments map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "id":
+		return r.model.ID, nil
+	case "address":
+		return r.model.Address, nil
+	case "domain":
+		return lookup(r.s, (*Server).domains, r.model.NameHash, r.blockNumber)
+	case "addr":
+		return r.s.account(r.model.PointsToAddr, r.blockNumber), nil
+	case "contentHash":
+		return nullable(r.model.Contenthash), nil
+	case "texts":
+		keys, err := r.s.GetResolverTextKeys(r.model.Address, r.model.NameHash, r.blockNumber)
+		if err != nil {
+			return nil, resolveError(err)
+		}
+		if len(keys) == 0 {
+			return nil, nil
+		}
+		return keys, nil
+	case "coinTypes":
+		coinTypes, err := r.s.GetResolverCoinTypes(r.model.Address, r.model.NameHash, r.blockNumber)
+		if err != nil {
+			return nil, resolveError(err)
+		}
+		if len(coinTypes) == 0 {
+			return nil, nil
+		}
+		return coinTypes, nil
+	}
+
+	return nil, fmt.Errorf("unknown field %s on Resolver", field)
+}
+
+type registrationObject struct {
+	s           *Server
+	model       models.SubgraphRegistrationModel
+	blockNumber int64
+}
+
+func (r *registrationObject) TypeName() string {
+	return "Registration"
+}
+
+func (r *registrationObject) Resolve(field string, arguments map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "id":
+		return r.model.LabelHash, nil
+	case "domain":
+		return lookup(r.s, (*Server).domains, utils.CreateSubnode(utils.NameHash("eth"), r.model.LabelHash), r.blockNumber)
+	case "registrationDate":
+		return nullable(r.model.RegisteredAt), nil
+	case "expiryDate":
+		return nullable(r.model.Expires), nil
+	case "registrant":
+		return r.s.account(r.model.Registrant, r.blockNumber), nil
+	case "labelName":
+		return nullable(r.model.Label), nil
+	}
+
+	return nil, fmt.Errorf("unknown field %s on Registration", field)
+}
+
+// Transfer, NewOwner, NewResolver and NewTTL events, which all implement the DomainEvent interface
+type domainEventObject struct {
+	s           *Server
+	model       models.SubgraphDomainEventModel
+	blockNumber int64
+}
+
+func (e *domainEventObject) TypeName() string {
+	return e.model.Type
+}
+
+func (e *domainEventObject) Interfaces() []string {
+	return []string{"DomainEvent"}
+}
+
+func (e *domainEventObject) Resolve(field string, arguments map[string]interface{}) (interface{}, error) {
+	switch field {
+	case "id":
+		return e.model.ID, nil
+	case "domain":
+		return lookup(e.s, (*Server).domains, e.model.NameHash, e.blockNumber)
+	case "blockNumber":
+		return e.model.BlockNumber, nil
+	case "transactionID":
+		return e.model.TransactionHash, nil
+	}
+	switch {
+	case field == "owner" && (e.model.Type == models.DomainEventTransfer || e.model.Type == models.DomainEventNewOwner):
+		return e.s.account(e.model.Owner, e.blockNumber), nil
+	case field == "parentDomain" && e.model.Type == models.DomainEventNewOwner:
+		return lookup(e.s, (*Server).domains, e.model.ParentHash, e.blockNumber)
+	case field == "resolver" && e.model.Type == models.DomainEventNewResolver:
+		if e.model.ResolverAddr == zeroAddress {
+			return nil, nil
+		}
+		return lookup(e.s, (*Server).resolvers, resolverID(e.model.ResolverAddr, e.model.NameHash), e.blockNumber)
+	case field == "ttl" && e.model.Type == models.DomainEventNewTTL:
+		return e.model.TTL, nil
+	}
+
+	return nil, fmt.Errorf("unknown field %s on %s", field, e.model.Type)
+}
@@ -0,0 +1,232 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/api"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+var _ = Describe("GraphQL", func() {
+	var db *postgres.DB
+	var handler http.Handler
+	address := "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"
+	resolverAddr := "0x5FfC014343cd971B7eb70732021E26C35B744cc4"
+	ethNode := utils.NameHash("eth")
+	vitalikNode := utils.NameHash("vitalik.eth")
+	resolverID := strings.ToLower(resolverAddr) + "-" + vitalikNode
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		headerIds := test_helpers.CreateHeaders(db, 3327420, 3327422)
+		ensRepo := repository.NewENSRepository(db)
+		records := []models.DomainModel{{
+			NameHash:    ethNode,
+			BlockNumber: 3327420,
+			LabelHash:   utils.LabelHash("eth"),
+			ParentHash:  utils.RootNode,
+			Owner:       address,
+			FullName:    "eth",
+		}, {
+			NameHash:     vitalikNode,
+			BlockNumber:  3327421,
+			LabelHash:    utils.LabelHash("vitalik"),
+			ParentHash:   ethNode,
+			Owner:        address,
+			ResolverAddr: resolverAddr,
+			FullName:     "vitalik.eth",
+		}, {
+			NameHash:     vitalikNode,
+			BlockNumber:  3327422,
+			LabelHash:    utils.LabelHash("vitalik"),
+			ParentHash:   ethNode,
+			Owner:        address,
+			ResolverAddr: resolverAddr,
+			PointsToAddr: address,
+			FullName:     "vitalik.eth",
+		}}
+		for _, record := range records {
+			record.HeaderID = headerIds[record.BlockNumber]
+			err := ensRepo.CreateRecord(record)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		err := repository.NewLabelRepository(db).CreateLabel(models.LabelModel{
+			LabelHash: utils.LabelHash("vitalik"),
+			Label:     "vitalik",
+			Source:    "wordlist",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		err = ensRepo.CreateTextRecord(models.TextRecordModel{
+			BlockNumber:  3327422,
+			HeaderID:     headerIds[3327422],
+			NameHash:     vitalikNode,
+			Key:          "url",
+			Value:        "https://vitalik.ca",
+			ResolverAddr: resolverAddr,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(`INSERT INTO ens.new_owner (header_id, node, label, owner, subnode, tx_idx, log_idx, raw_log)
+			VALUES ($1, $2, $3, $4, $5, 0, 1, '{"transactionHash": "0xabc"}')`,
+			headerIds[3327421], ethNode, utils.LabelHash("vitalik"), address, vitalikNode)
+		Expect(err).NotTo(HaveOccurred())
+		handler = api.NewServer(db).Handler()
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	post := func(query string, variables map[string]interface{}) string {
+		body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+		Expect(err).NotTo(HaveOccurred())
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		return recorder.Body.String()
+	}
+
+	It("Resolves domains and the entities related to them", func() {
+		response := post(`{
+			domains(where: {name: "vitalik.eth"}) {
+				id name labelName labelhash subdomainCount isMigrated ttl
+				parent { name }
+				owner { id }
+				resolvedAddress { id }
+				resolver { id address texts coinTypes }
+			}
+		}`, nil)
+
+		Expect(response).To(MatchJSON(`{"data": {"domains": [{
+			"id": "` + vitalikNode + `",
+			"name": "vitalik.eth",
+			"labelName": "vitalik",
+			"labelhash": "` + utils.LabelHash("vitalik") + `",
+			"subdomainCount": 0,
+			"isMigrated": false,
+			"ttl": null,
+			"parent": {"name": "eth"},
+			"owner": {"id": "` + strings.ToLower(address) + `"},
+			"resolvedAddress": {"id": "` + strings.ToLower(address) + `"},
+			"resolver": {"id": "` + resolverID + `", "address": "` + resolverAddr + `", "texts": ["url"], "coinTypes": null}
+		}]}}`))
+	})
+
+	It("Reads entities as of the block argument, and the entities related to them as of the same block", func() {
+		response := post(`query Domain($id: ID!, $block: Int) {
+			domain(id: $id, block: {number: $block}) { resolvedAddress { id } resolver { texts } parent { subdomainCount } }
+		}`, map[string]interface{}{"id": vitalikNode, "block": 3327421})
+
+		Expect(response).To(MatchJSON(`{"data": {"domain": {"resolvedAddress": null, "resolver": {"texts": null}, "parent": {"subdomainCount": 1}}}}`))
+
+		response = post(`{ domain(id: "`+vitalikNode+`", block: {number: 3327420}) { id } }`, nil)
+		Expect(response).To(MatchJSON(`{"data": {"domain": null}}`))
+	})
+
+	It("Paginates and orders lists", func() {
+		response := post(`{
+			first: domains(first: 1, orderBy: name) { name }
+			second: domains(first: 1, skip: 1, orderBy: name) { name }
+			last: domains(orderBy: name, orderDirection: desc) { name }
+		}`, nil)
+
+		Expect(response).To(MatchJSON(`{"data": {
+			"first": [{"name": "eth"}],
+			"second": [{"name": "vitalik.eth"}],
+			"last": [{"name": "vitalik.eth"}, {"name": "eth"}]
+		}}`))
+	})
+
+	It("Resolves the registry's events through the DomainEvent interface", func() {
+		response := post(`{
+			domain(id: "`+vitalikNode+`") {
+				events { id __typename transactionID ...on NewOwner { owner { id } parentDomain { name } } ...on Transfer { owner { id } } }
+			}
+			accounts { domains(orderBy: name) { name } }
+		}`, nil)
+
+		Expect(response).To(MatchJSON(`{"data": {
+			"domain": {"events": [{
+				"id": "3327421-1",
+				"__typename": "NewOwner",
+				"transactionID": "0xabc",
+				"owner": {"id": "` + strings.ToLower(address) + `"},
+				"parentDomain": {"name": "eth"}
+			}]},
+			"accounts": [{"domains": [{"name": "eth"}, {"name": "vitalik.eth"}]}]
+		}}`))
+	})
+
+	It("Returns errors for invalid arguments alongside the fields that resolved", func() {
+		response := post(`{
+			tooMany: domains(first: 5000) { id }
+			none: domains(first: 0) { id }
+			unknown: domains(where: {unknown: "value"}) { id }
+			future: domain(id: "`+vitalikNode+`", block: {number: 3327423}) { id }
+			eth: domain(id: "`+ethNode+`") { name }
+		}`, nil)
+
+		Expect(response).To(MatchJSON(`{
+			"data": {"tooMany": null, "none": [], "unknown": null, "future": null, "eth": {"name": "eth"}},
+			"errors": [
+				{"message": "first must be an integer between 0 and 1000", "path": ["tooMany"]},
+				{"message": "cannot filter by unknown field \"unknown\"", "path": ["unknown"]},
+				{"message": "block 3327423 has not been indexed, the latest indexed block is 3327422", "path": ["future"]}
+			]
+		}`))
+	})
+
+	It("Rejects queries nested deeper than the limit", func() {
+		response := post(`{ domains { parent { parent { parent { parent { parent { parent { parent { id } } } } } } } } }`, nil)
+
+		Expect(response).To(MatchJSON(`{"errors": [{"message": "query depth 9 exceeds the maximum of 8"}]}`))
+	})
+
+	It("Accepts queries and variables as GET parameters", func() {
+		params := url.Values{}
+		params.Set("query", `query ($id: ID!) { domain(id: $id) { name } }`)
+		params.Set("variables", `{"id": "`+ethNode+`"}`)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(MatchJSON(`{"data": {"domain": {"name": "eth"}}}`))
+	})
+
+	It("Rejects requests without a query", func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"variables": {}}`)))
+
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		Expect(recorder.Body.String()).To(MatchJSON(`{"errors": [{"message": "query is required"}]}`))
+	})
+})
@@ -0,0 +1,103 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Exports the rows of an ens event table, or of the domain records, in a block range to a CSV, JSON lines or Parquet file
+// Usage: export_tables --config environments/private.toml --table new_owner --format parquet --out new_owner_{from}_{to}.parquet
+// [--from 3327417] [--to 7700000] [--confirmations 12] [--state export_state.json] [--network mainnet]
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/export"
+	ensconfig "github.com/vulcanize/ens_transformers/transformers/domain_records/config"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+)
+
+func main() {
+	configFile := flag.String("config", "", "config file with the [database] settings")
+	table := flag.String("table", "", "table to export, one of "+strings.Join(repository.ExportTables(), ", "))
+	format := flag.String("format", export.FormatCSV, "format to export in: csv, jsonl or parquet")
+	out := flag.String("out", "", "file to export to, in which {from} and {to} are replaced by the block range exported; - for stdout")
+	from := flag.Int64("from", 0, "first block to export, when the state file has no high-water mark for the table")
+	to := flag.Int64("to", 0, "last block to export (default the last exportable block)")
+	confirmations := flag.Int64("confirmations", 12, "number of blocks below the latest header to leave out, as they could still be reorged")
+	stateFile := flag.String("state", "", "JSON file of high-water marks to resume incremental exports from, updated after each export")
+	network := flag.String("network", "mainnet", "network whose registries the domain records transformer watches: mainnet or ropsten")
+	flag.Parse()
+	if *configFile == "" || *table == "" || *out == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	registries := map[string]config.ContractConfig{
+		"mainnet": ensconfig.MainnetENSConfig,
+		"ropsten": ensconfig.RopstenENSConfig,
+	}
+	registry, ok := registries[*network]
+	if !ok {
+		log.Fatalf("unknown network %s, expected mainnet or ropsten", *network)
+	}
+
+	viper.SetConfigFile(*configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatal(err)
+	}
+	databaseConfig := config.Database{
+		Name:     viper.GetString("database.name"),
+		Hostname: viper.GetString("database.hostname"),
+		Port:     viper.GetInt("database.port"),
+		User:     viper.GetString("database.user"),
+		Password: viper.GetString("database.password"),
+	}
+	// Connect without registering an eth node, since every row comes from the database
+	db, err := sqlx.Connect("postgres", config.DbConnectionString(databaseConfig))
+	if err != nil {
+		log.Fatal(postgres.ErrDBConnectionFailed(err))
+	}
+	defer db.Close()
+
+	// The tables are bounded by where the transformers start, read from the contract settings of the config file as they read them
+	exportConfig := repository.ExportConfig{
+		StartingBlocks: export.StartingBlocks(),
+		Registry:       registry,
+	}
+	result, err := export.NewExporter(&postgres.DB{DB: db}, exportConfig).Run(export.Job{
+		Table:         *table,
+		Format:        *format,
+		Out:           *out,
+		From:          *from,
+		To:            *to,
+		Confirmations: *confirmations,
+		StateFile:     *stateFile,
+	})
+	if err == export.ErrNoNewBlocks {
+		log.Infof("%s is up to date: no blocks after %d to export yet", *table, result.From-1)
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("exported %d rows of %s from blocks %d to %d to %s", result.Rows, *table, result.From, result.To, result.File)
+}
@@ -0,0 +1,106 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Writes a zone file in the master file format for each [[zone]] table of the config file, from the indexed tables
+// Usage: export_zones --config environments/zones.toml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/dnsserver"
+)
+
+func main() {
+	configFile := flag.String("config", "", "config file with the [database] settings and a [[zone]] table for each zone to export")
+	flag.Parse()
+	if *configFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	viper.SetConfigFile(*configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatal(err)
+	}
+	var zones []dnsserver.ZoneConfig
+	if err := viper.UnmarshalKey("zone", &zones); err != nil {
+		log.Fatal(err)
+	}
+	if len(zones) == 0 {
+		log.Fatal("no [[zone]] tables in ", *configFile)
+	}
+	databaseConfig := config.Database{
+		Name:     viper.GetString("database.name"),
+		Hostname: viper.GetString("database.hostname"),
+		Port:     viper.GetInt("database.port"),
+		User:     viper.GetString("database.user"),
+		Password: viper.GetString("database.password"),
+	}
+	// Connect without registering an eth node, since every record comes from the database
+	db, err := sqlx.Connect("postgres", config.DbConnectionString(databaseConfig))
+	if err != nil {
+		log.Fatal(postgres.ErrDBConnectionFailed(err))
+	}
+	defer db.Close()
+
+	exporter := dnsserver.NewZoneExporter(&postgres.DB{DB: db})
+	for _, zone := range zones {
+		err := exportZone(exporter, zone)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Infof("exported the zone of %s to %s", zone.Parent, zone.File)
+	}
+}
+
+// Writes the zone to a temporary file next to its file and renames it into place, so that the file is never left half written
+func exportZone(exporter *dnsserver.ZoneExporter, zone dnsserver.ZoneConfig) error {
+	if zone.File == "" {
+		return fmt.Errorf("the zone of %s has no file", zone.Parent)
+	}
+	file, err := ioutil.TempFile(filepath.Dir(zone.File), filepath.Base(zone.File)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	err = exporter.Export(file, zone)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	// Zone files are read by the name server, so they aren't kept private like temporary files
+	err = os.Chmod(file.Name(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), zone.File)
+}
@@ -0,0 +1,74 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Imports a newline-separated wordlist into the ens.labels preimage table, filling in the full names of any domain records waiting on those labels
+// Usage: import_labels --config environments/private.toml --wordlist words.txt [--source wordlist]
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+)
+
+func main() {
+	configFile := flag.String("config", "", "config file with the [database] settings")
+	wordlistFile := flag.String("wordlist", "", "newline-separated list of labels or dotted names to import")
+	source := flag.String("source", "wordlist", "source recorded for the imported labels")
+	flag.Parse()
+	if *configFile == "" || *wordlistFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	viper.SetConfigFile(*configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatal(err)
+	}
+	databaseConfig := config.Database{
+		Name:     viper.GetString("database.name"),
+		Hostname: viper.GetString("database.hostname"),
+		Port:     viper.GetInt("database.port"),
+		User:     viper.GetString("database.user"),
+		Password: viper.GetString("database.password"),
+	}
+	// Connect without registering an eth node, since this doesn't sync anything from one
+	db, err := sqlx.Connect("postgres", config.DbConnectionString(databaseConfig))
+	if err != nil {
+		log.Fatal(postgres.ErrDBConnectionFailed(err))
+	}
+	defer db.Close()
+
+	wordlist, err := os.Open(*wordlistFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer wordlist.Close()
+
+	imported, err := repository.NewLabelRepository(&postgres.DB{DB: db}).ImportWordlist(wordlist, *source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("imported %d new labels from %s", imported, *wordlistFile)
+}
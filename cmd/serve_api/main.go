@@ -0,0 +1,73 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Serves read-only HTTP/JSON resolution queries answered from the indexed tables, without an eth node
+// Usage: serve_api --config environments/private.toml [--port 8080]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/api"
+)
+
+func main() {
+	configFile := flag.String("config", "", "config file with the [database] settings")
+	port := flag.Int("port", 8080, "port to serve the api on")
+	flag.Parse()
+	if *configFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	viper.SetConfigFile(*configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatal(err)
+	}
+	databaseConfig := config.Database{
+		Name:     viper.GetString("database.name"),
+		Hostname: viper.GetString("database.hostname"),
+		Port:     viper.GetInt("database.port"),
+		User:     viper.GetString("database.user"),
+		Password: viper.GetString("database.password"),
+	}
+	// Connect without registering an eth node, since every answer comes from the database
+	db, err := sqlx.Connect("postgres", config.DbConnectionString(databaseConfig))
+	if err != nil {
+		log.Fatal(postgres.ErrDBConnectionFailed(err))
+	}
+	defer db.Close()
+
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", *port),
+		Handler:      api.NewServer(&postgres.DB{DB: db}).Handler(),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+	log.Infof("serving the api on %s", server.Addr)
+	log.Fatal(server.ListenAndServe())
+}
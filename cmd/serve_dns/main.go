@@ -0,0 +1,71 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Serves DNS queries for ENS names over UDP, answered from the indexed tables
+// Usage: serve_dns --config environments/private.toml [--addr 127.0.0.1:5353] [--suffix eth]
+package main
+
+import (
+	"flag"
+	"net"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/dnsserver"
+)
+
+func main() {
+	configFile := flag.String("config", "", "config file with the [database] settings")
+	addr := flag.String("addr", "127.0.0.1:5353", "UDP address to serve DNS on")
+	suffix := flag.String("suffix", "eth", "DNS suffix to answer for in place of .eth, e.g. eth.local")
+	flag.Parse()
+	if *configFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	viper.SetConfigFile(*configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatal(err)
+	}
+	databaseConfig := config.Database{
+		Name:     viper.GetString("database.name"),
+		Hostname: viper.GetString("database.hostname"),
+		Port:     viper.GetInt("database.port"),
+		User:     viper.GetString("database.user"),
+		Password: viper.GetString("database.password"),
+	}
+	// Connect without registering an eth node, since every answer comes from the database
+	db, err := sqlx.Connect("postgres", config.DbConnectionString(databaseConfig))
+	if err != nil {
+		log.Fatal(postgres.ErrDBConnectionFailed(err))
+	}
+	defer db.Close()
+
+	conn, err := net.ListenPacket("udp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	log.Infof("serving DNS for *.%s on %s", *suffix, conn.LocalAddr())
+	log.Fatal(dnsserver.NewServer(&postgres.DB{DB: db}, *suffix).Serve(conn))
+}
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE ens.watched_resolvers (
+  id                    SERIAL PRIMARY KEY,
+  resolver_addr         VARCHAR(66) NOT NULL UNIQUE,
+  starting_block        BIGINT NOT NULL,
+  abi                   TEXT NOT NULL DEFAULT '',
+  valid                 BOOLEAN NOT NULL
+);
+
+-- +goose Down
+DROP TABLE ens.watched_resolvers;
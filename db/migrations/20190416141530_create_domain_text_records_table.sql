@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE ens.domain_text_records (
+  id                    SERIAL PRIMARY KEY,
+  block_number          BIGINT NOT NULL,
+  name_hash             VARCHAR(66) NOT NULL,
+  key                   TEXT NOT NULL,
+  resolver_addr         VARCHAR(66) NOT NULL,
+  UNIQUE (name_hash, key, block_number)
+);
+
+-- +goose Down
+DROP TABLE ens.domain_text_records;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE ens.domain_text_records
+  ADD COLUMN value TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE ens.domain_records
+  ADD COLUMN text_value TEXT NOT NULL DEFAULT '';
+
+
+-- +goose Down
+ALTER TABLE ens.domain_text_records
+  DROP COLUMN value;
+
+ALTER TABLE ens.domain_records
+  DROP COLUMN text_value;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE ens.labels (
+  label_hash            VARCHAR(66) PRIMARY KEY,
+  label                 TEXT NOT NULL,
+  source                TEXT NOT NULL
+);
+
+ALTER TABLE ens.domain_records
+  ADD COLUMN full_name TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX domain_records_label_hash_index ON ens.domain_records (label_hash);
+
+
+-- +goose Down
+DROP INDEX ens.domain_records_label_hash_index;
+
+DROP TABLE ens.labels;
+
+ALTER TABLE ens.domain_records
+  DROP COLUMN full_name;
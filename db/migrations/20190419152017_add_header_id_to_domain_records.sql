@@ -0,0 +1,63 @@
+-- +goose Up
+-- Existing records are linked to the header at their block synced from the node the transformers ran against, i.e. the node
+-- whose headers have been checked; the migration fails rather than dropping records that can't be linked to a unique header
+CREATE TEMPORARY VIEW checked_node_headers AS
+  SELECT MIN(headers.id) AS id, headers.block_number
+  FROM public.headers
+  WHERE headers.eth_node_fingerprint IN (
+    SELECT DISTINCT checked.eth_node_fingerprint
+    FROM public.headers AS checked
+    JOIN public.checked_headers ON checked_headers.header_id = checked.id
+  )
+  GROUP BY headers.block_number
+  HAVING COUNT(*) = 1;
+
+ALTER TABLE ens.domain_records
+  ADD COLUMN header_id INTEGER REFERENCES headers (id) ON DELETE CASCADE;
+
+UPDATE ens.domain_records SET header_id = checked_node_headers.id
+  FROM checked_node_headers WHERE checked_node_headers.block_number = domain_records.block_number;
+
+ALTER TABLE ens.domain_text_records
+  ADD COLUMN header_id INTEGER REFERENCES headers (id) ON DELETE CASCADE;
+
+UPDATE ens.domain_text_records SET header_id = checked_node_headers.id
+  FROM checked_node_headers WHERE checked_node_headers.block_number = domain_text_records.block_number;
+
+-- +goose StatementBegin
+DO $$
+DECLARE
+  unlinked BIGINT;
+BEGIN
+  SELECT (SELECT COUNT(*) FROM ens.domain_records WHERE header_id IS NULL) +
+         (SELECT COUNT(*) FROM ens.domain_text_records WHERE header_id IS NULL)
+    INTO unlinked;
+  IF unlinked > 0 THEN
+    RAISE EXCEPTION '% domain records can''t be linked to a unique header of the synced node; sync their headers, or clear ens.domain_records, ens.domain_text_records and the transformer''s checked_headers columns to re-derive them', unlinked;
+  END IF;
+END
+$$;
+-- +goose StatementEnd
+
+DROP VIEW checked_node_headers;
+
+ALTER TABLE ens.domain_records
+  ALTER COLUMN header_id SET NOT NULL;
+
+CREATE INDEX domain_records_header_index ON ens.domain_records (header_id);
+
+ALTER TABLE ens.domain_text_records
+  ALTER COLUMN header_id SET NOT NULL;
+
+CREATE INDEX domain_text_records_header_index ON ens.domain_text_records (header_id);
+
+
+-- +goose Down
+DROP INDEX ens.domain_records_header_index;
+DROP INDEX ens.domain_text_records_header_index;
+
+ALTER TABLE ens.domain_records
+  DROP COLUMN header_id;
+
+ALTER TABLE ens.domain_text_records
+  DROP COLUMN header_id;
@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE ens.domain_records
+  ADD COLUMN migrated BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE ens.registry_approvals (
+  id                    SERIAL PRIMARY KEY,
+  header_id             INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  block_number          BIGINT NOT NULL,
+  registry_addr         VARCHAR(66) NOT NULL,
+  owner_addr            VARCHAR(66) NOT NULL,
+  operator_addr         VARCHAR(66) NOT NULL,
+  approved              BOOLEAN NOT NULL,
+  UNIQUE (registry_addr, owner_addr, operator_addr, block_number)
+);
+
+CREATE INDEX registry_approvals_owner_index ON ens.registry_approvals (owner_addr);
+
+-- +goose Down
+DROP TABLE ens.registry_approvals;
+
+ALTER TABLE ens.domain_records
+  DROP COLUMN migrated;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE ens.name_migrated (
+  id                SERIAL PRIMARY KEY,
+  header_id         INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  label_hash        CHARACTER VARYING(66) NOT NULL,
+  owner             CHARACTER VARYING(66) NOT NULL,
+  expires           NUMERIC NOT NULL,
+  tx_idx            INTEGER NOT NUll,
+  log_idx           INTEGER NOT NUll,
+  raw_log           JSONB,
+  UNIQUE (header_id, tx_idx, log_idx)
+);
+
+ALTER TABLE public.checked_headers
+  ADD COLUMN name_migrated_checked INTEGER NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+DROP TABLE ens.name_migrated;
+
+ALTER TABLE public.checked_headers
+  DROP COLUMN name_migrated_checked;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE ens.controller_removed (
+  id                SERIAL PRIMARY KEY,
+  header_id         INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  controller        CHARACTER VARYING(66) NOT NULL,
+  tx_idx            INTEGER NOT NUll,
+  log_idx           INTEGER NOT NUll,
+  raw_log           JSONB,
+  UNIQUE (header_id, tx_idx, log_idx)
+);
+
+ALTER TABLE public.checked_headers
+  ADD COLUMN controller_removed_checked INTEGER NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+DROP TABLE ens.controller_removed;
+
+ALTER TABLE public.checked_headers
+  DROP COLUMN controller_removed_checked;
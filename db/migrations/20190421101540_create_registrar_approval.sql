@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE ens.registrar_approval (
+  id                SERIAL PRIMARY KEY,
+  header_id         INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  owner             CHARACTER VARYING(66) NOT NULL,
+  approved          CHARACTER VARYING(66) NOT NULL,
+  label_hash        CHARACTER VARYING(66) NOT NULL,
+  tx_idx            INTEGER NOT NUll,
+  log_idx           INTEGER NOT NUll,
+  raw_log           JSONB,
+  UNIQUE (header_id, tx_idx, log_idx)
+);
+
+ALTER TABLE public.checked_headers
+  ADD COLUMN registrar_approval_checked INTEGER NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+DROP TABLE ens.registrar_approval;
+
+ALTER TABLE public.checked_headers
+  DROP COLUMN registrar_approval_checked;
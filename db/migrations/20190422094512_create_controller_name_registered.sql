@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE ens.controller_name_registered (
+  id                SERIAL PRIMARY KEY,
+  header_id         INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  name              TEXT NOT NULL,
+  label_hash        CHARACTER VARYING(66) NOT NULL,
+  owner             CHARACTER VARYING(66) NOT NULL,
+  cost              NUMERIC NOT NULL,
+  expires           NUMERIC NOT NULL,
+  tx_idx            INTEGER NOT NUll,
+  log_idx           INTEGER NOT NUll,
+  raw_log           JSONB,
+  UNIQUE (header_id, tx_idx, log_idx)
+);
+
+ALTER TABLE public.checked_headers
+  ADD COLUMN controller_name_registered_checked INTEGER NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+DROP TABLE ens.controller_name_registered;
+
+ALTER TABLE public.checked_headers
+  DROP COLUMN controller_name_registered_checked;
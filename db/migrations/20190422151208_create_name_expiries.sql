@@ -0,0 +1,86 @@
+-- +goose Up
+-- Every event that sets or ends the term of a .eth name, normalized to its label hash
+-- Legacy registrar names have no expiry of their own, they lapse at the end of the permanent registrar's migration period (2020-05-04)
+-- unless they are migrated, and cannot be renewed during a grace period
+-- Permanent registrar names can be renewed for 90 days after they expire
+CREATE VIEW ens.name_expiry_events AS
+  SELECT events.*, headers.block_number
+  FROM (
+    SELECT header_id, tx_idx, log_idx, hash AS label_hash, registration_date AS registered_at,
+           1588550400::NUMERIC AS expires, 1588550400::NUMERIC AS grace_period_ends, FALSE AS released
+    FROM ens.hash_registered
+    UNION ALL
+    SELECT header_id, tx_idx, log_idx, hash, NULL, NULL, NULL, TRUE
+    FROM ens.hash_released
+    UNION ALL
+    SELECT header_id, tx_idx, log_idx, hash, NULL, NULL, NULL, TRUE
+    FROM ens.hash_invalidated
+    UNION ALL
+    SELECT header_id, tx_idx, log_idx, label_hash, NULL, expires, expires + 7776000, FALSE
+    FROM ens.name_migrated
+    UNION ALL
+    SELECT name_registered.header_id, tx_idx, log_idx, label_hash, headers.block_timestamp, expires, expires + 7776000, FALSE
+    FROM ens.name_registered
+    JOIN public.headers ON headers.id = name_registered.header_id
+    UNION ALL
+    SELECT header_id, tx_idx, log_idx, label_hash, NULL, expires, expires + 7776000, FALSE
+    FROM ens.name_renewed
+  ) AS events
+  JOIN public.headers ON headers.id = events.header_id;
+
+-- The expiry, grace period end and status of every name as of the given block, evaluated at the timestamp of that block
+-- A name's registration date is carried over from its latest registration through its migration and renewals
+-- +goose StatementBegin
+CREATE FUNCTION ens.name_expiries_at(block BIGINT)
+  RETURNS TABLE (
+    label_hash        VARCHAR(66),
+    block_number      BIGINT,
+    registered_at     NUMERIC,
+    expires           NUMERIC,
+    grace_period_ends NUMERIC,
+    status            TEXT
+  ) AS $$
+  SELECT latest.label_hash,
+         latest.block_number,
+         registration.registered_at,
+         latest.expires,
+         latest.grace_period_ends,
+         CASE
+           WHEN latest.released THEN 'released'
+           WHEN at_block.block_timestamp < latest.expires THEN 'active'
+           WHEN at_block.block_timestamp < latest.grace_period_ends THEN 'grace'
+           ELSE 'expired'
+         END
+  FROM (
+    SELECT DISTINCT ON (events.label_hash) events.*
+    FROM ens.name_expiry_events AS events
+    WHERE events.block_number <= $1
+    ORDER BY events.label_hash, events.block_number DESC, events.tx_idx DESC, events.log_idx DESC
+  ) AS latest
+  LEFT JOIN (
+    SELECT DISTINCT ON (events.label_hash) events.label_hash, events.registered_at
+    FROM ens.name_expiry_events AS events
+    WHERE events.block_number <= $1 AND events.registered_at IS NOT NULL
+    ORDER BY events.label_hash, events.block_number DESC, events.tx_idx DESC, events.log_idx DESC
+  ) AS registration ON registration.label_hash = latest.label_hash
+  CROSS JOIN (
+    SELECT headers.block_timestamp
+    FROM public.headers
+    WHERE headers.block_number <= $1
+    ORDER BY headers.block_number DESC
+    LIMIT 1
+  ) AS at_block
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- The expiries of every name as of the latest synced header
+CREATE VIEW ens.name_expiries AS
+  SELECT * FROM ens.name_expiries_at((SELECT MAX(block_number) FROM public.headers));
+
+
+-- +goose Down
+DROP VIEW ens.name_expiries;
+
+DROP FUNCTION ens.name_expiries_at(BIGINT);
+
+DROP VIEW ens.name_expiry_events;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE ens.domain_coin_addresses (
+  id                    SERIAL PRIMARY KEY,
+  header_id             INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  block_number          BIGINT NOT NULL,
+  name_hash             VARCHAR(66) NOT NULL,
+  coin_type             NUMERIC NOT NULL,
+  address               TEXT NOT NULL,
+  raw_address           TEXT NOT NULL,
+  resolver_addr         VARCHAR(66) NOT NULL,
+  UNIQUE (name_hash, coin_type, block_number)
+);
+
+CREATE INDEX domain_coin_addresses_header_index ON ens.domain_coin_addresses (header_id);
+
+-- +goose Down
+DROP INDEX ens.domain_coin_addresses_header_index;
+
+DROP TABLE ens.domain_coin_addresses;
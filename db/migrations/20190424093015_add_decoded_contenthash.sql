@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE ens.contenthash_changed
+  ADD COLUMN content_protocol TEXT NOT NULL DEFAULT '',
+  ADD COLUMN content_uri TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE ens.domain_records
+  ADD COLUMN contenthash_protocol TEXT NOT NULL DEFAULT '',
+  ADD COLUMN contenthash_uri TEXT NOT NULL DEFAULT '';
+
+
+-- +goose Down
+ALTER TABLE ens.contenthash_changed
+  DROP COLUMN content_protocol,
+  DROP COLUMN content_uri;
+
+ALTER TABLE ens.domain_records
+  DROP COLUMN contenthash_protocol,
+  DROP COLUMN contenthash_uri;
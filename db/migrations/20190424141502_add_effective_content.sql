@@ -0,0 +1,27 @@
+-- +goose Up
+ALTER TABLE ens.content_changed
+  ADD COLUMN content_protocol TEXT NOT NULL DEFAULT '',
+  ADD COLUMN content_uri TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE ens.multihash_changed
+  ADD COLUMN content_protocol TEXT NOT NULL DEFAULT '',
+  ADD COLUMN content_uri TEXT NOT NULL DEFAULT '';
+
+-- The content of the node from whichever of the content, multihash and contenthash records was changed most recently
+ALTER TABLE ens.domain_records
+  ADD COLUMN effective_content_protocol TEXT NOT NULL DEFAULT '',
+  ADD COLUMN effective_content_uri TEXT NOT NULL DEFAULT '';
+
+
+-- +goose Down
+ALTER TABLE ens.content_changed
+  DROP COLUMN content_protocol,
+  DROP COLUMN content_uri;
+
+ALTER TABLE ens.multihash_changed
+  DROP COLUMN content_protocol,
+  DROP COLUMN content_uri;
+
+ALTER TABLE ens.domain_records
+  DROP COLUMN effective_content_protocol,
+  DROP COLUMN effective_content_uri;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE ens.interface_changed (
+  id                SERIAL PRIMARY KEY,
+  header_id         INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  resolver          CHARACTER VARYING(66) NOT NULL,
+  node              CHARACTER VARYING(66) NOT NULL,
+  interface_id      VARCHAR(10) NOT NULL,
+  implementer       VARCHAR(66) NOT NULL,
+  tx_idx            INTEGER NOT NUll,
+  log_idx           INTEGER NOT NUll,
+  raw_log           JSONB,
+  UNIQUE (header_id, tx_idx, log_idx)
+);
+
+ALTER TABLE public.checked_headers
+  ADD COLUMN interface_changed_checked INTEGER NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+DROP TABLE ens.interface_changed;
+
+ALTER TABLE public.checked_headers
+  DROP COLUMN interface_changed_checked;
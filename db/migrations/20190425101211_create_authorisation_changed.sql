@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE ens.authorisation_changed (
+  id                SERIAL PRIMARY KEY,
+  header_id         INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  resolver          CHARACTER VARYING(66) NOT NULL,
+  node              CHARACTER VARYING(66) NOT NULL,
+  owner             VARCHAR(66) NOT NULL,
+  target            VARCHAR(66) NOT NULL,
+  is_authorised     BOOLEAN NOT NULL,
+  tx_idx            INTEGER NOT NUll,
+  log_idx           INTEGER NOT NUll,
+  raw_log           JSONB,
+  UNIQUE (header_id, tx_idx, log_idx)
+);
+
+ALTER TABLE public.checked_headers
+  ADD COLUMN authorisation_changed_checked INTEGER NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+DROP TABLE ens.authorisation_changed;
+
+ALTER TABLE public.checked_headers
+  DROP COLUMN authorisation_changed_checked;
@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE ens.dns_record_changed (
+  id                SERIAL PRIMARY KEY,
+  header_id         INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  resolver          CHARACTER VARYING(66) NOT NULL,
+  node              CHARACTER VARYING(66) NOT NULL,
+  name              TEXT NOT NULL,
+  resource          INTEGER NOT NULL,
+  record            TEXT NOT NULL,
+  tx_idx            INTEGER NOT NUll,
+  log_idx           INTEGER NOT NUll,
+  raw_log           JSONB,
+  UNIQUE (header_id, tx_idx, log_idx)
+);
+
+-- The resource records of each RRset set by a DNSRecordChanged event, parsed from their wire format
+CREATE TABLE ens.dns_resource_records (
+  id                    SERIAL PRIMARY KEY,
+  dns_record_changed_id INTEGER NOT NULL REFERENCES ens.dns_record_changed (id) ON DELETE CASCADE,
+  name                  TEXT NOT NULL,
+  type                  INTEGER NOT NULL,
+  class                 INTEGER NOT NULL,
+  ttl                   BIGINT NOT NULL,
+  rdata                 TEXT NOT NULL
+);
+
+CREATE INDEX dns_resource_records_dns_record_changed_id_index ON ens.dns_resource_records (dns_record_changed_id);
+
+ALTER TABLE public.checked_headers
+  ADD COLUMN dns_record_changed_checked INTEGER NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+DROP TABLE ens.dns_resource_records;
+
+DROP TABLE ens.dns_record_changed;
+
+ALTER TABLE public.checked_headers
+  DROP COLUMN dns_record_changed_checked;
@@ -0,0 +1,55 @@
+-- +goose Up
+-- The latest VersionChanged event of every node on every resolver, which wipes the node's DNS records and zonehash
+CREATE VIEW ens.dns_record_versions AS
+  SELECT DISTINCT ON (version_changed.resolver, version_changed.node)
+         version_changed.resolver, version_changed.node, headers.block_number, version_changed.tx_idx, version_changed.log_idx
+  FROM ens.version_changed
+  JOIN public.headers ON headers.id = version_changed.header_id
+  ORDER BY version_changed.resolver, version_changed.node, headers.block_number DESC, version_changed.tx_idx DESC, version_changed.log_idx DESC;
+
+-- The current DNS zone of every node on every resolver: the resource records of each name and type's latest RRset
+-- RRsets that were deleted, or set before the node's latest version change, are left out
+CREATE VIEW ens.dns_zones AS
+  SELECT latest.resolver, latest.node, latest.block_number, latest.resource,
+         dns_resource_records.name, dns_resource_records.type, dns_resource_records.class,
+         dns_resource_records.ttl, dns_resource_records.rdata
+  FROM (
+    SELECT DISTINCT ON (events.resolver, events.node, events.name, events.resource) events.*, headers.block_number
+    FROM (
+      SELECT id AS dns_record_changed_id, header_id, resolver, node, name, resource, tx_idx, log_idx
+      FROM ens.dns_record_changed
+      UNION ALL
+      SELECT NULL, header_id, resolver, node, name, resource, tx_idx, log_idx
+      FROM ens.dns_record_deleted
+    ) AS events
+    JOIN public.headers ON headers.id = events.header_id
+    ORDER BY events.resolver, events.node, events.name, events.resource, headers.block_number DESC, events.tx_idx DESC, events.log_idx DESC
+  ) AS latest
+  JOIN ens.dns_resource_records ON dns_resource_records.dns_record_changed_id = latest.dns_record_changed_id
+  LEFT JOIN ens.dns_record_versions AS versions ON versions.resolver = latest.resolver AND versions.node = latest.node
+  WHERE versions.node IS NULL
+     OR (latest.block_number, latest.tx_idx, latest.log_idx) > (versions.block_number, versions.tx_idx, versions.log_idx);
+
+-- The current zonehash of every node on every resolver, cleared by a later version change
+CREATE VIEW ens.dns_zonehashes AS
+  SELECT latest.resolver, latest.node, latest.block_number, latest.zonehash
+  FROM (
+    SELECT DISTINCT ON (dns_zonehash_changed.resolver, dns_zonehash_changed.node)
+           dns_zonehash_changed.resolver, dns_zonehash_changed.node, dns_zonehash_changed.zonehash,
+           headers.block_number, dns_zonehash_changed.tx_idx, dns_zonehash_changed.log_idx
+    FROM ens.dns_zonehash_changed
+    JOIN public.headers ON headers.id = dns_zonehash_changed.header_id
+    ORDER BY dns_zonehash_changed.resolver, dns_zonehash_changed.node, headers.block_number DESC, dns_zonehash_changed.tx_idx DESC, dns_zonehash_changed.log_idx DESC
+  ) AS latest
+  LEFT JOIN ens.dns_record_versions AS versions ON versions.resolver = latest.resolver AND versions.node = latest.node
+  WHERE latest.zonehash <> '0x'
+    AND (versions.node IS NULL
+         OR (latest.block_number, latest.tx_idx, latest.log_idx) > (versions.block_number, versions.tx_idx, versions.log_idx));
+
+
+-- +goose Down
+DROP VIEW ens.dns_zonehashes;
+
+DROP VIEW ens.dns_zones;
+
+DROP VIEW ens.dns_record_versions;
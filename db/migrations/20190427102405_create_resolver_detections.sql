@@ -0,0 +1,26 @@
+-- +goose Up
+-- Upgradeable resolvers have their interfaces detected again, from the block they were last detected at
+ALTER TABLE ens.watched_resolvers
+  ADD COLUMN upgradeable BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN detected_at BIGINT NOT NULL DEFAULT 0;
+
+-- Every decision made about how to watch a resolver: the method its abi was detected with (erc165, allow_list, bytecode or none),
+-- the hash of its bytecode if it could be fetched, and whether it looked upgradeable
+CREATE TABLE ens.resolver_detections (
+  id                    SERIAL PRIMARY KEY,
+  resolver_addr         VARCHAR(66) NOT NULL,
+  block_number          BIGINT NOT NULL,
+  method                TEXT NOT NULL,
+  code_hash             VARCHAR(66) NOT NULL DEFAULT '',
+  abi                   TEXT NOT NULL DEFAULT '',
+  upgradeable           BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX resolver_detections_resolver_addr_index ON ens.resolver_detections (resolver_addr);
+
+-- +goose Down
+DROP TABLE ens.resolver_detections;
+
+ALTER TABLE ens.watched_resolvers
+  DROP COLUMN upgradeable,
+  DROP COLUMN detected_at;
@@ -0,0 +1,63 @@
+-- +goose Up
+-- Every claim of a reverse node (<address>.addr.reverse) by an address, taken from the ReverseRegistrar's ReverseClaimed events
+-- and from the registry's NewOwner events under addr.reverse
+CREATE TABLE ens.reverse_records (
+  id                    SERIAL PRIMARY KEY,
+  header_id             INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE,
+  block_number          BIGINT NOT NULL,
+  address               VARCHAR(66) NOT NULL,
+  node                  VARCHAR(66) NOT NULL,
+  source                TEXT NOT NULL,
+  UNIQUE (header_id, address, node, source)
+);
+
+CREATE INDEX reverse_records_address_index ON ens.reverse_records (LOWER(address));
+
+CREATE INDEX reverse_records_header_index ON ens.reverse_records (header_id);
+
+-- The primary name of the given address, or of every address that had claimed its reverse node if it is NULL, as of the given block:
+-- the name set (NameChanged) on its most recently claimed reverse node
+-- Whether the name resolves back to the address is left to the caller, which looks the name up by the namehash of its normalized form
+-- +goose StatementBegin
+CREATE FUNCTION ens.primary_names_at(block BIGINT, addr VARCHAR(66) DEFAULT NULL)
+  RETURNS TABLE (
+    address      VARCHAR(66),
+    node         VARCHAR(66),
+    block_number BIGINT,
+    name         TEXT
+  ) AS $$
+  SELECT claims.address,
+         claims.node,
+         claims.block_number,
+         COALESCE(reverse.resolved_name, '')::TEXT
+  FROM (
+    SELECT DISTINCT ON (LOWER(reverse_records.address)) reverse_records.*
+    FROM ens.reverse_records
+    WHERE reverse_records.block_number <= $1 AND ($2 IS NULL OR LOWER(reverse_records.address) = LOWER($2))
+    ORDER BY LOWER(reverse_records.address), reverse_records.block_number DESC, reverse_records.id DESC
+  ) AS claims
+  LEFT JOIN LATERAL (
+    SELECT domain_records.resolved_name
+    FROM ens.domain_records
+    WHERE domain_records.name_hash = claims.node AND domain_records.block_number <= $1
+    ORDER BY domain_records.block_number DESC
+    LIMIT 1
+  ) AS reverse ON TRUE
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- The primary names of every address as of the latest synced header
+CREATE VIEW ens.primary_names AS
+  SELECT * FROM ens.primary_names_at((SELECT MAX(block_number) FROM public.headers));
+
+
+-- +goose Down
+DROP VIEW ens.primary_names;
+
+DROP FUNCTION ens.primary_names_at(BIGINT, VARCHAR(66));
+
+DROP INDEX ens.reverse_records_header_index;
+
+DROP INDEX ens.reverse_records_address_index;
+
+DROP TABLE ens.reverse_records;
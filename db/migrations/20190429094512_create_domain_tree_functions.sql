@@ -0,0 +1,86 @@
+-- +goose Up
+-- Children are looked up by their parent at a blockheight
+CREATE INDEX domain_records_parent_hash_index ON ens.domain_records (parent_hash, block_number);
+
+-- Records are sparse, so the state of a node at a given block is its most recent record at or before the block
+-- +goose StatementBegin
+CREATE FUNCTION ens.domain_record_at(node VARCHAR(66), block BIGINT)
+  RETURNS SETOF ens.domain_records AS $$
+  SELECT *
+  FROM ens.domain_records
+  WHERE domain_records.name_hash = $1 AND domain_records.block_number <= $2
+  ORDER BY domain_records.block_number DESC
+  LIMIT 1
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- The records of the nodes directly under the given node as of the given block, ordered by full name
+-- A node's parent is fixed by its name hash, so every record of a child carries the same parent_hash
+-- +goose StatementBegin
+CREATE FUNCTION ens.domain_children(node VARCHAR(66), block BIGINT)
+  RETURNS SETOF ens.domain_records AS $$
+  SELECT children.*
+  FROM (
+    SELECT DISTINCT ON (domain_records.name_hash) *
+    FROM ens.domain_records
+    WHERE domain_records.parent_hash = $1 AND domain_records.block_number <= $2
+    ORDER BY domain_records.name_hash, domain_records.block_number DESC
+  ) AS children
+  ORDER BY children.full_name, children.name_hash
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- The records of every node below the given node as of the given block, up to the given depth (children are at depth 1)
+-- A depth below 1 returns the whole subtree. Nodes are ordered by their depth, then by full name
+-- +goose StatementBegin
+CREATE FUNCTION ens.domain_descendants(node VARCHAR(66), max_depth INTEGER, block BIGINT)
+  RETURNS SETOF ens.domain_records AS $$
+  WITH RECURSIVE descendants (name_hash, full_name, depth) AS (
+    SELECT children.name_hash, children.full_name, 1
+    FROM ens.domain_children($1, $3) AS children
+    UNION ALL
+    SELECT children.name_hash, children.full_name, descendants.depth + 1
+    FROM descendants
+    CROSS JOIN LATERAL ens.domain_children(descendants.name_hash, $3) AS children
+    WHERE $2 < 1 OR descendants.depth < $2
+  )
+  SELECT records.*
+  FROM descendants
+  CROSS JOIN LATERAL ens.domain_record_at(descendants.name_hash, $3) AS records
+  ORDER BY descendants.depth, descendants.full_name, descendants.name_hash
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- The records of every ancestor of the given node as of the given block, from its parent up to the root node
+-- The walk stops at the first ancestor that has never been seen, e.g. one created before the transformer's starting block
+-- +goose StatementBegin
+CREATE FUNCTION ens.domain_ancestors(node VARCHAR(66), block BIGINT)
+  RETURNS SETOF ens.domain_records AS $$
+  WITH RECURSIVE ancestors (name_hash, depth) AS (
+    SELECT node_record.parent_hash, 1
+    FROM ens.domain_record_at($1, $2) AS node_record
+    WHERE node_record.parent_hash <> ''
+    UNION ALL
+    SELECT node_record.parent_hash, ancestors.depth + 1
+    FROM ancestors
+    CROSS JOIN LATERAL ens.domain_record_at(ancestors.name_hash, $2) AS node_record
+    WHERE node_record.parent_hash <> ''
+  )
+  SELECT records.*
+  FROM ancestors
+  CROSS JOIN LATERAL ens.domain_record_at(ancestors.name_hash, $2) AS records
+  ORDER BY ancestors.depth
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+
+-- +goose Down
+DROP FUNCTION ens.domain_ancestors(VARCHAR(66), BIGINT);
+
+DROP FUNCTION ens.domain_descendants(VARCHAR(66), INTEGER, BIGINT);
+
+DROP FUNCTION ens.domain_children(VARCHAR(66), BIGINT);
+
+DROP FUNCTION ens.domain_record_at(VARCHAR(66), BIGINT);
+
+DROP INDEX ens.domain_records_parent_hash_index;
@@ -0,0 +1,63 @@
+-- +goose Up
+-- The latest domain record of every node, kept up to date alongside ens.domain_records so that the current state of a node
+-- can be read without finding its most recent record
+-- It does not reference the header its row was derived from: rows of replaced headers are reverted to the previous record when
+-- the domain records at or above the reorged blockheight are deleted
+CREATE TABLE ens.current_domains (
+  name_hash                  VARCHAR(66) PRIMARY KEY,
+  block_number               BIGINT NOT NULL,
+  header_id                  INTEGER NOT NULL,
+  label_hash                 VARCHAR(66) NOT NULL,
+  parent_hash                VARCHAR(66) NOT NULL,
+  owner_addr                 VARCHAR(66) NOT NULL,
+  resolver_addr              VARCHAR(66),
+  points_to_addr             VARCHAR(66),
+  resolved_name              VARCHAR(66),
+  content_                   VARCHAR(66),
+  content_type               TEXT,
+  pub_key_x                  VARCHAR(66),
+  pub_key_y                  VARCHAR(66),
+  ttl                        TEXT,
+  text_key                   TEXT,
+  indexed_text_key           TEXT,
+  text_value                 TEXT NOT NULL DEFAULT '',
+  multihash                  TEXT,
+  contenthash                TEXT,
+  full_name                  TEXT NOT NULL DEFAULT '',
+  migrated                   BOOLEAN NOT NULL DEFAULT FALSE,
+  contenthash_protocol       TEXT NOT NULL DEFAULT '',
+  contenthash_uri            TEXT NOT NULL DEFAULT '',
+  effective_content_protocol TEXT NOT NULL DEFAULT '',
+  effective_content_uri      TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX current_domains_owner_index ON ens.current_domains (owner_addr);
+
+CREATE INDEX current_domains_resolver_index ON ens.current_domains (resolver_addr);
+
+CREATE INDEX current_domains_points_to_index ON ens.current_domains (points_to_addr);
+
+CREATE INDEX current_domains_label_hash_index ON ens.current_domains (label_hash);
+
+INSERT INTO ens.current_domains
+  (block_number, name_hash, label_hash, parent_hash, owner_addr, resolver_addr, points_to_addr, resolved_name, content_, content_type,
+         pub_key_x, pub_key_y, ttl, text_key, indexed_text_key, text_value, multihash, contenthash, full_name, header_id, migrated,
+         contenthash_protocol, contenthash_uri, effective_content_protocol, effective_content_uri)
+  SELECT DISTINCT ON (name_hash)
+         block_number, name_hash, label_hash, parent_hash, owner_addr, resolver_addr, points_to_addr, resolved_name, content_, content_type,
+         pub_key_x, pub_key_y, ttl, text_key, indexed_text_key, text_value, multihash, contenthash, full_name, header_id, migrated,
+         contenthash_protocol, contenthash_uri, effective_content_protocol, effective_content_uri
+  FROM ens.domain_records
+  ORDER BY name_hash, block_number DESC;
+
+
+-- +goose Down
+DROP INDEX ens.current_domains_label_hash_index;
+
+DROP INDEX ens.current_domains_points_to_index;
+
+DROP INDEX ens.current_domains_resolver_index;
+
+DROP INDEX ens.current_domains_owner_index;
+
+DROP TABLE ens.current_domains;
@@ -0,0 +1,38 @@
+-- +goose Up
+-- Snapshots read the most recent record of every node at or before a block
+CREATE INDEX domain_records_name_hash_block_index ON ens.domain_records (name_hash, block_number DESC);
+
+-- The owner, resolver, address and TTL of every node as of the given block, taken from its most recent domain record at or before the block
+-- block_number is the block the node last changed at
+-- +goose StatementBegin
+CREATE FUNCTION ens.domain_snapshot_at(block BIGINT)
+  RETURNS TABLE (
+    name_hash      VARCHAR(66),
+    full_name      TEXT,
+    parent_hash    VARCHAR(66),
+    owner_addr     VARCHAR(66),
+    resolver_addr  VARCHAR(66),
+    points_to_addr VARCHAR(66),
+    ttl            TEXT,
+    block_number   BIGINT
+  ) AS $$
+  SELECT DISTINCT ON (domain_records.name_hash)
+         domain_records.name_hash,
+         domain_records.full_name,
+         domain_records.parent_hash,
+         domain_records.owner_addr,
+         COALESCE(domain_records.resolver_addr, ''),
+         COALESCE(domain_records.points_to_addr, ''),
+         COALESCE(domain_records.ttl, ''),
+         domain_records.block_number
+  FROM ens.domain_records
+  WHERE domain_records.block_number <= $1
+  ORDER BY domain_records.name_hash, domain_records.block_number DESC
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+
+-- +goose Down
+DROP FUNCTION ens.domain_snapshot_at(BIGINT);
+
+DROP INDEX ens.domain_records_name_hash_block_index;
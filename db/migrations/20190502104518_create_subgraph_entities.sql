@@ -0,0 +1,224 @@
+-- +goose Up
+-- The entities of the ENS subgraph's schema, derived from the indexed events and domain records
+-- Each entity can be read as of a block from its _at function, or as of the latest synced header from its view
+
+-- Domains as of the given block, with their label preimages where they are known
+-- +goose StatementBegin
+CREATE FUNCTION ens.subgraph_domains_at(block BIGINT)
+  RETURNS TABLE (
+    name_hash      VARCHAR(66),
+    full_name      TEXT,
+    label          TEXT,
+    label_hash     VARCHAR(66),
+    parent_hash    VARCHAR(66),
+    owner_addr     VARCHAR(66),
+    resolver_addr  VARCHAR(66),
+    points_to_addr VARCHAR(66),
+    ttl            TEXT,
+    migrated       BOOLEAN,
+    block_number   BIGINT
+  ) AS $$
+  SELECT domains.name_hash,
+         domains.full_name,
+         COALESCE(labels.label, ''),
+         domains.label_hash,
+         domains.parent_hash,
+         domains.owner_addr,
+         COALESCE(domains.resolver_addr, ''),
+         COALESCE(domains.points_to_addr, ''),
+         COALESCE(domains.ttl, ''),
+         domains.migrated,
+         domains.block_number
+  FROM (
+    SELECT DISTINCT ON (domain_records.name_hash) domain_records.*
+    FROM ens.domain_records
+    WHERE domain_records.block_number <= $1
+    ORDER BY domain_records.name_hash, domain_records.block_number DESC
+  ) AS domains
+  LEFT JOIN ens.labels ON labels.label_hash = domains.label_hash
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- The latest domains are read from ens.current_domains rather than from their most recent records
+CREATE VIEW ens.subgraph_domains AS
+  SELECT current_domains.name_hash,
+         current_domains.full_name,
+         COALESCE(labels.label, '') AS label,
+         current_domains.label_hash,
+         current_domains.parent_hash,
+         current_domains.owner_addr,
+         COALESCE(current_domains.resolver_addr, '') AS resolver_addr,
+         COALESCE(current_domains.points_to_addr, '') AS points_to_addr,
+         COALESCE(current_domains.ttl, '') AS ttl,
+         current_domains.migrated,
+         current_domains.block_number
+  FROM ens.current_domains
+  LEFT JOIN ens.labels ON labels.label_hash = current_domains.label_hash;
+
+-- .eth registrations as of the given block, identified by label hash
+-- The registrant is the latest owner of the registration according to the legacy registrar, the base registrar's
+-- registrations and its token transfers; released names have no registration
+-- +goose StatementBegin
+CREATE FUNCTION ens.subgraph_registrations_at(block BIGINT)
+  RETURNS TABLE (
+    label_hash    VARCHAR(66),
+    label         TEXT,
+    registered_at TEXT,
+    expires       TEXT,
+    registrant    VARCHAR(66),
+    block_number  BIGINT
+  ) AS $$
+  SELECT expiries.label_hash,
+         COALESCE(labels.label, ''),
+         COALESCE(expiries.registered_at::TEXT, ''),
+         COALESCE(expiries.expires::TEXT, ''),
+         COALESCE(registrants.registrant, ''),
+         expiries.block_number
+  FROM ens.name_expiries_at($1) AS expiries
+  LEFT JOIN ens.labels ON labels.label_hash = expiries.label_hash
+  LEFT JOIN (
+    SELECT DISTINCT ON (events.label_hash) events.label_hash, events.registrant
+    FROM (
+      SELECT header_id, tx_idx, log_idx, hash AS label_hash, owner AS registrant
+      FROM ens.hash_registered
+      UNION ALL
+      SELECT header_id, tx_idx, log_idx, label_hash, owner
+      FROM ens.name_registered
+      UNION ALL
+      SELECT header_id, tx_idx, log_idx, label_hash, to_addr
+      FROM ens.registrar_transfer
+    ) AS events
+    JOIN public.headers ON headers.id = events.header_id
+    WHERE headers.block_number <= $1
+    ORDER BY events.label_hash, headers.block_number DESC, events.tx_idx DESC, events.log_idx DESC
+  ) AS registrants ON registrants.label_hash = expiries.label_hash
+  WHERE expiries.status <> 'released'
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+CREATE VIEW ens.subgraph_registrations AS
+  SELECT * FROM ens.subgraph_registrations_at((SELECT MAX(block_number) FROM public.headers));
+
+-- Every address that had owned a domain or a registration as of the given block, lower cased
+-- +goose StatementBegin
+CREATE FUNCTION ens.subgraph_accounts_at(block BIGINT)
+  RETURNS TABLE (
+    address TEXT
+  ) AS $$
+  SELECT LOWER(domain_records.owner_addr)
+  FROM ens.domain_records
+  WHERE domain_records.block_number <= $1
+  UNION
+  SELECT LOWER(registrations.registrant)
+  FROM ens.subgraph_registrations_at($1) AS registrations
+  WHERE registrations.registrant <> ''
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+CREATE VIEW ens.subgraph_accounts AS
+  SELECT * FROM ens.subgraph_accounts_at((SELECT MAX(block_number) FROM public.headers));
+
+-- Looks up the records of a node while it used a resolver, for the resolvers of a resolver id
+CREATE INDEX domain_records_name_hash_resolver_index ON ens.domain_records (name_hash, LOWER(resolver_addr), block_number DESC);
+
+-- Every resolver that had been set on a node as of the given block, identified by its lower cased address and the node
+-- The address and content hash are taken from the node's most recent record while it used the resolver
+-- Given a lower cased resolver address and a node, only the records of that node while it used that resolver are read
+-- +goose StatementBegin
+CREATE FUNCTION ens.subgraph_resolvers_at(block BIGINT, resolver VARCHAR(66) DEFAULT NULL, node VARCHAR(66) DEFAULT NULL)
+  RETURNS TABLE (
+    id             TEXT,
+    resolver_addr  VARCHAR(66),
+    name_hash      VARCHAR(66),
+    points_to_addr VARCHAR(66),
+    contenthash    TEXT,
+    block_number   BIGINT
+  ) AS $$
+  SELECT DISTINCT ON (LOWER(domain_records.resolver_addr), domain_records.name_hash)
+         LOWER(domain_records.resolver_addr) || '-' || domain_records.name_hash,
+         domain_records.resolver_addr,
+         domain_records.name_hash,
+         COALESCE(domain_records.points_to_addr, ''),
+         COALESCE(domain_records.contenthash, ''),
+         domain_records.block_number
+  FROM ens.domain_records
+  WHERE domain_records.block_number <= $1
+    AND domain_records.resolver_addr <> ''
+    AND domain_records.resolver_addr <> '0x0000000000000000000000000000000000000000'
+    AND ($2 IS NULL OR LOWER(domain_records.resolver_addr) = $2)
+    AND ($3 IS NULL OR domain_records.name_hash = $3)
+  ORDER BY LOWER(domain_records.resolver_addr), domain_records.name_hash, domain_records.block_number DESC
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+CREATE VIEW ens.subgraph_resolvers AS
+  SELECT * FROM ens.subgraph_resolvers_at((SELECT MAX(block_number) FROM public.headers));
+
+-- Finds the ENSRegistryWithFallback's NewOwner events for a node, which migrated it off the original registry
+CREATE INDEX new_owner_subnode_index ON ens.new_owner (subnode);
+
+-- The events of both registries, identified like the subgraph's by their block number and log index
+-- name_hash is the node whose owner, resolver or TTL the event set, and parent_hash the node a NewOwner event created it under
+-- Like the subgraph, the original registry's events for a node are left out once the ENSRegistryWithFallback
+-- (0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e on every network) has set its owner
+CREATE VIEW ens.subgraph_domain_events AS
+  SELECT headers.block_number || '-' || events.log_idx AS id,
+         events.type,
+         events.name_hash,
+         events.parent_hash,
+         events.owner_addr,
+         events.resolver_addr,
+         events.ttl,
+         COALESCE(events.raw_log ->> 'transactionHash', '') AS transaction_hash,
+         headers.block_number,
+         events.tx_idx,
+         events.log_idx
+  FROM (
+    SELECT header_id, tx_idx, log_idx, raw_log, 'Transfer' AS type, node AS name_hash, '' AS parent_hash,
+           owner AS owner_addr, '' AS resolver_addr, '' AS ttl
+    FROM ens.transfer
+    UNION ALL
+    SELECT header_id, tx_idx, log_idx, raw_log, 'NewOwner', subnode, node, owner, '', ''
+    FROM ens.new_owner
+    UNION ALL
+    SELECT header_id, tx_idx, log_idx, raw_log, 'NewResolver', node, '', '', resolver, ''
+    FROM ens.new_resolver
+    UNION ALL
+    SELECT header_id, tx_idx, log_idx, raw_log, 'NewTTL', node, '', '', '', ttl::TEXT
+    FROM ens.new_ttl
+  ) AS events
+  JOIN public.headers ON headers.id = events.header_id
+  WHERE LOWER(events.raw_log ->> 'address') = '0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e'
+     OR NOT EXISTS (
+       SELECT 1
+       FROM ens.new_owner AS migrations
+       JOIN public.headers AS migration_headers ON migration_headers.id = migrations.header_id
+       WHERE migrations.subnode = events.name_hash
+         AND LOWER(migrations.raw_log ->> 'address') = '0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e'
+         AND (migration_headers.block_number, migrations.tx_idx, migrations.log_idx) <
+             (headers.block_number, events.tx_idx, events.log_idx)
+     );
+
+
+-- +goose Down
+DROP VIEW ens.subgraph_domain_events;
+
+DROP INDEX ens.new_owner_subnode_index;
+
+DROP VIEW ens.subgraph_resolvers;
+
+DROP FUNCTION ens.subgraph_resolvers_at(BIGINT, VARCHAR(66), VARCHAR(66));
+
+DROP INDEX ens.domain_records_name_hash_resolver_index;
+
+DROP VIEW ens.subgraph_accounts;
+
+DROP FUNCTION ens.subgraph_accounts_at(BIGINT);
+
+DROP VIEW ens.subgraph_registrations;
+
+DROP FUNCTION ens.subgraph_registrations_at(BIGINT);
+
+DROP VIEW ens.subgraph_domains;
+
+DROP FUNCTION ens.subgraph_domains_at(BIGINT);
@@ -0,0 +1,53 @@
+-- +goose Up
+-- Address lookups compare addresses case-insensitively
+CREATE INDEX domain_records_owner_index ON ens.domain_records (LOWER(owner_addr), block_number);
+CREATE INDEX domain_records_points_to_index ON ens.domain_records (LOWER(points_to_addr), block_number);
+CREATE INDEX current_domains_lower_owner_index ON ens.current_domains (LOWER(owner_addr));
+CREATE INDEX current_domains_lower_points_to_index ON ens.current_domains (LOWER(points_to_addr));
+
+-- The state as of the given block of every node owned by or resolving to the given address at that block
+-- Only nodes that have had the address as owner or resolved address at or before the block are considered,
+-- so the lookup reads the address indexes instead of scanning the whole snapshot
+-- +goose StatementBegin
+CREATE FUNCTION ens.address_snapshot_at(address VARCHAR(66), block BIGINT)
+  RETURNS TABLE (
+    name_hash      VARCHAR(66),
+    full_name      TEXT,
+    parent_hash    VARCHAR(66),
+    owner_addr     VARCHAR(66),
+    resolver_addr  VARCHAR(66),
+    points_to_addr VARCHAR(66),
+    ttl            TEXT,
+    block_number   BIGINT
+  ) AS $$
+  WITH candidates AS (
+    SELECT domain_records.name_hash
+    FROM ens.domain_records
+    WHERE LOWER(domain_records.owner_addr) = LOWER($1) AND domain_records.block_number <= $2
+    UNION
+    SELECT domain_records.name_hash
+    FROM ens.domain_records
+    WHERE LOWER(domain_records.points_to_addr) = LOWER($1) AND domain_records.block_number <= $2
+  )
+  SELECT records.name_hash,
+         records.full_name,
+         records.parent_hash,
+         records.owner_addr,
+         COALESCE(records.resolver_addr, ''),
+         COALESCE(records.points_to_addr, ''),
+         COALESCE(records.ttl, ''),
+         records.block_number
+  FROM candidates
+  CROSS JOIN LATERAL ens.domain_record_at(candidates.name_hash, $2) AS records
+  WHERE LOWER(records.owner_addr) = LOWER($1) OR LOWER(records.points_to_addr) = LOWER($1)
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+
+-- +goose Down
+DROP FUNCTION ens.address_snapshot_at(VARCHAR(66), BIGINT);
+
+DROP INDEX ens.current_domains_lower_points_to_index;
+DROP INDEX ens.current_domains_lower_owner_index;
+DROP INDEX ens.domain_records_points_to_index;
+DROP INDEX ens.domain_records_owner_index;
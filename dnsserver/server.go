@@ -0,0 +1,265 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package dnsserver serves the DNS records of ENS names from the indexed tables, answering queries for them over UDP and exporting
+// them as zone files
+// A configurable suffix stands in for .eth, so that with the suffix eth.local a query for vitalik.eth.local. is answered from vitalik.eth
+package dnsserver
+
+import (
+	"database/sql"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/ens_transformers/transformers/shared/dns"
+)
+
+const (
+	// The TTL of the TXT records made from a name's contenthash and address when its registry TTL is 0
+	defaultTTL  = 300
+	zeroAddress = "0x0000000000000000000000000000000000000000"
+	ensSuffix   = "eth."
+	// Names with more labels than this are refused rather than looked up
+	maxLabels = 32
+	// The number of queries answered at once, and the number read while every worker is busy; queries read while the queue
+	// is full are dropped, and left for the client to retry
+	workers     = 64
+	queueLength = 1024
+)
+
+// Answers are taken from the resource records in the DNS zone of the name's closest ENS node on that node's current resolver,
+// falling back to a CNAME of the name when it has no records of the queried type
+// TXT queries are also answered with a dnslink= record for the name's contenthash and an eth-addr= record for the address it points to
+type Server struct {
+	suffix string
+	repository.ENSRepository
+	repository.DNSRepository
+}
+
+func NewServer(db *postgres.DB, suffix string) *Server {
+	return &Server{
+		suffix:        strings.ToLower(strings.Trim(suffix, ".")) + ".",
+		ENSRepository: repository.NewENSRepository(db),
+		DNSRepository: repository.NewDNSRepository(db),
+	}
+}
+
+type packet struct {
+	query []byte
+	addr  net.Addr
+}
+
+// Answers the queries read from conn on a fixed pool of workers until reading from it fails
+func (s *Server) Serve(conn net.PacketConn) error {
+	packets := make(chan packet, queueLength)
+	defer close(packets)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for p := range packets {
+				response := s.answer(p.query)
+				if response == nil {
+					continue
+				}
+				_, err := conn.WriteTo(response, p.addr)
+				if err != nil {
+					log.Error("failed to write DNS response ", err)
+				}
+			}
+		}()
+	}
+
+	buffer := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return err
+		}
+		select {
+		case packets <- packet{query: append([]byte{}, buffer[:n]...), addr: addr}:
+		default:
+			log.Debug("dropping DNS query from ", addr, ", the queue is full")
+		}
+	}
+}
+
+// Returns the wire format response to a query, or nil if no response should be sent
+func (s *Server) answer(query []byte) []byte {
+	request, err := dns.ParseMessage(query)
+	if err != nil && len(query) < 12 {
+		return nil
+	}
+	if request.Response {
+		return nil
+	}
+	response := dns.Message{
+		ID:               request.ID,
+		Response:         true,
+		Opcode:           request.Opcode,
+		RecursionDesired: request.RecursionDesired,
+		Questions:        request.Questions,
+	}
+	switch {
+	case err != nil || len(request.Questions) != 1:
+		response.Rcode = dns.RcodeFormatError
+		response.Questions = nil
+	case request.Opcode != dns.OpcodeQuery || request.Questions[0].Class != dns.ClassINET:
+		response.Rcode = dns.RcodeNotImplemented
+	default:
+		response.Rcode, response.Answers = s.resolve(request.Questions[0])
+		response.Authoritative = response.Rcode == dns.RcodeSuccess || response.Rcode == dns.RcodeNameError
+	}
+
+	packed, err := response.Pack()
+	if err == nil && len(packed) > dns.MaxUDPMessageLength {
+		response.Truncated = true
+		response.Answers = nil
+		packed, err = response.Pack()
+	}
+	if err != nil {
+		log.Error("failed to pack DNS response ", err)
+		return nil
+	}
+	return packed
+}
+
+// Returns the response code and answers for a question
+// Names outside the suffix are refused, and names that are neither ENS nodes nor in the zone of one don't exist
+func (s *Server) resolve(question dns.Question) (uint8, []dns.ResourceRecord) {
+	name := strings.ToLower(question.Name)
+	if name != s.suffix && !strings.HasSuffix(name, "."+s.suffix) {
+		return dns.RcodeRefused, nil
+	}
+	// Names with escaped characters can't be normalized ENS names
+	if strings.Contains(name, "\\") {
+		return dns.RcodeNameError, nil
+	}
+	ensName := strings.TrimSuffix(name, s.suffix) + ensSuffix
+	if strings.Count(ensName, ".") > maxLabels {
+		return dns.RcodeRefused, nil
+	}
+	nameNode := utils.NameHash(strings.TrimSuffix(ensName, "."))
+
+	record, node, err := s.closestRecord(strings.TrimSuffix(ensName, "."))
+	if err == sql.ErrNoRows {
+		return dns.RcodeNameError, nil
+	}
+	if err != nil {
+		log.Error("failed to look up ", ensName, " ", err)
+		return dns.RcodeServerFailure, nil
+	}
+	var zone []models.DNSRecordModel
+	if record.ResolverAddr != "" && record.ResolverAddr != zeroAddress {
+		zone, err = s.GetZoneRecords(record.ResolverAddr, node, ensName)
+		if err != nil {
+			log.Error("failed to look up the zone of ", ensName, " ", err)
+			return dns.RcodeServerFailure, nil
+		}
+	}
+	isNode := node == nameNode
+	if !isNode && len(zone) == 0 {
+		return dns.RcodeNameError, nil
+	}
+
+	answers := zoneAnswers(question, zone, question.Type)
+	if len(answers) == 0 && question.Type != dns.TypeCNAME {
+		answers = zoneAnswers(question, zone, dns.TypeCNAME)
+		if len(answers) > 0 {
+			return dns.RcodeSuccess, answers
+		}
+	}
+	if isNode && question.Type == dns.TypeTXT {
+		answers = append(answers, recordTXTs(question.Name, *record, defaultTTL)...)
+	}
+
+	return dns.RcodeSuccess, answers
+}
+
+// Gets the current record of the name, or of its closest ancestor that has one, and that record's node, in a single query
+// Returns sql.ErrNoRows if neither the name nor any of its ancestors are ENS nodes
+func (s *Server) closestRecord(name string) (*models.DomainModel, string, error) {
+	labels := utils.SplitName(name)
+	nodes := make([]string, 0, len(labels))
+	for i := range labels {
+		nodes = append(nodes, utils.NameHash(strings.Join(labels[i:], ".")))
+	}
+	record, err := s.GetClosestCurrentRecord(nodes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return record, record.NameHash, nil
+}
+
+// Converts the zone's records of the type into answers to the question, owned by the name as it was asked
+func zoneAnswers(question dns.Question, zone []models.DNSRecordModel, recordType uint16) []dns.ResourceRecord {
+	var answers []dns.ResourceRecord
+	for _, record := range zone {
+		if uint16(record.Type) != recordType || uint16(record.Class) != dns.ClassINET {
+			continue
+		}
+		rdata, err := hexutil.Decode(record.Rdata)
+		if err != nil {
+			log.Error("invalid rdata in the zone of ", record.Name, " ", err)
+			continue
+		}
+		answers = append(answers, dns.ResourceRecord{
+			Name:  question.Name,
+			Type:  recordType,
+			Class: dns.ClassINET,
+			TTL:   uint32(record.TTL),
+			Rdata: rdata,
+		})
+	}
+
+	return answers
+}
+
+// Makes TXT records of the name's contenthash, as a dnslink (e.g. dnslink=/ipfs/<cid> for ipfs://<cid>), and of the address it points to
+// Their TTL is the name's registry TTL, or the fallback TTL if that is 0
+func recordTXTs(name string, record models.DomainModel, fallbackTTL uint32) []dns.ResourceRecord {
+	ttl, err := strconv.ParseUint(record.TTL, 10, 32)
+	if err != nil || ttl == 0 {
+		ttl = uint64(fallbackTTL)
+	}
+	var texts []string
+	if uri := record.EffectiveContentURI; uri != "" {
+		texts = append(texts, "dnslink=/"+strings.Replace(uri, "://", "/", 1))
+	}
+	if record.PointsToAddr != "" && record.PointsToAddr != zeroAddress {
+		texts = append(texts, "eth-addr="+record.PointsToAddr)
+	}
+
+	answers := make([]dns.ResourceRecord, 0, len(texts))
+	for _, text := range texts {
+		answers = append(answers, dns.ResourceRecord{
+			Name:  name,
+			Type:  dns.TypeTXT,
+			Class: dns.ClassINET,
+			TTL:   uint32(ttl),
+			Rdata: dns.TXTRdata(text),
+		})
+	}
+	return answers
+}
@@ -0,0 +1,217 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dnsserver_test
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/dnsserver"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/ens_transformers/transformers/shared/dns"
+)
+
+var _ = Describe("Server", func() {
+	var db *postgres.DB
+	var conn net.PacketConn
+	address := "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"
+	resolverAddr := "0x5FfC014343cd971B7eb70732021E26C35B744cc4"
+	vitalikNode := utils.NameHash("vitalik.eth")
+
+	// Sets an RRset of the given type with a single record on vitalik.eth's resolver
+	setRecord := func(headerID int64, name string, recordType uint16, rdata string) {
+		var recordID int64
+		err := db.Get(&recordID, `INSERT INTO ens.dns_record_changed (header_id, resolver, node, name, resource, record, tx_idx, log_idx)
+			VALUES ($1, $2, $3, $4, $5, '0x', 0, $5) RETURNING id`, headerID, resolverAddr, vitalikNode, name, recordType)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(`INSERT INTO ens.dns_resource_records (dns_record_changed_id, name, type, class, ttl, rdata)
+			VALUES ($1, $2, $3, 1, 3600, $4)`, recordID, name, recordType, rdata)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		headerIds := test_helpers.CreateHeaders(db, 3327420, 3327421)
+		ensRepo := repository.NewENSRepository(db)
+		err := ensRepo.CreateRecord(models.DomainModel{
+			NameHash:    utils.NameHash("eth"),
+			BlockNumber: 3327420,
+			HeaderID:    headerIds[3327420],
+			LabelHash:   utils.LabelHash("eth"),
+			ParentHash:  utils.RootNode,
+			Owner:       address,
+			FullName:    "eth",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		err = ensRepo.CreateRecord(models.DomainModel{
+			NameHash:                 vitalikNode,
+			BlockNumber:              3327421,
+			HeaderID:                 headerIds[3327421],
+			LabelHash:                utils.LabelHash("vitalik"),
+			ParentHash:               utils.NameHash("eth"),
+			Owner:                    address,
+			ResolverAddr:             resolverAddr,
+			PointsToAddr:             address,
+			EffectiveContentProtocol: "ipfs",
+			EffectiveContentURI:      "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4",
+			FullName:                 "vitalik.eth",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		setRecord(headerIds[3327421], "vitalik.eth.", dns.TypeA, "0x01020304")
+		setRecord(headerIds[3327421], "www.vitalik.eth.", dns.TypeAAAA, "0x20010db8000000000000000000000001")
+		// blog.vitalik.eth. CNAME www.vitalik.eth.
+		setRecord(headerIds[3327421], "blog.vitalik.eth.", dns.TypeCNAME, "0x0377777707766974616c696b0365746800")
+
+		conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		go dnsserver.NewServer(db, "eth.local.").Serve(conn)
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		test_helpers.TearDown(db)
+	})
+
+	query := func(name string, recordType uint16) dns.Message {
+		client, err := net.Dial("udp", conn.LocalAddr().String())
+		Expect(err).NotTo(HaveOccurred())
+		defer client.Close()
+		request, err := dns.Message{
+			ID:               0x1234,
+			RecursionDesired: true,
+			Questions:        []dns.Question{{Name: name, Type: recordType, Class: dns.ClassINET}},
+		}.Pack()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(request)
+		Expect(err).NotTo(HaveOccurred())
+
+		buffer := make([]byte, dns.MaxUDPMessageLength)
+		err = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		n, err := client.Read(buffer)
+		Expect(err).NotTo(HaveOccurred())
+		response, err := dns.ParseMessage(buffer[:n])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.ID).To(Equal(uint16(0x1234)))
+		Expect(response.Response).To(BeTrue())
+
+		return response
+	}
+
+	It("Answers A and AAAA queries from the zone on the name's resolver", func() {
+		response := query("Vitalik.eth.local.", dns.TypeA)
+		Expect(response.Rcode).To(Equal(dns.RcodeSuccess))
+		Expect(response.Authoritative).To(BeTrue())
+		Expect(response.Answers).To(Equal([]dns.ResourceRecord{
+			{Name: "Vitalik.eth.local.", Type: dns.TypeA, Class: dns.ClassINET, TTL: 3600, Rdata: []byte{1, 2, 3, 4}},
+		}))
+
+		response = query("www.vitalik.eth.local.", dns.TypeAAAA)
+		Expect(response.Rcode).To(Equal(dns.RcodeSuccess))
+		Expect(len(response.Answers)).To(Equal(1))
+		Expect(net.IP(response.Answers[0].Rdata).String()).To(Equal("2001:db8::1"))
+	})
+
+	It("Answers with a CNAME when the name has no records of the queried type", func() {
+		response := query("blog.vitalik.eth.local.", dns.TypeA)
+
+		Expect(response.Rcode).To(Equal(dns.RcodeSuccess))
+		Expect(len(response.Answers)).To(Equal(1))
+		Expect(response.Answers[0].Type).To(Equal(dns.TypeCNAME))
+		target, err := dns.ParseName(response.Answers[0].Rdata)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(Equal("www.vitalik.eth."))
+	})
+
+	It("Answers TXT queries with the name's contenthash as a dnslink and the address it points to", func() {
+		response := query("vitalik.eth.local.", dns.TypeTXT)
+
+		Expect(response.Rcode).To(Equal(dns.RcodeSuccess))
+		Expect(response.Answers).To(Equal([]dns.ResourceRecord{{
+			Name:  "vitalik.eth.local.",
+			Type:  dns.TypeTXT,
+			Class: dns.ClassINET,
+			TTL:   300,
+			Rdata: dns.TXTRdata("dnslink=/ipfs/QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"),
+		}, {
+			Name:  "vitalik.eth.local.",
+			Type:  dns.TypeTXT,
+			Class: dns.ClassINET,
+			TTL:   300,
+			Rdata: dns.TXTRdata("eth-addr=" + address),
+		}}))
+	})
+
+	It("Answers without records for names that exist without records of the queried type", func() {
+		response := query("eth.local.", dns.TypeA)
+
+		Expect(response.Rcode).To(Equal(dns.RcodeSuccess))
+		Expect(response.Answers).To(BeEmpty())
+	})
+
+	It("Answers that names which are neither ENS nodes nor in a zone don't exist", func() {
+		response := query("unknown.vitalik.eth.local.", dns.TypeA)
+		Expect(response.Rcode).To(Equal(dns.RcodeNameError))
+		Expect(response.Authoritative).To(BeTrue())
+
+		response = query("unknown.eth.local.", dns.TypeA)
+		Expect(response.Rcode).To(Equal(dns.RcodeNameError))
+	})
+
+	It("Refuses names outside of the suffix", func() {
+		response := query("vitalik.eth.", dns.TypeA)
+
+		Expect(response.Rcode).To(Equal(dns.RcodeRefused))
+		Expect(response.Answers).To(BeEmpty())
+	})
+
+	It("Refuses names with too many labels", func() {
+		response := query(strings.Repeat("a.", 40)+"vitalik.eth.local.", dns.TypeA)
+
+		Expect(response.Rcode).To(Equal(dns.RcodeRefused))
+		Expect(response.Answers).To(BeEmpty())
+	})
+
+	It("Rejects malformed queries with a format error", func() {
+		client, err := net.Dial("udp", conn.LocalAddr().String())
+		Expect(err).NotTo(HaveOccurred())
+		defer client.Close()
+		_, err = client.Write(hexutil.MustDecode("0x123401000001000000000000076578616d706c65"))
+		Expect(err).NotTo(HaveOccurred())
+
+		buffer := make([]byte, dns.MaxUDPMessageLength)
+		err = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		n, err := client.Read(buffer)
+		Expect(err).NotTo(HaveOccurred())
+		response, err := dns.ParseMessage(buffer[:n])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.ID).To(Equal(uint16(0x1234)))
+		Expect(response.Rcode).To(Equal(dns.RcodeFormatError))
+		Expect(response.Questions).To(BeEmpty())
+	})
+})
@@ -0,0 +1,375 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dnsserver
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/ens_transformers/transformers/shared/dns"
+)
+
+// The SOA timers used when a zone doesn't configure its own (RFC 1912 section 2.2)
+const (
+	defaultRefresh = 3600
+	defaultRetry   = 600
+	defaultExpire  = 604800
+)
+
+// The settings of a zone file exported for an ENS name and the names below it, read from a [[zone]] table of the config file
+type ZoneConfig struct {
+	// The ENS name at the apex of the zone, e.g. eth
+	Parent string `mapstructure:"parent"`
+	// The DNS name the parent is exported as, e.g. eth.link.; defaults to the parent's own name
+	Origin string `mapstructure:"origin"`
+	File   string `mapstructure:"file"`
+	// The TTL of the SOA record and of the TXT records made from names whose registry TTL is 0
+	TTL uint32 `mapstructure:"ttl"`
+	// Writes fully qualified owner names instead of names relative to a $ORIGIN directive
+	Absolute bool `mapstructure:"absolute"`
+	// Leaves out the SOA record, e.g. for files $INCLUDEd into a zone that has its own
+	OmitSOA bool `mapstructure:"omit_soa"`
+	// The SOA record's fields, defaulting to ns1.<origin>, hostmaster.<origin> and the latest block any of the zone's names or
+	// DNS records were changed at, counting deleted records and cleared zones, so that the serial only increases when the zone does
+	PrimaryNS string `mapstructure:"primary_ns"`
+	Contact   string `mapstructure:"contact"`
+	Serial    uint32 `mapstructure:"serial"`
+	Refresh   uint32 `mapstructure:"refresh"`
+	Retry     uint32 `mapstructure:"retry"`
+	Expire    uint32 `mapstructure:"expire"`
+	Minimum   uint32 `mapstructure:"minimum"`
+}
+
+func (c ZoneConfig) withDefaults() (ZoneConfig, error) {
+	c.Parent = strings.ToLower(strings.Trim(c.Parent, "."))
+	if c.Parent == "" {
+		return c, fmt.Errorf("zone has no parent name")
+	}
+	if c.Origin == "" {
+		c.Origin = c.Parent
+	}
+	c.Origin = strings.TrimSuffix(c.Origin, ".") + "."
+	if c.TTL == 0 {
+		c.TTL = defaultTTL
+	}
+	if c.PrimaryNS == "" {
+		c.PrimaryNS = "ns1." + c.Origin
+	}
+	if c.Contact == "" {
+		c.Contact = "hostmaster." + c.Origin
+	}
+	if c.Refresh == 0 {
+		c.Refresh = defaultRefresh
+	}
+	if c.Retry == 0 {
+		c.Retry = defaultRetry
+	}
+	if c.Expire == 0 {
+		c.Expire = defaultExpire
+	}
+	if c.Minimum == 0 {
+		c.Minimum = c.TTL
+	}
+
+	return c, nil
+}
+
+// Exports the DNS records of ENS names as zone files in the master file format (RFC 1035 section 5)
+// A zone holds the records of its parent name and every name below it: the TXT records the server answers with for each name,
+// and the resource records of each name's DNS zone on its current resolver, with the parent's name replaced by the origin
+type ZoneExporter struct {
+	db *postgres.DB
+	repository.ENSRepository
+	repository.DNSRepository
+}
+
+func NewZoneExporter(db *postgres.DB) *ZoneExporter {
+	return &ZoneExporter{
+		db:            db,
+		ENSRepository: repository.NewENSRepository(db),
+		DNSRepository: repository.NewDNSRepository(db),
+	}
+}
+
+// Writes the zone as of the latest synced header
+// As with the server, a name is only taken from the DNS zone of its closest ENS node, and names that can't be written in
+// presentation format (e.g. those with unknown labels) are left out. The resolvers' own SOA records are left out too, since a zone
+// has a single SOA record at its apex
+func (e *ZoneExporter) Export(w io.Writer, config ZoneConfig) error {
+	config, err := config.withDefaults()
+	if err != nil {
+		return err
+	}
+	parentNode := utils.NameHash(config.Parent)
+	parent, err := e.GetCurrentRecord(parentNode)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%s is not an ENS name", config.Parent)
+	}
+	if err != nil {
+		return err
+	}
+	var latest int64
+	err = e.db.Get(&latest, `SELECT COALESCE(MAX(block_number), 0) FROM public.headers`)
+	if err != nil {
+		return err
+	}
+	descendants, err := e.GetDescendants(parentNode, 0, latest)
+	if err != nil {
+		return err
+	}
+	parent.FullName = config.Parent
+	domains := append([]models.DomainModel{*parent}, descendants...)
+	apex, ok := presentationName(config.Parent)
+	if !ok {
+		return fmt.Errorf("%s can't be written as a DNS name", config.Parent)
+	}
+
+	// The presentation format names of the zone's ENS nodes, e.g. vitalik.eth., and the set of them lower cased
+	owners := make([]string, len(domains))
+	nodes := make(map[string]bool)
+	for i, domain := range domains {
+		owner, ok := presentationName(domain.FullName)
+		if !ok || !ownsName(nil, apex, owner) {
+			log.Debug("leaving ", domain.FullName, " out of the zone of ", config.Parent)
+			continue
+		}
+		owners[i] = owner
+		nodes[strings.ToLower(owner)] = true
+	}
+
+	// The DNS zones of every name on its current resolver are read at once, and grouped by node
+	var serial int64
+	var resolverAddrs, zoneNodes []string
+	for i, domain := range domains {
+		if owners[i] == "" {
+			continue
+		}
+		if domain.BlockNumber > serial {
+			serial = domain.BlockNumber
+		}
+		if domain.ResolverAddr != "" && domain.ResolverAddr != zeroAddress {
+			resolverAddrs = append(resolverAddrs, domain.ResolverAddr)
+			zoneNodes = append(zoneNodes, domain.NameHash)
+		}
+	}
+	rows, err := e.GetZones(resolverAddrs, zoneNodes)
+	if err != nil {
+		return err
+	}
+	zones := make(map[string][]models.DNSRecordModel)
+	for _, row := range rows {
+		zones[row.Node] = append(zones[row.Node], row)
+	}
+	zonesChangedAt, err := e.GetZonesChangedAt(resolverAddrs, zoneNodes)
+	if err != nil {
+		return err
+	}
+	if zonesChangedAt > serial {
+		serial = zonesChangedAt
+	}
+
+	var records []dns.ResourceRecord
+	for i, domain := range domains {
+		if owners[i] == "" {
+			continue
+		}
+		records = append(records, recordTXTs(rename(owners[i], apex, config.Origin), domain, config.TTL)...)
+		if domain.ResolverAddr == "" || domain.ResolverAddr == zeroAddress {
+			continue
+		}
+		for _, row := range zones[domain.NameHash] {
+			if uint16(row.Class) != dns.ClassINET || uint16(row.Type) == dns.TypeSOA || !ownsName(nodes, owners[i], row.Name) {
+				continue
+			}
+			rdata, err := hexutil.Decode(row.Rdata)
+			if err != nil {
+				log.Error("invalid rdata in the zone of ", row.Name, " ", err)
+				continue
+			}
+			records = append(records, dns.ResourceRecord{
+				Name:  rename(row.Name, apex, config.Origin),
+				Type:  uint16(row.Type),
+				Class: dns.ClassINET,
+				TTL:   uint32(row.TTL),
+				Rdata: rdata,
+			})
+		}
+	}
+	if config.Serial == 0 {
+		config.Serial = uint32(serial)
+	}
+
+	return writeZone(w, config, records)
+}
+
+// A record of a zone file with the labels its owner has below the origin, its type and its rdata, for sorting
+type zoneLine struct {
+	labels     []string
+	recordType uint16
+	rdata      string
+	text       string
+}
+
+// Writes the records of a zone, whose names are all at or below its origin, in canonical order so that exports can be diffed:
+// names are sorted by their labels from the origin down, case-insensitively, then by type and rdata. Duplicate records are dropped
+func writeZone(w io.Writer, config ZoneConfig, records []dns.ResourceRecord) error {
+	originLabels := len(splitName(config.Origin))
+	lines := make([]zoneLine, 0, len(records))
+	for _, record := range records {
+		labels := splitName(record.Name)
+		labels = labels[:len(labels)-originLabels]
+		rdata := dns.FormatRdata(record.Type, record.Rdata)
+		lines = append(lines, zoneLine{
+			labels:     labels,
+			recordType: record.Type,
+			rdata:      rdata,
+			text:       fmt.Sprintf("%s\t%d\tIN\t%s\t%s\n", ownerName(labels, config), record.TTL, dns.TypeString(record.Type), rdata),
+		})
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := lines[i].labels, lines[j].labels
+		for k := 1; k <= len(a) && k <= len(b); k++ {
+			if x, y := strings.ToLower(a[len(a)-k]), strings.ToLower(b[len(b)-k]); x != y {
+				return x < y
+			}
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		if lines[i].recordType != lines[j].recordType {
+			return lines[i].recordType < lines[j].recordType
+		}
+		if lines[i].rdata != lines[j].rdata {
+			return lines[i].rdata < lines[j].rdata
+		}
+		return lines[i].text < lines[j].text
+	})
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "; Zone of the ENS name %s and the names below it\n", config.Parent)
+	if !config.Absolute {
+		fmt.Fprintf(&builder, "$ORIGIN %s\n", config.Origin)
+	}
+	fmt.Fprintf(&builder, "$TTL %d\n", config.TTL)
+	if !config.OmitSOA {
+		fmt.Fprintf(&builder, "%s\t%d\tIN\tSOA\t%s %s %d %d %d %d %d\n", ownerName(nil, config), config.TTL,
+			config.PrimaryNS, config.Contact, config.Serial, config.Refresh, config.Retry, config.Expire, config.Minimum)
+	}
+	for i, line := range lines {
+		if i > 0 && line.text == lines[i-1].text {
+			continue
+		}
+		builder.WriteString(line.text)
+	}
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+// Writes an owner from its labels below the origin, relative to the $ORIGIN unless the zone is written with absolute names
+func ownerName(labels []string, config ZoneConfig) string {
+	if config.Absolute {
+		return qualify(labels, config.Origin)
+	}
+	if len(labels) == 0 {
+		return "@"
+	}
+	return strings.Join(labels, ".")
+}
+
+// Converts an ENS name into a fully qualified name in presentation format, e.g. vitalik.eth into vitalik.eth.
+// Returns false if the name has unknown labels or is too long for DNS
+func presentationName(name string) (string, bool) {
+	var labels []string
+	for _, label := range utils.SplitName(name) {
+		if strings.HasPrefix(label, "[") {
+			return "", false
+		}
+		labels = append(labels, dns.EscapeLabel([]byte(label)))
+	}
+	owner := strings.Join(labels, ".") + "."
+	if _, err := dns.EncodeName(owner); err != nil {
+		return "", false
+	}
+
+	return owner, true
+}
+
+// Reports whether a name belongs to the ENS node: whether it is the node's name or a name below it, with no other ENS node
+// between them
+func ownsName(nodes map[string]bool, node, name string) bool {
+	nodeLabels, labels := splitName(node), splitName(name)
+	below := len(labels) - len(nodeLabels)
+	if below < 0 || !strings.EqualFold(strings.Join(labels[below:], "."), strings.Join(nodeLabels, ".")) {
+		return false
+	}
+	for i := 0; i < below; i++ {
+		if nodes[strings.ToLower(strings.Join(labels[i:], "."))+"."] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Replaces the parent a name is at or below with the origin, e.g. www.vitalik.eth. into www.vitalik.eth.link. for eth. and eth.link.
+func rename(name, parent, origin string) string {
+	labels := splitName(name)
+
+	return qualify(labels[:len(labels)-len(splitName(parent))], origin)
+}
+
+// Joins labels onto a fully qualified name, e.g. www and vitalik onto eth. as www.vitalik.eth.
+func qualify(labels []string, name string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	return strings.Join(labels, ".") + "." + strings.TrimPrefix(name, ".")
+}
+
+// Splits a name in presentation format into its labels, keeping their escapes, e.g. a\.b.eth. into a\.b and eth
+func splitName(name string) []string {
+	var labels []string
+	start := 0
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '\\':
+			i++
+		case '.':
+			if i > start {
+				labels = append(labels, name[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(name) {
+		labels = append(labels, name[start:])
+	}
+
+	return labels
+}
@@ -0,0 +1,184 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dnsserver_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/dnsserver"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/ens_transformers/transformers/shared/dns"
+)
+
+var _ = Describe("ZoneExporter", func() {
+	var db *postgres.DB
+	var exporter *dnsserver.ZoneExporter
+	address := "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"
+	resolverAddr := "0x5FfC014343cd971B7eb70732021E26C35B744cc4"
+	vitalikNode := utils.NameHash("vitalik.eth")
+
+	// Sets an RRset of the given type with a single record on vitalik.eth's resolver
+	setRecord := func(headerID int64, logIdx int, name string, recordType uint16, rdata string) {
+		var recordID int64
+		err := db.Get(&recordID, `INSERT INTO ens.dns_record_changed (header_id, resolver, node, name, resource, record, tx_idx, log_idx)
+			VALUES ($1, $2, $3, $4, $5, '0x', 0, $6) RETURNING id`, headerID, resolverAddr, vitalikNode, name, recordType, logIdx)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(`INSERT INTO ens.dns_resource_records (dns_record_changed_id, name, type, class, ttl, rdata)
+			VALUES ($1, $2, $3, 1, 3600, $4)`, recordID, name, recordType, rdata)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		exporter = dnsserver.NewZoneExporter(db)
+		headerIds := test_helpers.CreateHeaders(db, 3327420, 3327421)
+		ensRepo := repository.NewENSRepository(db)
+		records := []models.DomainModel{{
+			NameHash:    utils.NameHash("eth"),
+			BlockNumber: 3327420,
+			HeaderID:    headerIds[3327420],
+			LabelHash:   utils.LabelHash("eth"),
+			ParentHash:  utils.RootNode,
+			Owner:       address,
+			FullName:    "eth",
+		}, {
+			NameHash:                 vitalikNode,
+			BlockNumber:              3327421,
+			HeaderID:                 headerIds[3327421],
+			LabelHash:                utils.LabelHash("vitalik"),
+			ParentHash:               utils.NameHash("eth"),
+			Owner:                    address,
+			ResolverAddr:             resolverAddr,
+			PointsToAddr:             address,
+			TTL:                      "60",
+			EffectiveContentProtocol: "ipfs",
+			EffectiveContentURI:      "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4",
+			FullName:                 "vitalik.eth",
+		}, {
+			NameHash:    utils.NameHash("sub.vitalik.eth"),
+			BlockNumber: 3327421,
+			HeaderID:    headerIds[3327421],
+			LabelHash:   utils.LabelHash("sub"),
+			ParentHash:  vitalikNode,
+			Owner:       address,
+			FullName:    "sub.vitalik.eth",
+		}, {
+			NameHash:     utils.NameHash("unknown.eth"),
+			BlockNumber:  3327421,
+			HeaderID:     headerIds[3327421],
+			LabelHash:    utils.LabelHash("unknown"),
+			ParentHash:   utils.NameHash("eth"),
+			Owner:        address,
+			PointsToAddr: address,
+			FullName:     utils.UnknownLabel(utils.LabelHash("unknown")) + ".eth",
+		}}
+		for _, record := range records {
+			err := ensRepo.CreateRecord(record)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		setRecord(headerIds[3327420], 0, "vitalik.eth.", dns.TypeA, "0x01020304")
+		setRecord(headerIds[3327420], 1, "www.vitalik.eth.", dns.TypeAAAA, "0x20010db8000000000000000000000001")
+		// blog.vitalik.eth. CNAME www.vitalik.eth.
+		setRecord(headerIds[3327420], 2, "blog.vitalik.eth.", dns.TypeCNAME, "0x0377777707766974616c696b0365746800")
+		// Names that belong to another ENS node, or aren't below vitalik.eth at all, and the resolver's own SOA record
+		setRecord(headerIds[3327420], 3, "www.sub.vitalik.eth.", dns.TypeA, "0x01020304")
+		setRecord(headerIds[3327420], 4, "example.com.", dns.TypeA, "0x01020304")
+		setRecord(headerIds[3327420], 5, "vitalik.eth.", dns.TypeSOA,
+			"0x036e7331076578616d706c6503636f6d000a686f73746d6173746572076578616d706c6503636f6d000000000100000002000000030000000400000005")
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	It("Writes the records of the parent and the names below it under the origin, in canonical order", func() {
+		var zone bytes.Buffer
+		err := exporter.Export(&zone, dnsserver.ZoneConfig{Parent: "eth", Origin: "eth.link"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zone.String()).To(Equal(`; Zone of the ENS name eth and the names below it
+$ORIGIN eth.link.
+$TTL 300
+@	300	IN	SOA	ns1.eth.link. hostmaster.eth.link. 3327421 3600 600 604800 300
+vitalik	3600	IN	A	1.2.3.4
+vitalik	60	IN	TXT	"dnslink=/ipfs/QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"
+vitalik	60	IN	TXT	"eth-addr=0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"
+blog.vitalik	3600	IN	CNAME	www.vitalik.eth.
+www.vitalik	3600	IN	AAAA	2001:db8::1
+`))
+	})
+
+	It("Takes the serial from the latest block a record of the zone was deleted at", func() {
+		headerIds := test_helpers.CreateHeaders(db, 3327422, 3327422)
+		_, err := db.Exec(`INSERT INTO ens.dns_record_deleted (header_id, resolver, node, name, resource, tx_idx, log_idx)
+			VALUES ($1, $2, $3, 'www.vitalik.eth.', $4, 0, 0)`, headerIds[3327422], resolverAddr, vitalikNode, dns.TypeAAAA)
+		Expect(err).NotTo(HaveOccurred())
+
+		var zone bytes.Buffer
+		err = exporter.Export(&zone, dnsserver.ZoneConfig{Parent: "vitalik.eth"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zone.String()).To(ContainSubstring("@\t300\tIN\tSOA\tns1.vitalik.eth. hostmaster.vitalik.eth. 3327422 "))
+		Expect(zone.String()).NotTo(ContainSubstring("AAAA"))
+	})
+
+	It("Writes absolute names and the configured SOA record", func() {
+		var zone bytes.Buffer
+		err := exporter.Export(&zone, dnsserver.ZoneConfig{
+			Parent:    "vitalik.eth",
+			TTL:       600,
+			Absolute:  true,
+			PrimaryNS: "ns.example.com.",
+			Contact:   "admin.example.com.",
+			Serial:    2019050301,
+			Minimum:   60,
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zone.String()).To(Equal(`; Zone of the ENS name vitalik.eth and the names below it
+$TTL 600
+vitalik.eth.	600	IN	SOA	ns.example.com. admin.example.com. 2019050301 3600 600 604800 60
+vitalik.eth.	3600	IN	A	1.2.3.4
+vitalik.eth.	60	IN	TXT	"dnslink=/ipfs/QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"
+vitalik.eth.	60	IN	TXT	"eth-addr=0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"
+blog.vitalik.eth.	3600	IN	CNAME	www.vitalik.eth.
+www.vitalik.eth.	3600	IN	AAAA	2001:db8::1
+`))
+	})
+
+	It("Leaves out the SOA record if configured to", func() {
+		var zone bytes.Buffer
+		err := exporter.Export(&zone, dnsserver.ZoneConfig{Parent: "sub.vitalik.eth", OmitSOA: true})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zone.String()).To(Equal("; Zone of the ENS name sub.vitalik.eth and the names below it\n$ORIGIN sub.vitalik.eth.\n$TTL 300\n"))
+	})
+
+	It("Returns an error for parents that aren't ENS names", func() {
+		err := exporter.Export(&bytes.Buffer{}, dnsserver.ZoneConfig{Parent: "nonexistent.eth"})
+
+		Expect(err).To(MatchError("nonexistent.eth is not an ENS name"))
+	})
+})
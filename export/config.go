@@ -0,0 +1,71 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package export
+
+import (
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+)
+
+// The deployment block of the contract each event table's transformer watches, which the transformer starts checking headers at
+var deploymentBlocks = map[string]func() int64{
+	"auction_started":            constants.RegistarDeploymentBlock,
+	"bid_revealed":               constants.RegistarDeploymentBlock,
+	"hash_invalidated":           constants.RegistarDeploymentBlock,
+	"hash_registered":            constants.RegistarDeploymentBlock,
+	"hash_released":              constants.RegistarDeploymentBlock,
+	"new_bid":                    constants.RegistarDeploymentBlock,
+	"name_migrated":              constants.BaseRegistrarDeploymentBlock,
+	"name_registered":            constants.BaseRegistrarDeploymentBlock,
+	"name_renewed":               constants.BaseRegistrarDeploymentBlock,
+	"controller_added":           constants.BaseRegistrarDeploymentBlock,
+	"controller_removed":         constants.BaseRegistrarDeploymentBlock,
+	"registrar_transfer":         constants.BaseRegistrarDeploymentBlock,
+	"registrar_approval":         constants.BaseRegistrarDeploymentBlock,
+	"controller_name_registered": constants.ControllerDeploymentBlock,
+	"controller_name_renewed":    constants.ControllerDeploymentBlock,
+	"new_price_oracle":           constants.ControllerDeploymentBlock,
+	"reverse_claimed":            constants.ReverseRegistrarDeploymentBlock,
+	"new_owner":                  constants.RegistryDeploymentBlock,
+	"new_resolver":               constants.RegistryDeploymentBlock,
+	"new_ttl":                    constants.RegistryDeploymentBlock,
+	"transfer":                   constants.RegistryDeploymentBlock,
+	"abi_changed":                constants.ResolverDeploymentBlock,
+	"addr_changed":               constants.ResolverDeploymentBlock,
+	"address_changed":            constants.ResolverDeploymentBlock,
+	"authorisation_changed":      constants.ResolverDeploymentBlock,
+	"content_changed":            constants.ResolverDeploymentBlock,
+	"contenthash_changed":        constants.ResolverDeploymentBlock,
+	"dns_record_changed":         constants.ResolverDeploymentBlock,
+	"dns_record_deleted":         constants.ResolverDeploymentBlock,
+	"dns_zonehash_changed":       constants.ResolverDeploymentBlock,
+	"interface_changed":          constants.ResolverDeploymentBlock,
+	"multihash_changed":          constants.ResolverDeploymentBlock,
+	"name_changed":               constants.ResolverDeploymentBlock,
+	"pubkey_changed":             constants.ResolverDeploymentBlock,
+	"text_changed":               constants.ResolverDeploymentBlock,
+	"version_changed":            constants.ResolverDeploymentBlock,
+}
+
+// Reads the block each event table's transformer starts at from the environment config the transformers are run with
+func StartingBlocks() map[string]int64 {
+	startingBlocks := make(map[string]int64, len(deploymentBlocks))
+	for table, deploymentBlock := range deploymentBlocks {
+		startingBlocks[table] = deploymentBlock()
+	}
+
+	return startingBlocks
+}
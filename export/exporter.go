@@ -0,0 +1,193 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package export writes the ens event tables and the domain records to files in bulk, for loading into a data warehouse
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+)
+
+var ErrNoNewBlocks = errors.New("no new blocks to export")
+
+// An export of a table's rows in a block range
+type Job struct {
+	Table  string
+	Format string
+	// The file to write, in which {from} and {to} are replaced by the block range exported; - writes to stdout
+	Out string
+	// The block range, where To defaults to the last exportable block
+	From int64
+	To   int64
+	// The number of blocks below the latest header that are left out as they could still be reorged
+	Confirmations int64
+	// The file of high-water marks that incremental exports resume from, if any
+	StateFile string
+}
+
+// The block range and number of rows that were exported to the file
+type Result struct {
+	From int64
+	To   int64
+	Rows int64
+	File string
+}
+
+type Exporter struct {
+	repository.ExportRepository
+}
+
+func NewExporter(db *postgres.DB, exportConfig repository.ExportConfig) *Exporter {
+	return &Exporter{
+		ExportRepository: repository.NewExportRepository(db, exportConfig),
+	}
+}
+
+// Exports the rows of the job's table in its block range, up to the last exportable block
+// With a state file, the export starts after the table's high-water mark, if it has one, and the mark is moved to the end of the
+// range once the file has been written. Returns ErrNoNewBlocks if there is nothing after the mark to export yet
+func (e *Exporter) Run(job Job) (Result, error) {
+	err := checkFormat(job.Format)
+	if err != nil {
+		return Result{}, err
+	}
+	if job.Out == "" {
+		return Result{}, errors.New("no file to export to")
+	}
+	state := State{}
+	if job.StateFile != "" {
+		state, err = LoadState(job.StateFile)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	result := Result{From: job.From}
+	if mark, ok := state[job.Table]; ok && mark >= result.From {
+		result.From = mark + 1
+	}
+	result.To, err = e.LastExportableBlock(job.Table, job.Confirmations)
+	if err != nil {
+		return Result{}, err
+	}
+	if job.To != 0 && job.To < result.To {
+		result.To = job.To
+	}
+	if result.From > result.To {
+		return result, ErrNoNewBlocks
+	}
+
+	result.File = strings.NewReplacer("{from}", strconv.FormatInt(result.From, 10), "{to}", strconv.FormatInt(result.To, 10)).Replace(job.Out)
+	err = writeFile(result.File, func(w io.Writer) error {
+		writer, err := NewWriter(job.Format, w)
+		if err != nil {
+			return err
+		}
+		err = e.StreamTable(job.Table, result.From, result.To, writer.WriteHeader, func(row []interface{}) error {
+			result.Rows++
+			return writer.WriteRow(row)
+		})
+		if err != nil {
+			return err
+		}
+		return writer.Close()
+	})
+	if err != nil {
+		return result, err
+	}
+	if job.StateFile == "" {
+		return result, nil
+	}
+	state[job.Table] = result.To
+
+	return result, state.Save(job.StateFile)
+}
+
+// The high-water marks of incremental exports: the last block exported of each table
+type State map[string]int64
+
+// Reads the high-water marks from a JSON file, which may not exist yet
+func LoadState(path string) (State, error) {
+	state := State{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return state, json.Unmarshal(data, &state)
+}
+
+func (s State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, func(w io.Writer) error {
+		_, err := w.Write(append(data, '\n'))
+		return err
+	})
+}
+
+// Writes a file through a temporary file next to it that is renamed into place, so that the file is never left half written
+// The path - writes to stdout
+func writeFile(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		buffered := bufio.NewWriter(os.Stdout)
+		err := write(buffered)
+		if err != nil {
+			return err
+		}
+		return buffered.Flush()
+	}
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	buffered := bufio.NewWriter(file)
+	err = write(buffered)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(file.Name(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
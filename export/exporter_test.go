@@ -0,0 +1,146 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package export_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/export"
+	ensconfig "github.com/vulcanize/ens_transformers/transformers/domain_records/config"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+)
+
+var _ = Describe("Exporter", func() {
+	var db *postgres.DB
+	var exporter *export.Exporter
+	var headerIds map[int64]int64
+	var dir string
+
+	// Creates a NewOwner event in the block and marks its header checked for the event
+	createNewOwner := func(blockNumber int64, owner string) {
+		_, err := db.Exec(`INSERT INTO ens.new_owner (header_id, node, label, owner, subnode, tx_idx, log_idx, raw_log)
+			VALUES ($1, 'fakeNode', 'fakeLabel', $2, 'fakeSubnode', 0, 0, '{"blockHash": "0xfakeBlockHash", "transactionHash": "0xfakeTxHash"}')`,
+			headerIds[blockNumber], owner)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked) VALUES ($1, 1)`, headerIds[blockNumber])
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// Reads the owners of the exported JSON lines
+	readOwners := func(path string) []string {
+		data, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		var owners []string
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var row map[string]interface{}
+			err = json.Unmarshal([]byte(line), &row)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(row["tx_hash"]).To(Equal("0xfakeTxHash"))
+			owners = append(owners, row["owner"].(string))
+		}
+		return owners
+	}
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		exporter = export.NewExporter(db, repository.ExportConfig{Registry: ensconfig.RopstenENSConfig})
+		headerIds = test_helpers.CreateHeaders(db, 3327420, 3327422)
+		var err error
+		dir, err = ioutil.TempDir("", "export")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+		os.RemoveAll(dir)
+	})
+
+	It("Exports incrementally from the high-water mark in the state file", func() {
+		createNewOwner(3327420, "firstOwner")
+		createNewOwner(3327421, "secondOwner")
+		job := export.Job{
+			Table:     "new_owner",
+			Format:    export.FormatJSONL,
+			Out:       filepath.Join(dir, "new_owner_{from}_{to}.jsonl"),
+			StateFile: filepath.Join(dir, "state.json"),
+		}
+
+		result, err := exporter.Run(job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(export.Result{From: 0, To: 3327421, Rows: 2, File: filepath.Join(dir, "new_owner_0_3327421.jsonl")}))
+		Expect(readOwners(result.File)).To(Equal([]string{"firstOwner", "secondOwner"}))
+		state, err := export.LoadState(job.StateFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal(export.State{"new_owner": 3327421}))
+
+		_, err = exporter.Run(job)
+		Expect(err).To(Equal(export.ErrNoNewBlocks))
+
+		createNewOwner(3327422, "thirdOwner")
+		result, err = exporter.Run(job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(export.Result{From: 3327422, To: 3327422, Rows: 1, File: filepath.Join(dir, "new_owner_3327422_3327422.jsonl")}))
+		Expect(readOwners(result.File)).To(Equal([]string{"thirdOwner"}))
+		state, err = export.LoadState(job.StateFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal(export.State{"new_owner": 3327422}))
+	})
+
+	It("Exports the given block range without a state file", func() {
+		createNewOwner(3327420, "firstOwner")
+		createNewOwner(3327421, "secondOwner")
+		createNewOwner(3327422, "thirdOwner")
+
+		result, err := exporter.Run(export.Job{
+			Table:  "new_owner",
+			Format: export.FormatJSONL,
+			Out:    filepath.Join(dir, "new_owner.jsonl"),
+			From:   3327421,
+			To:     3327421,
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Rows).To(Equal(int64(1)))
+		Expect(readOwners(result.File)).To(Equal([]string{"secondOwner"}))
+	})
+
+	It("Leaves the file and the state alone if the export fails", func() {
+		job := export.Job{
+			Table:     "labels",
+			Format:    export.FormatCSV,
+			Out:       filepath.Join(dir, "labels.csv"),
+			StateFile: filepath.Join(dir, "state.json"),
+		}
+
+		_, err := exporter.Run(job)
+
+		Expect(err).To(HaveOccurred())
+		files, err := ioutil.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(BeEmpty())
+	})
+})
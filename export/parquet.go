@@ -0,0 +1,286 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package export
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+)
+
+const (
+	parquetMagic = "PAR1"
+	// The number of rows buffered into each row group before it is written
+	rowGroupSize = 10000
+)
+
+// Enums of the Parquet format (parquet-format's parquet.thrift)
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetOptional = 1
+	parquetUTF8     = 0
+
+	parquetPlain        = 0
+	parquetRLE          = 3
+	parquetUncompressed = 0
+	parquetDataPage     = 0
+)
+
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+type parquetRowGroup struct {
+	chunks []parquetChunk
+	size   int64
+	rows   int64
+}
+
+// Writes a Parquet file of optional (nullable) columns: int64 columns as INT64, bool columns as BOOLEAN and string columns as
+// UTF8 BYTE_ARRAYs. Rows are buffered into row groups of up to rowGroupSize rows, each column of which is written as a single
+// uncompressed, PLAIN encoded data page, and the file metadata is written by Close
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []models.ExportColumn
+	buffered  [][]interface{}
+	rowGroups []parquetRowGroup
+	rows      int64
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	return &parquetWriter{w: w}
+}
+
+func (p *parquetWriter) write(data []byte) error {
+	n, err := p.w.Write(data)
+	p.offset += int64(n)
+	return err
+}
+
+func (p *parquetWriter) WriteHeader(columns []models.ExportColumn) error {
+	p.columns = columns
+	return p.write([]byte(parquetMagic))
+}
+
+func (p *parquetWriter) WriteRow(row []interface{}) error {
+	if len(row) != len(p.columns) {
+		return fmt.Errorf("row of %d values for %d columns", len(row), len(p.columns))
+	}
+	p.buffered = append(p.buffered, row)
+	if len(p.buffered) < rowGroupSize {
+		return nil
+	}
+	return p.writeRowGroup()
+}
+
+func (p *parquetWriter) Close() error {
+	if p.offset == 0 {
+		err := p.write([]byte(parquetMagic))
+		if err != nil {
+			return err
+		}
+	}
+	err := p.writeRowGroup()
+	if err != nil {
+		return err
+	}
+	metadata := p.fileMetadata()
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(len(metadata)))
+
+	return p.write(append(append(metadata, length...), parquetMagic...))
+}
+
+func (p *parquetWriter) writeRowGroup() error {
+	if len(p.buffered) == 0 {
+		return nil
+	}
+	group := parquetRowGroup{rows: int64(len(p.buffered))}
+	for i := range p.columns {
+		page, err := p.dataPage(i)
+		if err != nil {
+			return err
+		}
+		chunk := parquetChunk{offset: p.offset, size: int64(len(page))}
+		err = p.write(page)
+		if err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.size
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.rows += group.rows
+	p.buffered = p.buffered[:0]
+
+	return nil
+}
+
+// Encodes the buffered values of a column as a data page: its header, then the definition levels of its values (1 for values,
+// 0 for nulls) in the RLE hybrid encoding prefixed by their length, then its non-null values PLAIN encoded
+func (p *parquetWriter) dataPage(column int) ([]byte, error) {
+	kind := p.columns[column].Kind
+	defined := make([]bool, len(p.buffered))
+	var values []byte
+	booleans := 0
+	for i, row := range p.buffered {
+		value := row[column]
+		if value == nil {
+			continue
+		}
+		defined[i] = true
+		switch v := value.(type) {
+		case int64:
+			if kind != models.ExportInt {
+				return nil, fmt.Errorf("int64 value in %s column %s", kind, p.columns[column].Name)
+			}
+			encoded := make([]byte, 8)
+			binary.LittleEndian.PutUint64(encoded, uint64(v))
+			values = append(values, encoded...)
+		case bool:
+			if kind != models.ExportBool {
+				return nil, fmt.Errorf("bool value in %s column %s", kind, p.columns[column].Name)
+			}
+			if booleans%8 == 0 {
+				values = append(values, 0)
+			}
+			if v {
+				values[len(values)-1] |= 1 << uint(booleans%8)
+			}
+			booleans++
+		case string:
+			if kind != models.ExportString {
+				return nil, fmt.Errorf("string value in %s column %s", kind, p.columns[column].Name)
+			}
+			length := make([]byte, 4)
+			binary.LittleEndian.PutUint32(length, uint32(len(v)))
+			values = append(append(values, length...), v...)
+		default:
+			return nil, fmt.Errorf("unsupported value %v in column %s", value, p.columns[column].Name)
+		}
+	}
+	levels := encodeLevels(defined)
+	data := make([]byte, 4, 4+len(levels)+len(values))
+	binary.LittleEndian.PutUint32(data, uint32(len(levels)))
+	data = append(append(data, levels...), values...)
+
+	header := newThriftEncoder()
+	header.fieldI32(1, parquetDataPage)
+	header.fieldI32(2, int32(len(data)))
+	header.fieldI32(3, int32(len(data)))
+	header.fieldStruct(5)
+	header.fieldI32(1, int32(len(p.buffered)))
+	header.fieldI32(2, parquetPlain)
+	header.fieldI32(3, parquetRLE)
+	header.fieldI32(4, parquetRLE)
+	header.structEnd()
+	header.structEnd()
+
+	return append(header.buf, data...), nil
+}
+
+// Encodes definition levels of bit width 1 as RLE runs of repeated levels, each a varint of the run's length shifted left by one
+// followed by the level in a byte
+func encodeLevels(defined []bool) []byte {
+	var encoded []byte
+	for i := 0; i < len(defined); {
+		run := 1
+		for i+run < len(defined) && defined[i+run] == defined[i] {
+			run++
+		}
+		header := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(header, uint64(run)<<1)
+		level := byte(0)
+		if defined[i] {
+			level = 1
+		}
+		encoded = append(append(encoded, header[:n]...), level)
+		i += run
+	}
+
+	return encoded
+}
+
+func parquetType(column models.ExportColumn) int32 {
+	switch column.Kind {
+	case models.ExportInt:
+		return parquetInt64
+	case models.ExportBool:
+		return parquetBoolean
+	default:
+		return parquetByteArray
+	}
+}
+
+// Encodes the FileMetaData of the file: its flat schema of optional columns under the root, and the row groups written
+func (p *parquetWriter) fileMetadata() []byte {
+	metadata := newThriftEncoder()
+	metadata.fieldI32(1, 1)
+	metadata.fieldList(2, thriftStruct, len(p.columns)+1)
+	metadata.structBegin()
+	metadata.fieldBinary(4, "schema")
+	metadata.fieldI32(5, int32(len(p.columns)))
+	metadata.structEnd()
+	for _, column := range p.columns {
+		metadata.structBegin()
+		metadata.fieldI32(1, parquetType(column))
+		metadata.fieldI32(3, parquetOptional)
+		metadata.fieldBinary(4, column.Name)
+		if column.Kind == models.ExportString {
+			metadata.fieldI32(6, parquetUTF8)
+		}
+		metadata.structEnd()
+	}
+	metadata.fieldI64(3, p.rows)
+	metadata.fieldList(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		metadata.structBegin()
+		metadata.fieldList(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			metadata.structBegin()
+			metadata.fieldI64(2, chunk.offset)
+			metadata.fieldStruct(3)
+			metadata.fieldI32(1, parquetType(p.columns[i]))
+			metadata.fieldList(2, thriftI32, 2)
+			metadata.i32(parquetPlain)
+			metadata.i32(parquetRLE)
+			metadata.fieldList(3, thriftBinary, 1)
+			metadata.binary(p.columns[i].Name)
+			metadata.fieldI32(4, parquetUncompressed)
+			metadata.fieldI64(5, group.rows)
+			metadata.fieldI64(6, chunk.size)
+			metadata.fieldI64(7, chunk.size)
+			metadata.fieldI64(9, chunk.offset)
+			metadata.structEnd()
+			metadata.structEnd()
+		}
+		metadata.fieldI64(2, group.size)
+		metadata.fieldI64(3, group.rows)
+		metadata.structEnd()
+	}
+	metadata.fieldBinary(6, "ens_transformers")
+	metadata.structEnd()
+
+	return metadata.buf
+}
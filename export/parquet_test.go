@@ -0,0 +1,185 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package export_test
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/export"
+)
+
+// Decodes values of the Thrift compact protocol: integers as int64, binaries as strings, lists as slices and structs as maps of their
+// fields by id
+type compactDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *compactDecoder) varint() uint64 {
+	value, n := binary.Uvarint(d.data[d.pos:])
+	d.pos += n
+	return value
+}
+
+func (d *compactDecoder) zigzag() int64 {
+	value := d.varint()
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+func (d *compactDecoder) value(valueType byte) interface{} {
+	switch valueType {
+	case 5, 6:
+		return d.zigzag()
+	case 8:
+		length := int(d.varint())
+		d.pos += length
+		return string(d.data[d.pos-length : d.pos])
+	case 9:
+		header := d.data[d.pos]
+		d.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(d.varint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = d.value(header & 0xf)
+		}
+		return list
+	case 12:
+		return d.structure()
+	}
+	Fail("unexpected compact protocol type")
+	return nil
+}
+
+func (d *compactDecoder) structure() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var id int16
+	for {
+		header := d.data[d.pos]
+		d.pos++
+		if header == 0 {
+			return fields
+		}
+		if delta := header >> 4; delta != 0 {
+			id += int16(delta)
+		} else {
+			id = int16(d.zigzag())
+		}
+		fields[id] = d.value(header & 0xf)
+	}
+}
+
+// Returns the file metadata of a Parquet file, checking its magic numbers
+func fileMetadata(file []byte) map[int16]interface{} {
+	Expect(string(file[:4])).To(Equal("PAR1"))
+	Expect(string(file[len(file)-4:])).To(Equal("PAR1"))
+	length := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	decoder := &compactDecoder{data: file[:len(file)-8], pos: len(file) - 8 - length}
+	metadata := decoder.structure()
+	Expect(decoder.pos).To(Equal(len(file) - 8))
+
+	return metadata
+}
+
+var _ = Describe("Parquet writer", func() {
+	It("Writes the schema and a row group of the rows in the file metadata", func() {
+		metadata := fileMetadata(writeRows(export.FormatParquet, rows))
+
+		Expect(metadata[1]).To(Equal(int64(1)))
+		Expect(metadata[2]).To(Equal([]interface{}{
+			map[int16]interface{}{4: "schema", 5: int64(3)},
+			map[int16]interface{}{1: int64(2), 3: int64(1), 4: "block_number"},
+			map[int16]interface{}{1: int64(6), 3: int64(1), 4: "name", 6: int64(0)},
+			map[int16]interface{}{1: int64(0), 3: int64(1), 4: "migrated"},
+		}))
+		Expect(metadata[3]).To(Equal(int64(3)))
+		rowGroups := metadata[4].([]interface{})
+		Expect(len(rowGroups)).To(Equal(1))
+		rowGroup := rowGroups[0].(map[int16]interface{})
+		Expect(rowGroup[3]).To(Equal(int64(3)))
+		chunks := rowGroup[1].([]interface{})
+		Expect(len(chunks)).To(Equal(3))
+		chunk := chunks[1].(map[int16]interface{})
+		Expect(chunk[3]).To(Equal(map[int16]interface{}{
+			1: int64(6),
+			2: []interface{}{int64(0), int64(3)},
+			3: []interface{}{"name"},
+			4: int64(0),
+			5: int64(3),
+			6: chunk[3].(map[int16]interface{})[6],
+			7: chunk[3].(map[int16]interface{})[6],
+			9: chunk[2],
+		}))
+	})
+
+	It("Writes each column as a data page of its definition levels and non-null values", func() {
+		file := writeRows(export.FormatParquet, rows)
+		metadata := fileMetadata(file)
+		chunks := metadata[4].([]interface{})[0].(map[int16]interface{})[1].([]interface{})
+
+		// The definition levels of each column in runs, prefixed by their length, then its values
+		expectedPages := []string{
+			"0x04000000" + "0401" + "0200" + "bcc5320000000000bdc5320000000000",
+			"0x06000000" + "0201" + "0200" + "0201" + "0b00000076697461" + "6c696b2e657468" + "1000000061202271756f746564222c206e616d65",
+			"0x04000000" + "0401" + "0200" + "01",
+		}
+		for i, expectedPage := range expectedPages {
+			columnMetadata := chunks[i].(map[int16]interface{})[3].(map[int16]interface{})
+			offset, size := int(columnMetadata[9].(int64)), int(columnMetadata[6].(int64))
+			decoder := &compactDecoder{data: file[:offset+size], pos: offset}
+			header := decoder.structure()
+			data := file[decoder.pos : offset+size]
+
+			Expect(header).To(Equal(map[int16]interface{}{
+				1: int64(0),
+				2: int64(len(data)),
+				3: int64(len(data)),
+				5: map[int16]interface{}{1: int64(3), 2: int64(0), 3: int64(3), 4: int64(3)},
+			}))
+			Expect(hexutil.Encode(data)).To(Equal(expectedPage))
+		}
+	})
+
+	It("Splits the rows into row groups", func() {
+		manyRows := make([][]interface{}, 10001)
+		for i := range manyRows {
+			manyRows[i] = []interface{}{int64(i), nil, true}
+		}
+
+		metadata := fileMetadata(writeRows(export.FormatParquet, manyRows))
+
+		Expect(metadata[3]).To(Equal(int64(10001)))
+		rowGroups := metadata[4].([]interface{})
+		Expect(len(rowGroups)).To(Equal(2))
+		Expect(rowGroups[0].(map[int16]interface{})[3]).To(Equal(int64(10000)))
+		Expect(rowGroups[1].(map[int16]interface{})[3]).To(Equal(int64(1)))
+	})
+
+	It("Writes the schema of tables without rows", func() {
+		metadata := fileMetadata(writeRows(export.FormatParquet, nil))
+
+		Expect(metadata[3]).To(Equal(int64(0)))
+		Expect(metadata[4]).To(Equal([]interface{}{}))
+		Expect(len(metadata[2].([]interface{}))).To(Equal(4))
+	})
+})
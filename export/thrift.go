@@ -0,0 +1,116 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package export
+
+// The types of the Thrift compact protocol used by Parquet's metadata
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// Encodes structs in the Thrift compact protocol, which Parquet's page headers and file metadata are written in
+// Fields have to be written in increasing order of their ids within each struct
+type thriftEncoder struct {
+	buf []byte
+	// The id of the last field written in each open struct, innermost last
+	lastFields []int16
+}
+
+func newThriftEncoder() *thriftEncoder {
+	return &thriftEncoder{lastFields: []int16{0}}
+}
+
+func (t *thriftEncoder) field(id int16, fieldType byte) {
+	last := &t.lastFields[len(t.lastFields)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|fieldType)
+	} else {
+		t.buf = append(t.buf, fieldType)
+		t.varint(uint64(zigzag(int64(id))))
+	}
+	*last = id
+}
+
+func (t *thriftEncoder) varint(value uint64) {
+	for value >= 0x80 {
+		t.buf = append(t.buf, byte(value)|0x80)
+		value >>= 7
+	}
+	t.buf = append(t.buf, byte(value))
+}
+
+func zigzag(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
+
+func (t *thriftEncoder) i32(value int32) {
+	t.varint(zigzag(int64(value)))
+}
+
+func (t *thriftEncoder) i64(value int64) {
+	t.varint(zigzag(value))
+}
+
+func (t *thriftEncoder) binary(value string) {
+	t.varint(uint64(len(value)))
+	t.buf = append(t.buf, value...)
+}
+
+func (t *thriftEncoder) fieldI32(id int16, value int32) {
+	t.field(id, thriftI32)
+	t.i32(value)
+}
+
+func (t *thriftEncoder) fieldI64(id int16, value int64) {
+	t.field(id, thriftI64)
+	t.i64(value)
+}
+
+func (t *thriftEncoder) fieldBinary(id int16, value string) {
+	t.field(id, thriftBinary)
+	t.binary(value)
+}
+
+// Begins a list field, whose elements are written next
+func (t *thriftEncoder) fieldList(id int16, elementType byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf = append(t.buf, byte(size)<<4|elementType)
+	} else {
+		t.buf = append(t.buf, 0xf0|elementType)
+		t.varint(uint64(size))
+	}
+}
+
+// Begins a struct field, whose fields are written next, up to a call to structEnd
+func (t *thriftEncoder) fieldStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.structBegin()
+}
+
+// Begins a struct that isn't a field, i.e. a list element
+func (t *thriftEncoder) structBegin() {
+	t.lastFields = append(t.lastFields, 0)
+}
+
+func (t *thriftEncoder) structEnd() {
+	t.buf = append(t.buf, 0)
+	t.lastFields = t.lastFields[:len(t.lastFields)-1]
+}
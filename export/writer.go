@@ -0,0 +1,149 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+)
+
+// The formats tables can be exported in
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+)
+
+// Writes the rows of an exported table in one of the export formats
+type Writer interface {
+	// Begins the output with the table's columns; called once, before any of its rows
+	WriteHeader(columns []models.ExportColumn) error
+	WriteRow(row []interface{}) error
+	// Ends the output, without closing the underlying writer
+	Close() error
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{csv: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		return &jsonlWriter{w: w}, nil
+	case FormatParquet:
+		return newParquetWriter(w), nil
+	default:
+		return nil, unknownFormat(format)
+	}
+}
+
+func checkFormat(format string) error {
+	switch format {
+	case FormatCSV, FormatJSONL, FormatParquet:
+		return nil
+	default:
+		return unknownFormat(format)
+	}
+}
+
+func unknownFormat(format string) error {
+	return fmt.Errorf("unknown export format %q, expected %s, %s or %s", format, FormatCSV, FormatJSONL, FormatParquet)
+}
+
+// Writes a header line of the column names, then a line per row, with nulls as empty fields
+type csvWriter struct {
+	csv    *csv.Writer
+	record []string
+}
+
+func (c *csvWriter) WriteHeader(columns []models.ExportColumn) error {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	c.record = make([]string, len(columns))
+
+	return c.csv.Write(names)
+}
+
+func (c *csvWriter) WriteRow(row []interface{}) error {
+	for i, value := range row {
+		switch v := value.(type) {
+		case nil:
+			c.record[i] = ""
+		case int64:
+			c.record[i] = strconv.FormatInt(v, 10)
+		case bool:
+			c.record[i] = strconv.FormatBool(v)
+		default:
+			c.record[i] = fmt.Sprint(v)
+		}
+	}
+
+	return c.csv.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// Writes a JSON object per row, one per line, with its columns as keys in the table's order
+type jsonlWriter struct {
+	w    io.Writer
+	keys [][]byte
+	line []byte
+}
+
+func (j *jsonlWriter) WriteHeader(columns []models.ExportColumn) error {
+	j.keys = make([][]byte, len(columns))
+	for i, column := range columns {
+		key, err := json.Marshal(column.Name)
+		if err != nil {
+			return err
+		}
+		j.keys[i] = key
+	}
+
+	return nil
+}
+
+func (j *jsonlWriter) WriteRow(row []interface{}) error {
+	j.line = append(j.line[:0], '{')
+	for i, value := range row {
+		if i > 0 {
+			j.line = append(j.line, ',')
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		j.line = append(append(append(j.line, j.keys[i]...), ':'), encoded...)
+	}
+	j.line = append(j.line, '}', '\n')
+	_, err := j.w.Write(j.line)
+
+	return err
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,79 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package export_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/export"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+)
+
+var columns = []models.ExportColumn{
+	{Name: "block_number", Kind: models.ExportInt},
+	{Name: "name", Kind: models.ExportString},
+	{Name: "migrated", Kind: models.ExportBool},
+}
+
+var rows = [][]interface{}{
+	{int64(3327420), "vitalik.eth", true},
+	{int64(3327421), nil, false},
+	{nil, "a \"quoted\", name", nil},
+}
+
+// Writes the rows in the format, returning the output
+func writeRows(format string, rows [][]interface{}) []byte {
+	var out bytes.Buffer
+	writer, err := export.NewWriter(format, &out)
+	Expect(err).NotTo(HaveOccurred())
+	err = writer.WriteHeader(columns)
+	Expect(err).NotTo(HaveOccurred())
+	for _, row := range rows {
+		err = writer.WriteRow(row)
+		Expect(err).NotTo(HaveOccurred())
+	}
+	err = writer.Close()
+	Expect(err).NotTo(HaveOccurred())
+
+	return out.Bytes()
+}
+
+var _ = Describe("Writers", func() {
+	It("Writes CSV with a header line and nulls as empty fields", func() {
+		Expect(string(writeRows(export.FormatCSV, rows))).To(Equal(`block_number,name,migrated
+3327420,vitalik.eth,true
+3327421,,false
+,"a ""quoted"", name",
+`))
+	})
+
+	It("Writes a JSON object per line with the columns in order", func() {
+		Expect(string(writeRows(export.FormatJSONL, rows))).To(Equal(`{"block_number":3327420,"name":"vitalik.eth","migrated":true}
+{"block_number":3327421,"name":null,"migrated":false}
+{"block_number":null,"name":"a \"quoted\", name","migrated":null}
+`))
+	})
+
+	It("Rejects unknown formats", func() {
+		_, err := export.NewWriter("xml", &bytes.Buffer{})
+
+		Expect(err).To(MatchError(`unknown export format "xml", expected csv, jsonl or parquet`))
+	})
+})
@@ -119,18 +119,38 @@ func CleanTestDB(db *postgres.DB) {
 	db.MustExec("DELETE FROM ens.hash_registered")
 	db.MustExec("DELETE FROM ens.hash_released")
 	db.MustExec("DELETE FROM ens.new_bid")
+	db.MustExec("DELETE FROM ens.name_migrated")
+	db.MustExec("DELETE FROM ens.name_registered")
+	db.MustExec("DELETE FROM ens.name_renewed")
+	db.MustExec("DELETE FROM ens.controller_added")
+	db.MustExec("DELETE FROM ens.controller_removed")
+	db.MustExec("DELETE FROM ens.registrar_transfer")
+	db.MustExec("DELETE FROM ens.registrar_approval")
+	db.MustExec("DELETE FROM ens.controller_name_registered")
+	db.MustExec("DELETE FROM ens.controller_name_renewed")
+	db.MustExec("DELETE FROM ens.new_price_oracle")
+	db.MustExec("DELETE FROM ens.reverse_claimed")
+	db.MustExec("DELETE FROM ens.reverse_records")
 	db.MustExec("DELETE FROM ens.new_owner")
 	db.MustExec("DELETE FROM ens.new_resolver")
 	db.MustExec("DELETE FROM ens.new_ttl")
 	db.MustExec("DELETE FROM ens.transfer")
 	db.MustExec("DELETE FROM ens.abi_changed")
 	db.MustExec("DELETE FROM ens.addr_changed")
+	db.MustExec("DELETE FROM ens.address_changed")
 	db.MustExec("DELETE FROM ens.content_changed")
 	db.MustExec("DELETE FROM ens.contenthash_changed")
 	db.MustExec("DELETE FROM ens.multihash_changed")
 	db.MustExec("DELETE FROM ens.name_changed")
 	db.MustExec("DELETE FROM ens.pubkey_changed")
 	db.MustExec("DELETE FROM ens.text_changed")
+	db.MustExec("DELETE FROM ens.interface_changed")
+	db.MustExec("DELETE FROM ens.authorisation_changed")
+	db.MustExec("DELETE FROM ens.version_changed")
+	db.MustExec("DELETE FROM ens.dns_record_changed")
+	db.MustExec("DELETE FROM ens.dns_record_deleted")
+	db.MustExec("DELETE FROM ens.dns_zonehash_changed")
+	db.MustExec("DELETE FROM ens.labels")
 }
 
 // Returns a new test node, with the same ID
@@ -0,0 +1,91 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller_added
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	repo "github.com/vulcanize/vulcanizedb/libraries/shared/repository"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+)
+
+type ControllerAddedRepository struct {
+	db *postgres.DB
+}
+
+func (repository *ControllerAddedRepository) SetDB(db *postgres.DB) {
+	repository.db = db
+}
+
+func (repository ControllerAddedRepository) Create(headerID int64, models []interface{}) error {
+	tx, dBaseErr := repository.db.Beginx()
+	if dBaseErr != nil {
+		return dBaseErr
+	}
+	for _, model := range models {
+		controllerAddedModel, ok := model.(ControllerAddedModel)
+		if !ok {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Error("failed to rollback ", rollbackErr)
+			}
+			return fmt.Errorf("model of type %T, not %T", model, ControllerAddedModel{})
+		}
+
+		_, execErr := tx.Exec(
+			`INSERT into ens.controller_added (header_id, controller, log_idx, tx_idx, raw_log)
+        			VALUES($1, $2, $3, $4, $5)
+					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET controller = $2, raw_log = $5;`,
+			headerID, controllerAddedModel.Controller, controllerAddedModel.LogIndex, controllerAddedModel.TransactionIndex, controllerAddedModel.Raw,
+		)
+		if execErr != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Error("failed to rollback ", rollbackErr)
+			}
+			return execErr
+		}
+	}
+
+	checkHeaderErr := repo.MarkHeaderCheckedInTransaction(headerID, tx, constants.ControllerAddedChecked)
+	if checkHeaderErr != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return checkHeaderErr
+	}
+
+	return tx.Commit()
+}
+
+func (repository ControllerAddedRepository) MarkHeaderChecked(headerID int64) error {
+	return repo.MarkHeaderChecked(headerID, repository.db, constants.ControllerAddedChecked)
+}
+
+func (repository ControllerAddedRepository) MissingHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.MissingHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.ControllerAddedChecked)
+}
+
+func (repository ControllerAddedRepository) RecheckHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.RecheckHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.ControllerAddedChecked)
+}
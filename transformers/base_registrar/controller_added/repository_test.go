@@ -0,0 +1,86 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller_added_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/controller_added"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("ControllerAdded repository", func() {
+	var (
+		controllerAddedRepository controller_added.ControllerAddedRepository
+		db                        *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		controllerAddedRepository = controller_added.ControllerAddedRepository{}
+		controllerAddedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.ControllerAddedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.ControllerAddedChecked,
+			LogEventTableName:        "ens.controller_added",
+			TestModel:                test_data.ControllerAddedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &controllerAddedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a controller_added record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = controllerAddedRepository.Create(headerID, []interface{}{test_data.ControllerAddedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbControllerAdded controller_added.ControllerAddedModel
+			err = db.Get(&dbControllerAdded, `SELECT controller, log_idx, tx_idx, raw_log FROM ens.controller_added WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbControllerAdded.Controller).To(Equal(test_data.ControllerAddedModel.Controller))
+			Expect(dbControllerAdded.LogIndex).To(Equal(test_data.ControllerAddedModel.LogIndex))
+			Expect(dbControllerAdded.TransactionIndex).To(Equal(test_data.ControllerAddedModel.TransactionIndex))
+			Expect(dbControllerAdded.Raw).To(MatchJSON(test_data.ControllerAddedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.ControllerAddedChecked,
+			Repository:              &controllerAddedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
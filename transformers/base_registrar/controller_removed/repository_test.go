@@ -0,0 +1,86 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller_removed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/controller_removed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("ControllerRemoved repository", func() {
+	var (
+		controllerRemovedRepository controller_removed.ControllerRemovedRepository
+		db                          *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		controllerRemovedRepository = controller_removed.ControllerRemovedRepository{}
+		controllerRemovedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.ControllerRemovedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.ControllerRemovedChecked,
+			LogEventTableName:        "ens.controller_removed",
+			TestModel:                test_data.ControllerRemovedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &controllerRemovedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a controller_removed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = controllerRemovedRepository.Create(headerID, []interface{}{test_data.ControllerRemovedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbControllerRemoved controller_removed.ControllerRemovedModel
+			err = db.Get(&dbControllerRemoved, `SELECT controller, log_idx, tx_idx, raw_log FROM ens.controller_removed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbControllerRemoved.Controller).To(Equal(test_data.ControllerRemovedModel.Controller))
+			Expect(dbControllerRemoved.LogIndex).To(Equal(test_data.ControllerRemovedModel.LogIndex))
+			Expect(dbControllerRemoved.TransactionIndex).To(Equal(test_data.ControllerRemovedModel.TransactionIndex))
+			Expect(dbControllerRemoved.Raw).To(MatchJSON(test_data.ControllerRemovedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.ControllerRemovedChecked,
+			Repository:              &controllerRemovedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
@@ -0,0 +1,91 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package name_migrated
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	repo "github.com/vulcanize/vulcanizedb/libraries/shared/repository"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+)
+
+type NameMigratedRepository struct {
+	db *postgres.DB
+}
+
+func (repository *NameMigratedRepository) SetDB(db *postgres.DB) {
+	repository.db = db
+}
+
+func (repository NameMigratedRepository) Create(headerID int64, models []interface{}) error {
+	tx, dBaseErr := repository.db.Beginx()
+	if dBaseErr != nil {
+		return dBaseErr
+	}
+	for _, model := range models {
+		nameMigratedModel, ok := model.(NameMigratedModel)
+		if !ok {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Error("failed to rollback ", rollbackErr)
+			}
+			return fmt.Errorf("model of type %T, not %T", model, NameMigratedModel{})
+		}
+
+		_, execErr := tx.Exec(
+			`INSERT into ens.name_migrated (header_id, label_hash, owner, expires, log_idx, tx_idx, raw_log)
+        			VALUES($1, $2, $3, $4, $5, $6, $7)
+					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET label_hash = $2, owner = $3, expires = $4, raw_log = $7;`,
+			headerID, nameMigratedModel.LabelHash, nameMigratedModel.Owner, nameMigratedModel.Expires, nameMigratedModel.LogIndex, nameMigratedModel.TransactionIndex, nameMigratedModel.Raw,
+		)
+		if execErr != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Error("failed to rollback ", rollbackErr)
+			}
+			return execErr
+		}
+	}
+
+	checkHeaderErr := repo.MarkHeaderCheckedInTransaction(headerID, tx, constants.NameMigratedChecked)
+	if checkHeaderErr != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return checkHeaderErr
+	}
+
+	return tx.Commit()
+}
+
+func (repository NameMigratedRepository) MarkHeaderChecked(headerID int64) error {
+	return repo.MarkHeaderChecked(headerID, repository.db, constants.NameMigratedChecked)
+}
+
+func (repository NameMigratedRepository) MissingHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.MissingHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.NameMigratedChecked)
+}
+
+func (repository NameMigratedRepository) RecheckHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.RecheckHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.NameMigratedChecked)
+}
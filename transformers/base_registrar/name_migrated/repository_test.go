@@ -0,0 +1,88 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package name_migrated_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/name_migrated"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("NameMigrated repository", func() {
+	var (
+		nameMigratedRepository name_migrated.NameMigratedRepository
+		db                     *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		nameMigratedRepository = name_migrated.NameMigratedRepository{}
+		nameMigratedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.NameMigratedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.NameMigratedChecked,
+			LogEventTableName:        "ens.name_migrated",
+			TestModel:                test_data.NameMigratedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &nameMigratedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a name_migrated record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = nameMigratedRepository.Create(headerID, []interface{}{test_data.NameMigratedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbNameMigrated name_migrated.NameMigratedModel
+			err = db.Get(&dbNameMigrated, `SELECT label_hash, owner, expires, log_idx, tx_idx, raw_log FROM ens.name_migrated WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbNameMigrated.LabelHash).To(Equal(test_data.NameMigratedModel.LabelHash))
+			Expect(dbNameMigrated.Owner).To(Equal(test_data.NameMigratedModel.Owner))
+			Expect(dbNameMigrated.Expires).To(Equal(test_data.NameMigratedModel.Expires))
+			Expect(dbNameMigrated.LogIndex).To(Equal(test_data.NameMigratedModel.LogIndex))
+			Expect(dbNameMigrated.TransactionIndex).To(Equal(test_data.NameMigratedModel.TransactionIndex))
+			Expect(dbNameMigrated.Raw).To(MatchJSON(test_data.NameMigratedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.NameMigratedChecked,
+			Repository:              &nameMigratedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
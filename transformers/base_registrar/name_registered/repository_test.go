@@ -0,0 +1,88 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package name_registered_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/name_registered"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("NameRegistered repository", func() {
+	var (
+		nameRegisteredRepository name_registered.NameRegisteredRepository
+		db                       *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		nameRegisteredRepository = name_registered.NameRegisteredRepository{}
+		nameRegisteredRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.NameRegisteredModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.NameRegisteredChecked,
+			LogEventTableName:        "ens.name_registered",
+			TestModel:                test_data.NameRegisteredModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &nameRegisteredRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a name_registered record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = nameRegisteredRepository.Create(headerID, []interface{}{test_data.NameRegisteredModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbNameRegistered name_registered.NameRegisteredModel
+			err = db.Get(&dbNameRegistered, `SELECT label_hash, owner, expires, log_idx, tx_idx, raw_log FROM ens.name_registered WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbNameRegistered.LabelHash).To(Equal(test_data.NameRegisteredModel.LabelHash))
+			Expect(dbNameRegistered.Owner).To(Equal(test_data.NameRegisteredModel.Owner))
+			Expect(dbNameRegistered.Expires).To(Equal(test_data.NameRegisteredModel.Expires))
+			Expect(dbNameRegistered.LogIndex).To(Equal(test_data.NameRegisteredModel.LogIndex))
+			Expect(dbNameRegistered.TransactionIndex).To(Equal(test_data.NameRegisteredModel.TransactionIndex))
+			Expect(dbNameRegistered.Raw).To(MatchJSON(test_data.NameRegisteredModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.NameRegisteredChecked,
+			Repository:              &nameRegisteredRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
@@ -0,0 +1,87 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package name_renewed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/name_renewed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("NameRenewed repository", func() {
+	var (
+		nameRenewedRepository name_renewed.NameRenewedRepository
+		db                    *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		nameRenewedRepository = name_renewed.NameRenewedRepository{}
+		nameRenewedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.NameRenewedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.NameRenewedChecked,
+			LogEventTableName:        "ens.name_renewed",
+			TestModel:                test_data.NameRenewedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &nameRenewedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a name_renewed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = nameRenewedRepository.Create(headerID, []interface{}{test_data.NameRenewedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbNameRenewed name_renewed.NameRenewedModel
+			err = db.Get(&dbNameRenewed, `SELECT label_hash, expires, log_idx, tx_idx, raw_log FROM ens.name_renewed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbNameRenewed.LabelHash).To(Equal(test_data.NameRenewedModel.LabelHash))
+			Expect(dbNameRenewed.Expires).To(Equal(test_data.NameRenewedModel.Expires))
+			Expect(dbNameRenewed.LogIndex).To(Equal(test_data.NameRenewedModel.LogIndex))
+			Expect(dbNameRenewed.TransactionIndex).To(Equal(test_data.NameRenewedModel.TransactionIndex))
+			Expect(dbNameRenewed.Raw).To(MatchJSON(test_data.NameRenewedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.NameRenewedChecked,
+			Repository:              &nameRenewedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
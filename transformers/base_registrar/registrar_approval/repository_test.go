@@ -0,0 +1,88 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package registrar_approval_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/registrar_approval"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("RegistrarApproval repository", func() {
+	var (
+		registrarApprovalRepository registrar_approval.RegistrarApprovalRepository
+		db                          *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		registrarApprovalRepository = registrar_approval.RegistrarApprovalRepository{}
+		registrarApprovalRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.RegistrarApprovalModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.RegistrarApprovalChecked,
+			LogEventTableName:        "ens.registrar_approval",
+			TestModel:                test_data.RegistrarApprovalModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &registrarApprovalRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a registrar_approval record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = registrarApprovalRepository.Create(headerID, []interface{}{test_data.RegistrarApprovalModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbRegistrarApproval registrar_approval.RegistrarApprovalModel
+			err = db.Get(&dbRegistrarApproval, `SELECT owner, approved, label_hash, log_idx, tx_idx, raw_log FROM ens.registrar_approval WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbRegistrarApproval.Owner).To(Equal(test_data.RegistrarApprovalModel.Owner))
+			Expect(dbRegistrarApproval.Approved).To(Equal(test_data.RegistrarApprovalModel.Approved))
+			Expect(dbRegistrarApproval.LabelHash).To(Equal(test_data.RegistrarApprovalModel.LabelHash))
+			Expect(dbRegistrarApproval.LogIndex).To(Equal(test_data.RegistrarApprovalModel.LogIndex))
+			Expect(dbRegistrarApproval.TransactionIndex).To(Equal(test_data.RegistrarApprovalModel.TransactionIndex))
+			Expect(dbRegistrarApproval.Raw).To(MatchJSON(test_data.RegistrarApprovalModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.RegistrarApprovalChecked,
+			Repository:              &registrarApprovalRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
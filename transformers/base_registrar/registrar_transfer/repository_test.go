@@ -0,0 +1,88 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package registrar_transfer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/registrar_transfer"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("RegistrarTransfer repository", func() {
+	var (
+		registrarTransferRepository registrar_transfer.RegistrarTransferRepository
+		db                          *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		registrarTransferRepository = registrar_transfer.RegistrarTransferRepository{}
+		registrarTransferRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.RegistrarTransferModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.RegistrarTransferChecked,
+			LogEventTableName:        "ens.registrar_transfer",
+			TestModel:                test_data.RegistrarTransferModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &registrarTransferRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a registrar_transfer record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = registrarTransferRepository.Create(headerID, []interface{}{test_data.RegistrarTransferModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbRegistrarTransfer registrar_transfer.RegistrarTransferModel
+			err = db.Get(&dbRegistrarTransfer, `SELECT from_addr, to_addr, label_hash, log_idx, tx_idx, raw_log FROM ens.registrar_transfer WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbRegistrarTransfer.From).To(Equal(test_data.RegistrarTransferModel.From))
+			Expect(dbRegistrarTransfer.To).To(Equal(test_data.RegistrarTransferModel.To))
+			Expect(dbRegistrarTransfer.LabelHash).To(Equal(test_data.RegistrarTransferModel.LabelHash))
+			Expect(dbRegistrarTransfer.LogIndex).To(Equal(test_data.RegistrarTransferModel.LogIndex))
+			Expect(dbRegistrarTransfer.TransactionIndex).To(Equal(test_data.RegistrarTransferModel.TransactionIndex))
+			Expect(dbRegistrarTransfer.Raw).To(MatchJSON(test_data.RegistrarTransferModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.RegistrarTransferChecked,
+			Repository:              &registrarTransferRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
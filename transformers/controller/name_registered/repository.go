@@ -0,0 +1,101 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package name_registered
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	repo "github.com/vulcanize/vulcanizedb/libraries/shared/repository"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+)
+
+type NameRegisteredRepository struct {
+	db *postgres.DB
+}
+
+func (repository *NameRegisteredRepository) SetDB(db *postgres.DB) {
+	repository.db = db
+}
+
+func (repository NameRegisteredRepository) Create(headerID int64, models []interface{}) error {
+	tx, dBaseErr := repository.db.Beginx()
+	if dBaseErr != nil {
+		return dBaseErr
+	}
+	for _, model := range models {
+		nameRegisteredModel, ok := model.(NameRegisteredModel)
+		if !ok {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Error("failed to rollback ", rollbackErr)
+			}
+			return fmt.Errorf("model of type %T, not %T", model, NameRegisteredModel{})
+		}
+
+		_, execErr := tx.Exec(
+			`INSERT into ens.controller_name_registered (header_id, name, label_hash, owner, cost, expires, log_idx, tx_idx, raw_log)
+        			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET name = $2, label_hash = $3, owner = $4, cost = $5, expires = $6, raw_log = $9;`,
+			headerID, nameRegisteredModel.Name, nameRegisteredModel.LabelHash, nameRegisteredModel.Owner, nameRegisteredModel.Cost, nameRegisteredModel.Expires, nameRegisteredModel.LogIndex, nameRegisteredModel.TransactionIndex, nameRegisteredModel.Raw,
+		)
+		if execErr != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Error("failed to rollback ", rollbackErr)
+			}
+			return execErr
+		}
+
+		// Seed the label table with the plaintext name
+		labelErr := createLabel(tx, nameRegisteredModel)
+		if labelErr != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Error("failed to rollback ", rollbackErr)
+			}
+			return labelErr
+		}
+	}
+
+	checkHeaderErr := repo.MarkHeaderCheckedInTransaction(headerID, tx, constants.ControllerNameRegisteredChecked)
+	if checkHeaderErr != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return checkHeaderErr
+	}
+
+	return tx.Commit()
+}
+
+func (repository NameRegisteredRepository) MarkHeaderChecked(headerID int64) error {
+	return repo.MarkHeaderChecked(headerID, repository.db, constants.ControllerNameRegisteredChecked)
+}
+
+func (repository NameRegisteredRepository) MissingHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.MissingHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.ControllerNameRegisteredChecked)
+}
+
+func (repository NameRegisteredRepository) RecheckHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.RecheckHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.ControllerNameRegisteredChecked)
+}
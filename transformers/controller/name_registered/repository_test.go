@@ -0,0 +1,120 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package name_registered_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/controller/name_registered"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("NameRegistered repository", func() {
+	var (
+		nameRegisteredRepository name_registered.NameRegisteredRepository
+		db                       *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		nameRegisteredRepository = name_registered.NameRegisteredRepository{}
+		nameRegisteredRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.ControllerNameRegisteredModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.ControllerNameRegisteredChecked,
+			LogEventTableName:        "ens.controller_name_registered",
+			TestModel:                test_data.ControllerNameRegisteredModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &nameRegisteredRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a controller_name_registered record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = nameRegisteredRepository.Create(headerID, []interface{}{test_data.ControllerNameRegisteredModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbNameRegistered name_registered.NameRegisteredModel
+			err = db.Get(&dbNameRegistered, `SELECT name, label_hash, owner, cost, expires, log_idx, tx_idx, raw_log FROM ens.controller_name_registered WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbNameRegistered.Name).To(Equal(test_data.ControllerNameRegisteredModel.Name))
+			Expect(dbNameRegistered.LabelHash).To(Equal(test_data.ControllerNameRegisteredModel.LabelHash))
+			Expect(dbNameRegistered.Owner).To(Equal(test_data.ControllerNameRegisteredModel.Owner))
+			Expect(dbNameRegistered.Cost).To(Equal(test_data.ControllerNameRegisteredModel.Cost))
+			Expect(dbNameRegistered.Expires).To(Equal(test_data.ControllerNameRegisteredModel.Expires))
+			Expect(dbNameRegistered.LogIndex).To(Equal(test_data.ControllerNameRegisteredModel.LogIndex))
+			Expect(dbNameRegistered.TransactionIndex).To(Equal(test_data.ControllerNameRegisteredModel.TransactionIndex))
+			Expect(dbNameRegistered.Raw).To(MatchJSON(test_data.ControllerNameRegisteredModel.Raw))
+		})
+
+		It("seeds the label table with the plaintext name", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = nameRegisteredRepository.Create(headerID, []interface{}{test_data.ControllerNameRegisteredModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var label string
+			err = db.Get(&label, `SELECT label FROM ens.labels WHERE label_hash = $1`, test_data.ControllerNameRegisteredModel.LabelHash)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(label).To(Equal("vitalik"))
+		})
+
+		It("doesn't seed the label table if the name doesn't hash to the label", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+			model := test_data.ControllerNameRegisteredModel
+			model.Name = "notvitalik"
+
+			err = nameRegisteredRepository.Create(headerID, []interface{}{model})
+
+			Expect(err).NotTo(HaveOccurred())
+			var count int
+			err = db.Get(&count, `SELECT COUNT(*) FROM ens.labels`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.ControllerNameRegisteredChecked,
+			Repository:              &nameRegisteredRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
@@ -0,0 +1,39 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package name_renewed
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	ensRepo "github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+// The controller emits the plaintext name alongside its label hash, so it is a reliable source of preimages
+// Names that don't hash to the emitted label are skipped rather than trusted
+func createLabel(tx *sqlx.Tx, model NameRenewedModel) error {
+	if model.Name == "" || utils.LabelHash(model.Name) != model.LabelHash {
+		return nil
+	}
+	_, err := ensRepo.CreateLabelInTransaction(tx, models.LabelModel{
+		LabelHash: model.LabelHash,
+		Label:     model.Name,
+		Source:    "ETHRegistrarController",
+	})
+
+	return err
+}
@@ -0,0 +1,86 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package new_price_oracle_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/controller/new_price_oracle"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("NewPriceOracle repository", func() {
+	var (
+		newPriceOracleRepository new_price_oracle.NewPriceOracleRepository
+		db                       *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		newPriceOracleRepository = new_price_oracle.NewPriceOracleRepository{}
+		newPriceOracleRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.NewPriceOracleModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.NewPriceOracleChecked,
+			LogEventTableName:        "ens.new_price_oracle",
+			TestModel:                test_data.NewPriceOracleModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &newPriceOracleRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a new_price_oracle record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = newPriceOracleRepository.Create(headerID, []interface{}{test_data.NewPriceOracleModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbNewPriceOracle new_price_oracle.NewPriceOracleModel
+			err = db.Get(&dbNewPriceOracle, `SELECT oracle, log_idx, tx_idx, raw_log FROM ens.new_price_oracle WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbNewPriceOracle.Oracle).To(Equal(test_data.NewPriceOracleModel.Oracle))
+			Expect(dbNewPriceOracle.LogIndex).To(Equal(test_data.NewPriceOracleModel.LogIndex))
+			Expect(dbNewPriceOracle.TransactionIndex).To(Equal(test_data.NewPriceOracleModel.TransactionIndex))
+			Expect(dbNewPriceOracle.Raw).To(MatchJSON(test_data.NewPriceOracleModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.NewPriceOracleChecked,
+			Repository:              &newPriceOracleRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
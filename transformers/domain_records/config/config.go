@@ -16,35 +16,133 @@
 package config
 
 import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/vulcanize/vulcanizedb/pkg/config"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/getter"
 )
 
+// The ENSRegistryWithFallback replaced the original registry on mainnet in 2020, reading through to the original registry
+// for any node that has not yet been migrated (set) on it
+const RegistryWithFallbackAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+// Returns the checked_headers column that marks headers checked for an event of a registry
+func RegistryEventId(eventName, address string) string {
+	return strings.ToLower(eventName + "_" + address)
+}
+
+// Returns the checked_headers columns of every event of every registry in the config, which together mark the headers the
+// transformer has checked for registry events, and the block the first registry starts at
+func RegistryEventIds(registryConfig config.ContractConfig) ([]string, int64, error) {
+	if len(registryConfig.Addresses) == 0 {
+		return nil, 0, errors.New("no registry addresses configured")
+	}
+	var ids []string
+	var startingBlock int64 = -1
+	for address := range registryConfig.Addresses {
+		parsed, err := abi.JSON(strings.NewReader(registryConfig.Abis[address]))
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, event := range parsed.Events {
+			ids = append(ids, RegistryEventId(event.Name, address))
+		}
+		if start := registryConfig.StartingBlocks[address]; startingBlock == -1 || start < startingBlock {
+			startingBlock = start
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, startingBlock, nil
+}
+
+// Returns the checked_headers column that marks headers checked for an event of a resolver
+func ResolverEventId(eventName, address string) string {
+	return strings.ToLower(eventName + "_" + address)
+}
+
+// Returns the checked_headers columns of every event in the abi a resolver is watched with, which together mark the headers
+// the transformer has checked for the resolver's events
+func ResolverEventIds(resolverAbi, address string) ([]string, error) {
+	parsed, err := abi.JSON(strings.NewReader(resolverAbi))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(parsed.Events))
+	for _, event := range parsed.Events {
+		ids = append(ids, ResolverEventId(event.Name, address))
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// Configures how the abi of a resolver is detected when it doesn't report its interfaces through ERC-165 (e.g. the earliest resolvers)
+// Resolvers on the allow-list, or whose runtime bytecode hashes to one of the known code hashes, are watched with the fallback abi
+// Upgradeable resolvers (those listed here and EIP-1967 proxies) have their abi detected again every RedetectInterval blocks
+type ResolverDetection struct {
+	LegacyResolvers      map[string]bool
+	LegacyCodeHashes     map[string]bool
+	FallbackAbi          string
+	UpgradeableResolvers map[string]bool
+	RedetectInterval     int64
+}
+
+// Known legacy resolvers and code hashes can be added to the allow-lists here
+var MainnetResolverDetection = ResolverDetection{
+	LegacyResolvers:      map[string]bool{},
+	LegacyCodeHashes:     map[string]bool{},
+	FallbackAbi:          getter.LegacyResolverABI,
+	UpgradeableResolvers: map[string]bool{},
+	RedetectInterval:     5760, // Roughly a day of blocks
+}
+
+var RopstenResolverDetection = ResolverDetection{
+	LegacyResolvers:      map[string]bool{},
+	LegacyCodeHashes:     map[string]bool{},
+	FallbackAbi:          getter.LegacyResolverABI,
+	UpgradeableResolvers: map[string]bool{},
+	RedetectInterval:     5760, // Roughly a day of blocks
+}
+
 var MainnetENSConfig = config.ContractConfig{
 	Name:    "ENS-mainnet",
 	Network: "",
 	Addresses: map[string]bool{
 		"0x314159265dD8dbb310642f98f50C066173C1259b": true,
+		"0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e": true,
 	},
 	Abis: map[string]string{
 		"0x314159265dD8dbb310642f98f50C066173C1259b": `[{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"resolver","outputs":[{"name":"","type":"address"}],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"owner","outputs":[{"name":"","type":"address"}],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"label","type":"bytes32"},{"name":"owner","type":"address"}],"name":"setSubnodeOwner","outputs":[],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"ttl","type":"uint64"}],"name":"setTTL","outputs":[],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"ttl","outputs":[{"name":"","type":"uint64"}],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"resolver","type":"address"}],"name":"setResolver","outputs":[],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"owner","type":"address"}],"name":"setOwner","outputs":[],"payable":false,"type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"owner","type":"address"}],"name":"Transfer","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":true,"name":"label","type":"bytes32"},{"indexed":false,"name":"owner","type":"address"}],"name":"NewOwner","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"resolver","type":"address"}],"name":"NewResolver","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"ttl","type":"uint64"}],"name":"NewTTL","type":"event"}]`,
+		"0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e": `[{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"resolver","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"owner","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"label","type":"bytes32"},{"name":"owner","type":"address"}],"name":"setSubnodeOwner","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"ttl","type":"uint64"}],"name":"setTTL","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"ttl","outputs":[{"name":"","type":"uint64"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"resolver","type":"address"}],"name":"setResolver","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"owner","type":"address"}],"name":"setOwner","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"owner","type":"address"},{"name":"resolver","type":"address"},{"name":"ttl","type":"uint64"}],"name":"setRecord","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"label","type":"bytes32"},{"name":"owner","type":"address"},{"name":"resolver","type":"address"},{"name":"ttl","type":"uint64"}],"name":"setSubnodeRecord","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"name":"setApprovalForAll","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"name":"isApprovedForAll","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"recordExists","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"old","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":true,"name":"label","type":"bytes32"},{"indexed":false,"name":"owner","type":"address"}],"name":"NewOwner","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"owner","type":"address"}],"name":"Transfer","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"resolver","type":"address"}],"name":"NewResolver","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"ttl","type":"uint64"}],"name":"NewTTL","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"operator","type":"address"},{"indexed":false,"name":"approved","type":"bool"}],"name":"ApprovalForAll","type":"event"}]`,
 	},
 	Events: map[string][]string{
 		"0x314159265dD8dbb310642f98f50C066173C1259b": []string{},
+		"0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e": []string{},
 	},
 	EventArgs: map[string][]string{
 		"0x314159265dD8dbb310642f98f50C066173C1259b": []string{},
+		"0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e": []string{},
 	},
 	Methods: map[string][]string{
 		"0x314159265dD8dbb310642f98f50C066173C1259b": []string{},
+		"0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e": []string{},
 	},
 	MethodArgs: map[string][]string{
 		"0x314159265dD8dbb310642f98f50C066173C1259b": []string{},
+		"0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e": []string{},
 	},
 	StartingBlocks: map[string]int64{
 		"0x314159265dD8dbb310642f98f50C066173C1259b": 3327417,
+		"0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e": 9380380,
 	},
 	Piping: map[string]bool{
 		"0x314159265dD8dbb310642f98f50C066173C1259b": false,
+		"0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e": false,
 	},
 }
 
@@ -0,0 +1,145 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/contract"
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/types"
+)
+
+// Converts the watched event logs of ENS contracts into custom logs
+// Mirrors vulcanizedb's light converter, but also resolves the fixed size integers and byte arrays that resolver events emit
+type Converter struct {
+	ContractInfo *contract.Contract
+}
+
+func (c *Converter) Update(info *contract.Contract) {
+	c.ContractInfo = info
+}
+
+// Convert the given watched event logs into types.Logs; returns a map of event names to a slice of their converted logs
+func (c *Converter) ConvertBatch(logs []gethTypes.Log, events map[string]types.Event, headerID int64) (map[string][]types.Log, error) {
+	boundContract := bind.NewBoundContract(common.HexToAddress(c.ContractInfo.Address), c.ContractInfo.ParsedAbi, nil, nil, nil)
+	eventsToLogs := make(map[string][]types.Log)
+	for _, event := range events {
+		eventsToLogs[event.Name] = make([]types.Log, 0, len(logs))
+		for _, log := range logs {
+			if len(log.Topics) == 0 || event.Sig() != log.Topics[0] {
+				continue
+			}
+			values := make(map[string]interface{})
+			err := boundContract.UnpackLogIntoMap(values, event.Name, log)
+			if err != nil {
+				return nil, err
+			}
+			// Postgres cannot handle custom types, so we resolve everything to strings
+			strValues := make(map[string]string, len(values))
+			seenAddrs := make([]interface{}, 0, len(values))
+			seenHashes := make([]interface{}, 0, len(values))
+			for fieldName, input := range values {
+				str, err := toString(input)
+				if err != nil {
+					return nil, err
+				}
+				strValues[fieldName] = str
+				switch v := input.(type) {
+				case common.Address:
+					seenAddrs = append(seenAddrs, v)
+				case common.Hash:
+					seenHashes = append(seenHashes, v)
+				case []byte, [32]byte:
+					// Collect byte arrays of size 32 as hashes
+					if b := hexutil.MustDecode(str); len(b) == common.HashLength {
+						seenHashes = append(seenHashes, common.BytesToHash(b))
+					}
+				}
+			}
+
+			// Only hold onto logs that pass our argument filter, if any
+			if !c.ContractInfo.PassesEventFilter(strValues) {
+				continue
+			}
+			raw, err := json.Marshal(log)
+			if err != nil {
+				return nil, err
+			}
+			eventsToLogs[event.Name] = append(eventsToLogs[event.Name], types.Log{
+				LogIndex:         log.Index,
+				Values:           strValues,
+				Raw:              raw,
+				TransactionIndex: log.TxIndex,
+				Id:               headerID,
+			})
+
+			// Cache emitted values that pass the argument filter if their caching is turned on
+			if c.ContractInfo.EmittedAddrs != nil {
+				c.ContractInfo.AddEmittedAddr(seenAddrs...)
+			}
+			if c.ContractInfo.EmittedHashes != nil {
+				c.ContractInfo.AddEmittedHash(seenHashes...)
+			}
+		}
+	}
+
+	return eventsToLogs, nil
+}
+
+// Resolves an unpacked event value to a string
+// Integers are rendered in base 10, and byte slices and fixed size byte arrays as hex
+func toString(input interface{}) (string, error) {
+	switch v := input.(type) {
+	case *big.Int:
+		return v.String(), nil
+	case common.Address:
+		return v.String(), nil
+	case common.Hash:
+		return v.String(), nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []byte:
+		return hexutil.Encode(v), nil
+	}
+
+	value := reflect.ValueOf(input)
+	switch value.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(b), value)
+			return hexutil.Encode(b), nil
+		}
+	}
+
+	return "", fmt.Errorf("error: unhandled abi type %T", input)
+}
@@ -0,0 +1,95 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package converter_test
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/contract"
+	sharedTypes "github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/types"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/converter"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+)
+
+var _ = Describe("Converter", func() {
+	var c converter.Converter
+	var events map[string]sharedTypes.Event
+	// Some of the fixtures share a placeholder topic, give them their event's signature
+	withSignature := func(log types.Log, signature string) types.Log {
+		log.Topics = append([]common.Hash{common.HexToHash(signature)}, log.Topics[1:]...)
+		return log
+	}
+	addrChangedLog := withSignature(test_data.EthAddrChangedLog, test_data.AddrChangedSignature)
+
+	BeforeEach(func() {
+		parsedAbi, err := abi.JSON(strings.NewReader(test_data.CompleteResolverAbi))
+		Expect(err).ToNot(HaveOccurred())
+		events = make(map[string]sharedTypes.Event)
+		for name, event := range parsedAbi.Events {
+			events[name] = sharedTypes.NewEvent(event)
+		}
+		c = converter.Converter{}
+		c.Update(&contract.Contract{
+			Address:    test_data.ResolverAddress,
+			ParsedAbi:  parsedAbi,
+			Events:     events,
+			FilterArgs: map[string]bool{},
+		})
+	})
+
+	It("Matches logs to their events by signature", func() {
+		logs := []types.Log{addrChangedLog, test_data.EthVersionChangedLog}
+
+		converted, err := c.ConvertBatch(logs, events, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(converted["AddrChanged"])).To(Equal(1))
+		Expect(len(converted["VersionChanged"])).To(Equal(1))
+		Expect(len(converted["NameChanged"])).To(Equal(0))
+		Expect(converted["AddrChanged"][0].Id).To(Equal(int64(1)))
+		Expect(converted["AddrChanged"][0].LogIndex).To(Equal(test_data.EthAddrChangedLog.Index))
+		Expect(converted["AddrChanged"][0].TransactionIndex).To(Equal(test_data.EthAddrChangedLog.TxIndex))
+	})
+
+	It("Resolves fixed size integers in base 10", func() {
+		converted, err := c.ConvertBatch([]types.Log{test_data.EthVersionChangedLog}, events, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(converted["VersionChanged"][0].Values["newVersion"]).To(Equal("1"))
+		Expect(converted["VersionChanged"][0].Values["node"]).To(Equal(test_data.EthVersionChangedLog.Topics[1].Hex()))
+	})
+
+	It("Resolves fixed size byte arrays to hex", func() {
+		converted, err := c.ConvertBatch([]types.Log{withSignature(test_data.EthPubkeyChangedLog, test_data.PubkeyChangedSignature)}, events, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(converted["PubkeyChanged"][0].Values["x"]).To(Equal(test_data.PubkeyChangedModel.X))
+		Expect(converted["PubkeyChanged"][0].Values["y"]).To(Equal(test_data.PubkeyChangedModel.Y))
+	})
+
+	It("Resolves indexed addresses and non-indexed bools", func() {
+		converted, err := c.ConvertBatch([]types.Log{test_data.EthAuthorisationChangedLog}, events, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(converted["AuthorisationChanged"][0].Values["owner"]).To(Equal(test_data.AuthorisationChangedModel.Owner))
+		Expect(converted["AuthorisationChanged"][0].Values["target"]).To(Equal(test_data.AuthorisationChangedModel.Target))
+		Expect(converted["AuthorisationChanged"][0].Values["isAuthorised"]).To(Equal("true"))
+	})
+})
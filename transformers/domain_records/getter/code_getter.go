@@ -0,0 +1,84 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package getter
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
+)
+
+// Storage slot an EIP-1967 proxy keeps its implementation address in (keccak256("eip1967.proxy.implementation") - 1)
+var ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// Resolvers that don't support ERC-165 can only be recognised by their bytecode, which the BlockChain interface doesn't expose
+type CodeGetter interface {
+	GetCode(address string, blockNumber int64) ([]byte, error)
+	GetStorageAt(address string, slot common.Hash, blockNumber int64) (common.Hash, error)
+}
+
+type codeGetter struct {
+	client core.RpcClient
+}
+
+func NewCodeGetter(client core.RpcClient) *codeGetter {
+	return &codeGetter{
+		client: client,
+	}
+}
+
+// Dials the eth node at the given ipc path or url for a code getter
+// Returns nil if the node can't be dialed, in which case resolvers can only be detected by the allow-list
+func DialCodeGetter(ipcPath string) CodeGetter {
+	rawRpcClient, err := rpc.Dial(ipcPath)
+	if err != nil {
+		log.Warnf("unable to dial eth node at %s, resolvers will not be detected by their bytecode: %v", ipcPath, err)
+		return nil
+	}
+
+	return NewCodeGetter(client.NewRpcClient(rawRpcClient, ipcPath))
+}
+
+// Fetches the runtime bytecode of the contract at the given address at the given blockheight, or the latest block if it is negative
+func (g *codeGetter) GetCode(address string, blockNumber int64) ([]byte, error) {
+	var code hexutil.Bytes
+	err := g.client.CallContext(context.Background(), &code, "eth_getCode", common.HexToAddress(address), toBlockArg(blockNumber))
+
+	return code, err
+}
+
+// Fetches the value of a storage slot of the contract at the given address at the given blockheight, or the latest block if it is negative
+func (g *codeGetter) GetStorageAt(address string, slot common.Hash, blockNumber int64) (common.Hash, error) {
+	var value hexutil.Bytes
+	err := g.client.CallContext(context.Background(), &value, "eth_getStorageAt", common.HexToAddress(address), slot, toBlockArg(blockNumber))
+
+	return common.BytesToHash(value), err
+}
+
+func toBlockArg(blockNumber int64) string {
+	if blockNumber < 0 {
+		return "latest"
+	}
+	return hexutil.EncodeBig(big.NewInt(blockNumber))
+}
@@ -0,0 +1,60 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package getter_test
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/getter"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers/mocks"
+)
+
+var _ = Describe("CodeGetter", func() {
+	var rpcClient *mocks.MockRpcClient
+	resolver := "0x1da022710dF5002339274AaDEe8D58218e9D6AB5"
+
+	BeforeEach(func() {
+		rpcClient = mocks.NewMockRpcClient()
+	})
+
+	It("Fetches the bytecode of a contract at the given blockheight", func() {
+		rpcClient.Code[common.HexToAddress(resolver)] = []byte{0x60, 0x60, 0x60, 0x40}
+
+		code, err := getter.NewCodeGetter(rpcClient).GetCode(resolver, 3648359)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(code).To(Equal([]byte{0x60, 0x60, 0x60, 0x40}))
+		Expect(rpcClient.PassedBlockNumber).To(Equal("0x37ab67"))
+	})
+
+	It("Fetches from the latest block when the blockheight is negative", func() {
+		code, err := getter.NewCodeGetter(rpcClient).GetCode(resolver, -1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(code).To(BeEmpty())
+		Expect(rpcClient.PassedBlockNumber).To(Equal("latest"))
+	})
+
+	It("Fetches a storage slot of a contract", func() {
+		implementation := common.HexToHash("0x4976fb03C32e5B8cfe2b6cCB31c09Ba78EBaBa41")
+		rpcClient.Storage[common.HexToAddress(resolver)] = map[common.Hash]common.Hash{getter.ImplementationSlot: implementation}
+
+		value, err := getter.NewCodeGetter(rpcClient).GetStorageAt(resolver, getter.ImplementationSlot, -1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal(implementation))
+	})
+})
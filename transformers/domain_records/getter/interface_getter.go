@@ -0,0 +1,123 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package getter
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/constants"
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/fetcher"
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/getter"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+// Interface ids of resolver methods vulcanizedb's interface getter doesn't check, and the events resolvers that support them emit
+const (
+	MulticoinAddrInterfaceId        = "0xf1cb7e06"
+	InterfaceImplementerInterfaceId = "0x124a319c"
+	RecordVersionsInterfaceId       = "0xd700ff33"
+	DNSRecordInterfaceId            = "0xa8fa5682"
+	DNSZonehashInterfaceId          = "0x5c98042b"
+
+	AddressChangedInterface       = `{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"coinType","type":"uint256"},{"indexed":false,"name":"newAddress","type":"bytes"}],"name":"AddressChanged","type":"event"}`
+	InterfaceChangedInterface     = `{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":true,"name":"interfaceID","type":"bytes4"},{"indexed":false,"name":"implementer","type":"address"}],"name":"InterfaceChanged","type":"event"}`
+	AuthorisationChangedInterface = `{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"target","type":"address"},{"indexed":false,"name":"isAuthorised","type":"bool"}],"name":"AuthorisationChanged","type":"event"}`
+	VersionChangedInterface       = `{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"newVersion","type":"uint64"}],"name":"VersionChanged","type":"event"}`
+	DNSRecordChangedInterface     = `{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"name","type":"bytes"},{"indexed":false,"name":"resource","type":"uint16"},{"indexed":false,"name":"record","type":"bytes"}],"name":"DNSRecordChanged","type":"event"}`
+	DNSRecordDeletedInterface     = `{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"name","type":"bytes"},{"indexed":false,"name":"resource","type":"uint16"}],"name":"DNSRecordDeleted","type":"event"}`
+	DNSZonehashChangedInterface   = `{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"lastzonehash","type":"bytes"},{"indexed":false,"name":"zonehash","type":"bytes"}],"name":"DNSZonehashChanged","type":"event"}`
+)
+
+// The events added to a resolver's abi for each interface it supports, in the order they are checked
+// Authorisations are not an interface of their own, they were introduced alongside interfaceImplementer
+var extraInterfaces = []struct {
+	id     string
+	events []string
+}{
+	{MulticoinAddrInterfaceId, []string{AddressChangedInterface}},
+	{InterfaceImplementerInterfaceId, []string{InterfaceChangedInterface, AuthorisationChangedInterface}},
+	{RecordVersionsInterfaceId, []string{VersionChangedInterface}},
+	{DNSRecordInterfaceId, []string{DNSRecordChangedInterface, DNSRecordDeletedInterface}},
+	{DNSZonehashInterfaceId, []string{DNSZonehashChangedInterface}},
+}
+
+// The abi assumed for legacy resolvers that predate ERC-165, made up of the events of the original public resolver
+var LegacyResolverABI = appendToABI("[]", []string{
+	constants.AddrChangeInterface,
+	constants.ContentChangeInterface,
+	constants.NameChangeInterface,
+	constants.AbiChangeInterface,
+	constants.PubkeyChangeInterface,
+})
+
+// Extends the resolver interfaces checked by vulcanizedb's interface getter with the ones it doesn't know about
+type interfaceGetter struct {
+	getter.InterfaceGetter
+	fetcher.Fetcher
+}
+
+func NewInterfaceGetter(blockChain core.BlockChain) *interfaceGetter {
+	return &interfaceGetter{
+		InterfaceGetter: getter.NewInterfaceGetter(blockChain),
+		Fetcher: fetcher.Fetcher{
+			BlockChain: blockChain,
+		},
+	}
+}
+
+// Constructs the resolver's abi from the interfaces it supports, adding the events of the interfaces vulcanizedb doesn't know about
+// Returns an empty abi if the resolver doesn't implement supportsInterface
+func (g *interfaceGetter) GetABI(resolverAddr string, blockNumber int64) string {
+	abiStr := g.InterfaceGetter.GetABI(resolverAddr, blockNumber)
+	if abiStr == "" {
+		return ""
+	}
+	var events []string
+	for _, extra := range extraInterfaces {
+		if g.supportsInterface(resolverAddr, extra.id, blockNumber) {
+			events = append(events, extra.events...)
+		}
+	}
+
+	return appendToABI(abiStr, events)
+}
+
+func (g *interfaceGetter) supportsInterface(resolverAddr, interfaceId string, blockNumber int64) bool {
+	var id [4]byte
+	copy(id[:], hexutil.MustDecode(interfaceId))
+	supports, err := g.Fetcher.FetchBool("supportsInterface", constants.SupportsInterfaceABI, resolverAddr, blockNumber, []interface{}{id})
+
+	return err == nil && supports
+}
+
+// Appends the given abi entries to a json abi array
+func appendToABI(abiStr string, entries []string) string {
+	if len(entries) == 0 {
+		return abiStr
+	}
+	// A resolver that supports none of the interfaces vulcanizedb checks comes back as a bare closing bracket
+	if abiStr == "]" || abiStr == "[]" {
+		abiStr = "["
+	} else {
+		abiStr = abiStr[:len(abiStr)-1] + ","
+	}
+	for _, entry := range entries {
+		abiStr += entry + ","
+	}
+
+	return abiStr[:len(abiStr)-1] + "]"
+}
@@ -0,0 +1,110 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package getter_test
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/constants"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/getter"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers/mocks"
+)
+
+var _ = Describe("InterfaceGetter", func() {
+	var blockChain *mocks.MockBlockChain
+	resolver := "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"
+	var multicoinId [4]byte
+	copy(multicoinId[:], hexutil.MustDecode(getter.MulticoinAddrInterfaceId))
+	var implementerId [4]byte
+	copy(implementerId[:], hexutil.MustDecode(getter.InterfaceImplementerInterfaceId))
+	var versionsId [4]byte
+	copy(versionsId[:], hexutil.MustDecode(getter.RecordVersionsInterfaceId))
+	var dnsRecordId [4]byte
+	copy(dnsRecordId[:], hexutil.MustDecode(getter.DNSRecordInterfaceId))
+	var dnsZonehashId [4]byte
+	copy(dnsZonehashId[:], hexutil.MustDecode(getter.DNSZonehashInterfaceId))
+
+	eventNames := func(abiStr string) []string {
+		var entries []struct{ Name string }
+		err := json.Unmarshal([]byte(abiStr), &entries)
+		Expect(err).ToNot(HaveOccurred())
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		blockChain = mocks.NewMockBlockChain()
+		blockChain.SupportedInterfaces[constants.MetaSig.Bytes()] = true
+	})
+
+	It("Adds the AddressChanged event for resolvers that support multicoin addresses", func() {
+		blockChain.SupportedInterfaces[constants.AddrChangeSig.Bytes()] = true
+		blockChain.SupportedInterfaces[multicoinId] = true
+
+		abiStr := getter.NewInterfaceGetter(blockChain).GetABI(resolver, 9412610)
+		Expect(eventNames(abiStr)).To(Equal([]string{"AddrChanged", "AddressChanged"}))
+	})
+
+	It("Adds the AddressChanged event when it is the only supported interface", func() {
+		blockChain.SupportedInterfaces[multicoinId] = true
+
+		abiStr := getter.NewInterfaceGetter(blockChain).GetABI(resolver, 9412610)
+		Expect(eventNames(abiStr)).To(Equal([]string{"AddressChanged"}))
+	})
+
+	It("Adds the InterfaceChanged, AuthorisationChanged and VersionChanged events for resolvers that support them", func() {
+		blockChain.SupportedInterfaces[constants.AddrChangeSig.Bytes()] = true
+		blockChain.SupportedInterfaces[multicoinId] = true
+		blockChain.SupportedInterfaces[implementerId] = true
+		blockChain.SupportedInterfaces[versionsId] = true
+
+		abiStr := getter.NewInterfaceGetter(blockChain).GetABI(resolver, 9412610)
+		Expect(eventNames(abiStr)).To(Equal([]string{"AddrChanged", "AddressChanged", "InterfaceChanged", "AuthorisationChanged", "VersionChanged"}))
+	})
+
+	It("Adds the DNS record and zonehash events for resolvers that support them", func() {
+		blockChain.SupportedInterfaces[constants.AddrChangeSig.Bytes()] = true
+		blockChain.SupportedInterfaces[dnsRecordId] = true
+		blockChain.SupportedInterfaces[dnsZonehashId] = true
+
+		abiStr := getter.NewInterfaceGetter(blockChain).GetABI(resolver, 9412610)
+		Expect(eventNames(abiStr)).To(Equal([]string{"AddrChanged", "DNSRecordChanged", "DNSRecordDeleted", "DNSZonehashChanged"}))
+	})
+
+	It("Leaves the abi unchanged for resolvers that don't support multicoin addresses", func() {
+		blockChain.SupportedInterfaces[constants.AddrChangeSig.Bytes()] = true
+
+		abiStr := getter.NewInterfaceGetter(blockChain).GetABI(resolver, 9412610)
+		Expect(abiStr).ToNot(ContainSubstring("AddressChanged"))
+		Expect(eventNames(abiStr)).To(ContainElement("AddrChanged"))
+	})
+
+	It("Returns an empty abi for contracts that don't implement supportsInterface", func() {
+		blockChain.SupportedInterfaces[constants.MetaSig.Bytes()] = false
+		blockChain.SupportedInterfaces[multicoinId] = true
+
+		abiStr := getter.NewInterfaceGetter(blockChain).GetABI(resolver, 9412610)
+		Expect(abiStr).To(Equal(""))
+	})
+})
@@ -0,0 +1,80 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package getter
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/fetcher"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+// Abi for the resolver text(bytes32 node, string key) method
+const TextABI = `[{"constant":true,"inputs":[{"name":"node","type":"bytes32"},{"name":"key","type":"string"}],"name":"text","outputs":[{"name":"","type":"string"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// Returned when the resolver can't give a value for the key, e.g. because text() reverts or the resolver doesn't implement it
+// Unlike a failure to reach the eth node, fetching the value again won't give a different result
+var ErrNoTextValue = errors.New("resolver returned no text value")
+
+// TextChanged events only emit the key that changed, so the value has to be fetched from the resolver
+type TextGetter interface {
+	GetText(resolverAddr, node, key string, blockNumber int64) (string, error)
+}
+
+type textGetter struct {
+	fetcher.Fetcher
+}
+
+func NewTextGetter(blockChain core.BlockChain) *textGetter {
+	return &textGetter{
+		Fetcher: fetcher.Fetcher{
+			BlockChain: blockChain,
+		},
+	}
+}
+
+// Calls the resolver's text method for the given node and key at the given blockheight
+// Fetching values for past blocks requires the underlying eth node to be an archive node, other nodes return no value for them
+// Returns ErrNoTextValue if the call gives no value, and the error itself if the eth node can't be reached
+func (g *textGetter) GetText(resolverAddr, node, key string, blockNumber int64) (string, error) {
+	args := make([]interface{}, 2)
+	args[0] = [32]byte(common.HexToHash(node))
+	args[1] = key
+	var value string
+	err := g.Fetcher.BlockChain.FetchContractData(TextABI, resolverAddr, "text", args, &value, blockNumber)
+	if err != nil && isCallResult(err) {
+		log.Debugf("no text value for key %s on node %s from resolver %s at block %d: %v", key, node, resolverAddr, blockNumber, err)
+		return "", ErrNoTextValue
+	}
+
+	return value, err
+}
+
+// Errors the eth node returns for the call (execution reverted, missing trie node) and errors unpacking the call's output
+// (empty output from a resolver without a text method) are the result of the call, rather than a failure to make it
+func isCallResult(err error) bool {
+	if _, ok := err.(rpc.Error); ok {
+		return true
+	}
+
+	return strings.HasPrefix(err.Error(), "abi:")
+}
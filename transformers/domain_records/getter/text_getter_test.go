@@ -0,0 +1,81 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package getter_test
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/getter"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers/mocks"
+)
+
+var _ = Describe("TextGetter", func() {
+	var blockChain *mocks.MockBlockChain
+	var textGetter getter.TextGetter
+	node := "0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"
+	resolver := "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"
+
+	BeforeEach(func() {
+		blockChain = mocks.NewMockBlockChain()
+		textGetter = getter.NewTextGetter(blockChain)
+	})
+
+	It("Calls the resolver's text method for the node and key at the given blockheight", func() {
+		blockChain.StringValues["avatar"] = "https://example.com/avatar.png"
+
+		value, err := textGetter.GetText(resolver, node, "avatar", 6885695)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("https://example.com/avatar.png"))
+		blockChain.AssertFetchContractDataCalledWith(resolver, "text", []interface{}{[32]byte(common.HexToHash(node)), "avatar"}, 6885695)
+	})
+
+	It("Returns an empty value if the key has not been set", func() {
+		value, err := textGetter.GetText(resolver, node, "url", 6885695)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal(""))
+	})
+
+	It("Returns an error if the eth node can't be reached", func() {
+		blockChain.SetFetchContractDataErr(fakes.FakeError)
+
+		_, err := textGetter.GetText(resolver, node, "avatar", 6885695)
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(Equal(getter.ErrNoTextValue))
+		Expect(err.Error()).To(ContainSubstring(fakes.FakeError.Error()))
+	})
+
+	It("Returns ErrNoTextValue if the call reverts", func() {
+		blockChain.SetFetchContractDataErr(mocks.RpcError{Code: -32000, Message: "execution reverted"})
+
+		value, err := textGetter.GetText(resolver, node, "avatar", 6885695)
+		Expect(err).To(Equal(getter.ErrNoTextValue))
+		Expect(value).To(Equal(""))
+	})
+
+	It("Returns ErrNoTextValue if the resolver returns no output", func() {
+		blockChain.SetFetchContractDataErr(errors.New("abi: unmarshalling empty output"))
+
+		value, err := textGetter.GetText(resolver, node, "avatar", 6885695)
+		Expect(err).To(Equal(getter.ErrNoTextValue))
+		Expect(value).To(Equal(""))
+	})
+})
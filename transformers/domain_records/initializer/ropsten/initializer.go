@@ -17,12 +17,22 @@
 package ropsten
 
 import (
+	"github.com/spf13/viper"
+
 	"github.com/vulcanize/vulcanizedb/libraries/shared/transformer"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
 
 	"github.com/vulcanize/ens_transformers/transformers/domain_records"
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/config"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/getter"
 )
 
-var GenericTransformerInitializer transformer.ContractTransformerInitializer = domain_records.Transformer{
-	RegistryConfig: config.RopstenENSConfig,
-}.NewTransformer
+// Resolver bytecode is fetched over rpc from the eth node the client is configured with
+var GenericTransformerInitializer transformer.ContractTransformerInitializer = func(db *postgres.DB, bc core.BlockChain) transformer.ContractTransformer {
+	return domain_records.Transformer{
+		RegistryConfig:    config.RopstenENSConfig,
+		ResolverDetection: config.RopstenResolverDetection,
+		CodeGetter:        getter.DialCodeGetter(viper.GetString("client.ipcPath")),
+	}.NewTransformer(db, bc)
+}
@@ -17,21 +17,241 @@
 package models
 
 type DomainModel struct {
-	Name           string `db:"resolved_name"`
-	NameHash       string `db:"name_hash"`
-	BlockNumber    int64  `db:"block_number"`
-	LabelHash      string `db:"label_hash"`
-	ParentHash     string `db:"parent_hash"`
-	Owner          string `db:"owner_addr"`
-	ResolverAddr   string `db:"resolver_addr"`
-	PointsToAddr   string `db:"points_to_addr"`
-	Content        string `db:"content_"`
-	ContentType    string `db:"content_type"`
-	PubKeyX        string `db:"pub_key_x"`
-	PubKeyY        string `db:"pub_key_y"`
-	TTL            string `db:"ttl"`
-	TextKey        string `db:"text_key"`
-	IndexedTextKey string `db:"indexed_text_key"`
-	Multihash      string `db:"multihash"`
-	Contenthash    string `db:"contenthash"`
+	Name                string `db:"resolved_name"`
+	NameHash            string `db:"name_hash"`
+	BlockNumber         int64  `db:"block_number"`
+	HeaderID            int64  `db:"header_id"`
+	LabelHash           string `db:"label_hash"`
+	ParentHash          string `db:"parent_hash"`
+	Owner               string `db:"owner_addr"`
+	ResolverAddr        string `db:"resolver_addr"`
+	PointsToAddr        string `db:"points_to_addr"`
+	Content             string `db:"content_"`
+	ContentType         string `db:"content_type"`
+	PubKeyX             string `db:"pub_key_x"`
+	PubKeyY             string `db:"pub_key_y"`
+	TTL                 string `db:"ttl"`
+	TextKey             string `db:"text_key"`
+	IndexedTextKey      string `db:"indexed_text_key"`
+	TextValue           string `db:"text_value"`
+	Multihash           string `db:"multihash"`
+	Contenthash         string `db:"contenthash"`
+	ContenthashProtocol string `db:"contenthash_protocol"`
+	ContenthashURI      string `db:"contenthash_uri"`
+	// The content of whichever of Content, Multihash and Contenthash was changed most recently
+	EffectiveContentProtocol string `db:"effective_content_protocol"`
+	EffectiveContentURI      string `db:"effective_content_uri"`
+	FullName                 string `db:"full_name"`
+	Migrated                 bool   `db:"migrated"`
+}
+
+type ResolverModel struct {
+	Address       string `db:"resolver_addr"`
+	StartingBlock int64  `db:"starting_block"`
+	Abi           string `db:"abi"`
+	Valid         bool   `db:"valid"`
+	Upgradeable   bool   `db:"upgradeable"`
+	DetectedAt    int64  `db:"detected_at"`
+}
+
+// Methods a resolver's abi is detected with
+const (
+	DetectionInterface = "erc165"
+	DetectionAllowList = "allow_list"
+	DetectionBytecode  = "bytecode"
+	DetectionNone      = "none"
+)
+
+type ResolverDetectionModel struct {
+	Address     string `db:"resolver_addr"`
+	BlockNumber int64  `db:"block_number"`
+	Method      string `db:"method"`
+	CodeHash    string `db:"code_hash"`
+	Abi         string `db:"abi"`
+	Upgradeable bool   `db:"upgradeable"`
+}
+
+type TextRecordModel struct {
+	BlockNumber  int64  `db:"block_number"`
+	HeaderID     int64  `db:"header_id"`
+	NameHash     string `db:"name_hash"`
+	Key          string `db:"key"`
+	Value        string `db:"value"`
+	ResolverAddr string `db:"resolver_addr"`
+}
+
+type LabelModel struct {
+	LabelHash string `db:"label_hash"`
+	Label     string `db:"label"`
+	Source    string `db:"source"`
+}
+
+type ApprovalModel struct {
+	BlockNumber  int64  `db:"block_number"`
+	HeaderID     int64  `db:"header_id"`
+	RegistryAddr string `db:"registry_addr"`
+	Owner        string `db:"owner_addr"`
+	Operator     string `db:"operator_addr"`
+	Approved     bool   `db:"approved"`
+}
+
+// Statuses of a .eth name's term as of a block
+const (
+	ExpiryStatusActive   = "active"
+	ExpiryStatusGrace    = "grace"
+	ExpiryStatusExpired  = "expired"
+	ExpiryStatusReleased = "released"
+)
+
+type ExpiryModel struct {
+	LabelHash       string `db:"label_hash"`
+	BlockNumber     int64  `db:"block_number"`
+	RegisteredAt    string `db:"registered_at"`
+	Expires         string `db:"expires"`
+	GracePeriodEnds string `db:"grace_period_ends"`
+	Status          string `db:"status"`
+}
+
+// Sources of reverse records
+const (
+	ReverseSourceRegistrar = "ReverseRegistrar"
+	ReverseSourceNewOwner  = "NewOwner"
+)
+
+type ReverseRecordModel struct {
+	BlockNumber int64  `db:"block_number"`
+	HeaderID    int64  `db:"header_id"`
+	Address     string `db:"address"`
+	Node        string `db:"node"`
+	Source      string `db:"source"`
+}
+
+type PrimaryNameModel struct {
+	Address     string `db:"address"`
+	Node        string `db:"node"`
+	BlockNumber int64  `db:"block_number"`
+	Name        string `db:"name"`
+	Verified    bool   `db:"verified"`
+}
+
+// The state of a node as of a snapshot's block; BlockNumber is the block the node last changed at
+type SnapshotModel struct {
+	NameHash     string `db:"name_hash"`
+	FullName     string `db:"full_name"`
+	ParentHash   string `db:"parent_hash"`
+	Owner        string `db:"owner_addr"`
+	ResolverAddr string `db:"resolver_addr"`
+	PointsToAddr string `db:"points_to_addr"`
+	TTL          string `db:"ttl"`
+	BlockNumber  int64  `db:"block_number"`
+}
+
+// Narrows a snapshot to the nodes whose state matches every non-empty field; addresses are matched case-insensitively
+type SnapshotFilter struct {
+	ParentHash   string
+	Owner        string
+	ResolverAddr string
+	PointsToAddr string
+}
+
+type CoinAddressModel struct {
+	BlockNumber  int64  `db:"block_number"`
+	HeaderID     int64  `db:"header_id"`
+	NameHash     string `db:"name_hash"`
+	CoinType     string `db:"coin_type"`
+	Address      string `db:"address"`
+	RawAddress   string `db:"raw_address"`
+	ResolverAddr string `db:"resolver_addr"`
+}
+
+// Selects entities of the subgraph schema; a BlockNumber of 0 selects them as of the latest synced header
+// Where maps a field, optionally suffixed with a comparison such as _not, _in, _gt or _contains, to the value it is compared with
+// First is always applied, so a query with a First of 0 selects no entities
+type EntityQuery struct {
+	Where          map[string]interface{}
+	OrderBy        string
+	OrderDirection string
+	First          int
+	Skip           int
+	BlockNumber    int64
+}
+
+type SubgraphDomainModel struct {
+	NameHash     string `db:"name_hash"`
+	FullName     string `db:"full_name"`
+	Label        string `db:"label"`
+	LabelHash    string `db:"label_hash"`
+	ParentHash   string `db:"parent_hash"`
+	Owner        string `db:"owner_addr"`
+	ResolverAddr string `db:"resolver_addr"`
+	PointsToAddr string `db:"points_to_addr"`
+	TTL          string `db:"ttl"`
+	Migrated     bool   `db:"migrated"`
+	BlockNumber  int64  `db:"block_number"`
+}
+
+type SubgraphAccountModel struct {
+	Address string `db:"address"`
+}
+
+type SubgraphResolverModel struct {
+	ID           string `db:"id"`
+	Address      string `db:"resolver_addr"`
+	NameHash     string `db:"name_hash"`
+	PointsToAddr string `db:"points_to_addr"`
+	Contenthash  string `db:"contenthash"`
+	BlockNumber  int64  `db:"block_number"`
+}
+
+type SubgraphRegistrationModel struct {
+	LabelHash    string `db:"label_hash"`
+	Label        string `db:"label"`
+	RegisteredAt string `db:"registered_at"`
+	Expires      string `db:"expires"`
+	Registrant   string `db:"registrant"`
+	BlockNumber  int64  `db:"block_number"`
+}
+
+// Types of the registry's events, named after their subgraph entities
+const (
+	DomainEventTransfer    = "Transfer"
+	DomainEventNewOwner    = "NewOwner"
+	DomainEventNewResolver = "NewResolver"
+	DomainEventNewTTL      = "NewTTL"
+)
+
+type SubgraphDomainEventModel struct {
+	ID              string `db:"id"`
+	Type            string `db:"type"`
+	NameHash        string `db:"name_hash"`
+	ParentHash      string `db:"parent_hash"`
+	Owner           string `db:"owner_addr"`
+	ResolverAddr    string `db:"resolver_addr"`
+	TTL             string `db:"ttl"`
+	TransactionHash string `db:"transaction_hash"`
+	BlockNumber     int64  `db:"block_number"`
+}
+
+// A resource record of a node's current DNS zone on a resolver; Rdata is hex encoded
+type DNSRecordModel struct {
+	Resolver    string `db:"resolver"`
+	Node        string `db:"node"`
+	Name        string `db:"name"`
+	Type        int    `db:"type"`
+	Class       int    `db:"class"`
+	TTL         int64  `db:"ttl"`
+	Rdata       string `db:"rdata"`
+	BlockNumber int64  `db:"block_number"`
+}
+
+// The kinds of values an exported column holds; every value of the column is either nil or of its kind's Go type
+const (
+	ExportString = "string"
+	ExportInt    = "int64"
+	ExportBool   = "bool"
+)
+
+type ExportColumn struct {
+	Name string
+	Kind string
 }
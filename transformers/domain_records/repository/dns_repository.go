@@ -0,0 +1,153 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_record_changed"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_record_deleted"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_zonehash_changed"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/version_changed"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type DNSRepository interface {
+	GetZoneRecords(resolverAddr, node, name string) ([]models.DNSRecordModel, error)
+	GetZone(resolverAddr, node string) ([]models.DNSRecordModel, error)
+	GetZones(resolverAddrs, nodes []string) ([]models.DNSRecordModel, error)
+	GetZonesChangedAt(resolverAddrs, nodes []string) (int64, error)
+	CreateDNSEvents(headerID int64, eventName string, models []interface{}) error
+}
+
+type dnsRepository struct {
+	db *postgres.DB
+}
+
+func NewDNSRepository(db *postgres.DB) *dnsRepository {
+	return &dnsRepository{
+		db: db,
+	}
+}
+
+// Gets the resource records of every type the node's current zone on the resolver holds for the given name, e.g. www.vitalik.eth.
+// Names and resolver addresses are matched case-insensitively
+func (r *dnsRepository) GetZoneRecords(resolverAddr, node, name string) ([]models.DNSRecordModel, error) {
+	var records []models.DNSRecordModel
+	err := r.db.Select(&records,
+		`SELECT resolver, node, name, type, class, ttl, rdata, block_number
+		 FROM ens.dns_zones
+		 WHERE LOWER(resolver) = LOWER($1) AND node = $2 AND LOWER(name) = LOWER($3)
+		 ORDER BY type, rdata`,
+		resolverAddr,
+		node,
+		name,
+	)
+
+	return records, err
+}
+
+// Gets every resource record of the node's current zone on the resolver, ordered by name, type and rdata
+func (r *dnsRepository) GetZone(resolverAddr, node string) ([]models.DNSRecordModel, error) {
+	var records []models.DNSRecordModel
+	err := r.db.Select(&records,
+		`SELECT resolver, node, name, type, class, ttl, rdata, block_number
+		 FROM ens.dns_zones
+		 WHERE LOWER(resolver) = LOWER($1) AND node = $2
+		 ORDER BY LOWER(name), type, rdata`,
+		resolverAddr,
+		node,
+	)
+
+	return records, err
+}
+
+// Gets every resource record of the current zones of the nodes on the resolvers, where the resolver and node of each zone are
+// given at the same index, ordered by node, name, type and rdata
+func (r *dnsRepository) GetZones(resolverAddrs, nodes []string) ([]models.DNSRecordModel, error) {
+	var records []models.DNSRecordModel
+	err := r.db.Select(&records,
+		`SELECT dns_zones.resolver, dns_zones.node, dns_zones.name, dns_zones.type, dns_zones.class, dns_zones.ttl,
+		        dns_zones.rdata, dns_zones.block_number
+		 FROM unnest($1::VARCHAR(66)[], $2::VARCHAR(66)[]) AS zones (resolver, node)
+		 JOIN ens.dns_zones ON LOWER(dns_zones.resolver) = LOWER(zones.resolver) AND dns_zones.node = zones.node
+		 ORDER BY dns_zones.node, LOWER(dns_zones.name), dns_zones.type, dns_zones.rdata`,
+		pq.Array(resolverAddrs),
+		pq.Array(nodes),
+	)
+
+	return records, err
+}
+
+// Gets the latest block any of the zones of the nodes on the resolvers, given as for GetZones, changed at: the latest block a record
+// was set or deleted at, or a version change cleared the zone at. Returns 0 if none of the zones has ever changed
+func (r *dnsRepository) GetZonesChangedAt(resolverAddrs, nodes []string) (int64, error) {
+	var blockNumber int64
+	err := r.db.Get(&blockNumber,
+		`SELECT COALESCE(MAX(headers.block_number), 0)
+		 FROM unnest($1::VARCHAR(66)[], $2::VARCHAR(66)[]) AS zones (resolver, node)
+		 JOIN (
+		   SELECT header_id, resolver, node FROM ens.dns_record_changed
+		   UNION ALL
+		   SELECT header_id, resolver, node FROM ens.dns_record_deleted
+		   UNION ALL
+		   SELECT header_id, resolver, node FROM ens.version_changed
+		 ) AS events ON LOWER(events.resolver) = LOWER(zones.resolver) AND events.node = zones.node
+		 JOIN public.headers ON headers.id = events.header_id`,
+		pq.Array(resolverAddrs),
+		pq.Array(nodes),
+	)
+
+	return blockNumber, err
+}
+
+// The functions persisting the models of each event the DNS zones are derived from into its event table
+var dnsEventCreators = map[string]func(tx *sqlx.Tx, headerID int64, models []interface{}) error{
+	"DNSRecordChanged":   dns_record_changed.CreateInTransaction,
+	"DNSRecordDeleted":   dns_record_deleted.CreateInTransaction,
+	"DNSZonehashChanged": dns_zonehash_changed.CreateInTransaction,
+	"VersionChanged":     version_changed.CreateInTransaction,
+}
+
+// Persists the models of a resolver's DNSRecordChanged, DNSRecordDeleted, DNSZonehashChanged or VersionChanged events into the
+// event's table, without marking the header checked for the event
+// The event transformers only watch the configured resolver, this lets the zones of every resolver the registry points to be derived
+func (r *dnsRepository) CreateDNSEvents(headerID int64, eventName string, models []interface{}) error {
+	create, ok := dnsEventCreators[eventName]
+	if !ok {
+		return fmt.Errorf("%s is not an event DNS zones are derived from", eventName)
+	}
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	err = create(tx, headerID, models)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
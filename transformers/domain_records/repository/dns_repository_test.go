@@ -0,0 +1,164 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+)
+
+var _ = Describe("DNS Repository", func() {
+	var repo repository.DNSRepository
+	var db *postgres.DB
+	var headerIds map[int64]int64
+
+	setRecord := func(headerID int64, logIdx int, name string, recordType int, rdata string) {
+		var recordID int64
+		err := db.Get(&recordID, `INSERT INTO ens.dns_record_changed (header_id, resolver, node, name, resource, record, tx_idx, log_idx)
+			VALUES ($1, 'fakeResolverAddress', 'fakeNameHash', $2, $3, '0x', 0, $4) RETURNING id`, headerID, name, recordType, logIdx)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = db.Exec(`INSERT INTO ens.dns_resource_records (dns_record_changed_id, name, type, class, ttl, rdata)
+			VALUES ($1, $2, $3, 1, 3600, $4)`, recordID, name, recordType, rdata)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		repo = repository.NewDNSRepository(db)
+		headerIds = test_helpers.CreateHeaders(db, 3327420, 3327421)
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	Describe("GetZoneRecords", func() {
+		It("Returns the current records for the name on the resolver's zone for the node", func() {
+			setRecord(headerIds[3327420], 0, "vitalik.eth.", 1, "0x01020304")
+			setRecord(headerIds[3327421], 0, "vitalik.eth.", 1, "0x05060708")
+			setRecord(headerIds[3327421], 1, "vitalik.eth.", 16, "0x0568656c6c6f")
+			setRecord(headerIds[3327421], 2, "www.vitalik.eth.", 1, "0x01020304")
+
+			records, err := repo.GetZoneRecords("FAKERESOLVERADDRESS", "fakeNameHash", "Vitalik.eth.")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]models.DNSRecordModel{{
+				Resolver:    "fakeResolverAddress",
+				Node:        "fakeNameHash",
+				Name:        "vitalik.eth.",
+				Type:        1,
+				Class:       1,
+				TTL:         3600,
+				Rdata:       "0x05060708",
+				BlockNumber: 3327421,
+			}, {
+				Resolver:    "fakeResolverAddress",
+				Node:        "fakeNameHash",
+				Name:        "vitalik.eth.",
+				Type:        16,
+				Class:       1,
+				TTL:         3600,
+				Rdata:       "0x0568656c6c6f",
+				BlockNumber: 3327421,
+			}}))
+		})
+
+		It("Returns no records for names that are not in the zone", func() {
+			setRecord(headerIds[3327420], 0, "vitalik.eth.", 1, "0x01020304")
+
+			records, err := repo.GetZoneRecords("fakeResolverAddress", "fakeNameHash", "blog.vitalik.eth.")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(BeEmpty())
+			records, err = repo.GetZoneRecords("fakeOtherResolverAddress", "fakeNameHash", "vitalik.eth.")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+	})
+
+	Describe("GetZone", func() {
+		It("Returns every current record of the node's zone on the resolver", func() {
+			setRecord(headerIds[3327420], 0, "www.vitalik.eth.", 1, "0x01020304")
+			setRecord(headerIds[3327420], 1, "vitalik.eth.", 16, "0x0568656c6c6f")
+			setRecord(headerIds[3327421], 0, "vitalik.eth.", 16, "0x05776f726c64")
+
+			records, err := repo.GetZone("fakeResolverAddress", "fakeNameHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]models.DNSRecordModel{{
+				Resolver:    "fakeResolverAddress",
+				Node:        "fakeNameHash",
+				Name:        "vitalik.eth.",
+				Type:        16,
+				Class:       1,
+				TTL:         3600,
+				Rdata:       "0x05776f726c64",
+				BlockNumber: 3327421,
+			}, {
+				Resolver:    "fakeResolverAddress",
+				Node:        "fakeNameHash",
+				Name:        "www.vitalik.eth.",
+				Type:        1,
+				Class:       1,
+				TTL:         3600,
+				Rdata:       "0x01020304",
+				BlockNumber: 3327420,
+			}}))
+		})
+	})
+
+	Describe("GetZones", func() {
+		It("Returns every current record of the zones of the nodes on their resolvers, ordered by node", func() {
+			setRecord(headerIds[3327420], 0, "www.vitalik.eth.", 1, "0x01020304")
+			setRecord(headerIds[3327421], 0, "vitalik.eth.", 16, "0x05776f726c64")
+
+			records, err := repo.GetZones([]string{"FAKERESOLVERADDRESS", "fakeResolverAddress"}, []string{"fakeNameHash", "fakeOtherNameHash"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(records)).To(Equal(2))
+			Expect(records[0].Name).To(Equal("vitalik.eth."))
+			Expect(records[1].Name).To(Equal("www.vitalik.eth."))
+
+			records, err = repo.GetZones([]string{"fakeOtherResolverAddress"}, []string{"fakeNameHash"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+	})
+
+	Describe("GetZonesChangedAt", func() {
+		It("Returns the latest block a record of the zones was set or deleted at", func() {
+			setRecord(headerIds[3327420], 0, "vitalik.eth.", 1, "0x01020304")
+
+			blockNumber, err := repo.GetZonesChangedAt([]string{"fakeResolverAddress"}, []string{"fakeNameHash"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockNumber).To(Equal(int64(3327420)))
+
+			_, err = db.Exec(`INSERT INTO ens.dns_record_deleted (header_id, resolver, node, name, resource, tx_idx, log_idx)
+				VALUES ($1, 'fakeResolverAddress', 'fakeNameHash', 'vitalik.eth.', 1, 0, 0)`, headerIds[3327421])
+			Expect(err).ToNot(HaveOccurred())
+			blockNumber, err = repo.GetZonesChangedAt([]string{"fakeResolverAddress"}, []string{"fakeNameHash"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockNumber).To(Equal(int64(3327421)))
+
+			blockNumber, err = repo.GetZonesChangedAt([]string{"fakeResolverAddress"}, []string{"fakeOtherNameHash"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockNumber).To(Equal(int64(0)))
+		})
+	})
+})
@@ -0,0 +1,72 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package repository
+
+import (
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type ExpiryRepository interface {
+	GetExpiry(labelHash string, blockNumber int64) (models.ExpiryModel, error)
+	GetExpiringBetween(start, end string, blockNumber int64) ([]models.ExpiryModel, error)
+}
+
+type expiryRepository struct {
+	db *postgres.DB
+}
+
+func NewExpiryRepository(db *postgres.DB) *expiryRepository {
+	return &expiryRepository{
+		db: db,
+	}
+}
+
+const expiryColumns = `label_hash, block_number, COALESCE(registered_at::TEXT, '') AS registered_at,
+		COALESCE(expires::TEXT, '') AS expires, COALESCE(grace_period_ends::TEXT, '') AS grace_period_ends, status`
+
+// Gets the expiry of the .eth name with the given label hash as of the given block, with its status evaluated at that block's timestamp
+// Returns sql.ErrNoRows if the name had not been registered at that block
+func (r *expiryRepository) GetExpiry(labelHash string, blockNumber int64) (models.ExpiryModel, error) {
+	var expiry models.ExpiryModel
+	err := r.db.Get(&expiry,
+		`SELECT `+expiryColumns+`
+		 FROM ens.name_expiries_at($2)
+		 WHERE label_hash = $1`,
+		labelHash,
+		blockNumber,
+	)
+
+	return expiry, err
+}
+
+// Gets the names that, as of the given block, expire at or after the start and before the end timestamp (in unix seconds), ordered by expiry
+// Released names are excluded
+func (r *expiryRepository) GetExpiringBetween(start, end string, blockNumber int64) ([]models.ExpiryModel, error) {
+	var expiries []models.ExpiryModel
+	err := r.db.Select(&expiries,
+		`SELECT `+expiryColumns+`
+		 FROM ens.name_expiries_at($3)
+		 WHERE status != $4 AND expires >= $1::NUMERIC AND expires < $2::NUMERIC
+		 ORDER BY expires, label_hash`,
+		start,
+		end,
+		blockNumber,
+		models.ExpiryStatusReleased,
+	)
+
+	return expiries, err
+}
@@ -0,0 +1,194 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package repository_test
+
+import (
+	"database/sql"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+var _ = Describe("Expiry Repository", func() {
+	var repo repository.ExpiryRepository
+	var db *postgres.DB
+	var headerIds map[int64]int64
+	labelHash := utils.LabelHash("vitalik")
+
+	// Creates a header for each block with the given timestamp
+	createHeaders := func(timestamps map[int64]int64) {
+		headerRepository := repositories.NewHeaderRepository(db)
+		for blockNumber, timestamp := range timestamps {
+			header := fakes.GetFakeHeader(blockNumber)
+			header.Timestamp = strconv.FormatInt(timestamp, 10)
+			id, err := headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).NotTo(HaveOccurred())
+			headerIds[blockNumber] = id
+		}
+	}
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		repo = repository.NewExpiryRepository(db)
+		headerIds = make(map[int64]int64)
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	Describe("Legacy registrar names", func() {
+		BeforeEach(func() {
+			createHeaders(map[int64]int64{
+				3327420: 1500000000,
+				3327421: 1588550399,
+				3327422: 1588550400,
+			})
+			_, err := db.Exec(`INSERT INTO ens.hash_registered (header_id, hash, owner, value, registration_date, tx_idx, log_idx)
+				VALUES ($1, $2, 'fakeOwner', 10000000000000000, 1499999000, 0, 0)`, headerIds[3327420], labelHash)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Are active until the end of the migration period, without a grace period", func() {
+			expiry, err := repo.GetExpiry(labelHash, 3327421)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry).To(Equal(models.ExpiryModel{
+				LabelHash:       labelHash,
+				BlockNumber:     3327420,
+				RegisteredAt:    "1499999000",
+				Expires:         "1588550400",
+				GracePeriodEnds: "1588550400",
+				Status:          models.ExpiryStatusActive,
+			}))
+
+			expiry, err = repo.GetExpiry(labelHash, 3327422)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry.Status).To(Equal(models.ExpiryStatusExpired))
+		})
+
+		It("Are released when their deed is released", func() {
+			_, err := db.Exec(`INSERT INTO ens.hash_released (header_id, hash, value, tx_idx, log_idx) VALUES ($1, $2, 0, 0, 0)`,
+				headerIds[3327421], labelHash)
+			Expect(err).NotTo(HaveOccurred())
+
+			expiry, err := repo.GetExpiry(labelHash, 3327420)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry.Status).To(Equal(models.ExpiryStatusActive))
+			expiry, err = repo.GetExpiry(labelHash, 3327421)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry.Status).To(Equal(models.ExpiryStatusReleased))
+			Expect(expiry.Expires).To(Equal(""))
+		})
+
+		It("Take the expiry they were migrated with, keeping their registration date", func() {
+			_, err := db.Exec(`INSERT INTO ens.name_migrated (header_id, label_hash, owner, expires, tx_idx, log_idx) VALUES ($1, $2, 'fakeOwner', 1600000000, 0, 0)`,
+				headerIds[3327421], labelHash)
+			Expect(err).NotTo(HaveOccurred())
+
+			expiry, err := repo.GetExpiry(labelHash, 3327422)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry).To(Equal(models.ExpiryModel{
+				LabelHash:       labelHash,
+				BlockNumber:     3327421,
+				RegisteredAt:    "1499999000",
+				Expires:         "1600000000",
+				GracePeriodEnds: "1607776000",
+				Status:          models.ExpiryStatusActive,
+			}))
+		})
+	})
+
+	Describe("Permanent registrar names", func() {
+		BeforeEach(func() {
+			createHeaders(map[int64]int64{
+				3327420: 1590000000,
+				3327421: 1600000000,
+				3327422: 1607776000,
+				3327423: 1607776001,
+			})
+			_, err := db.Exec(`INSERT INTO ens.name_registered (header_id, label_hash, owner, expires, tx_idx, log_idx) VALUES ($1, $2, 'fakeOwner', 1600000000, 0, 0)`,
+				headerIds[3327420], labelHash)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Are registered at the timestamp of the block they were registered in", func() {
+			expiry, err := repo.GetExpiry(labelHash, 3327420)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry.RegisteredAt).To(Equal("1590000000"))
+			Expect(expiry.Status).To(Equal(models.ExpiryStatusActive))
+		})
+
+		It("Go through a grace period after they expire", func() {
+			expiry, err := repo.GetExpiry(labelHash, 3327421)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry.Status).To(Equal(models.ExpiryStatusGrace))
+			expiry, err = repo.GetExpiry(labelHash, 3327422)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry.Status).To(Equal(models.ExpiryStatusExpired))
+		})
+
+		It("Are extended by renewals", func() {
+			_, err := db.Exec(`INSERT INTO ens.name_renewed (header_id, label_hash, expires, tx_idx, log_idx) VALUES ($1, $2, 1700000000, 0, 0)`,
+				headerIds[3327421], labelHash)
+			Expect(err).NotTo(HaveOccurred())
+
+			expiry, err := repo.GetExpiry(labelHash, 3327423)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry).To(Equal(models.ExpiryModel{
+				LabelHash:       labelHash,
+				BlockNumber:     3327421,
+				RegisteredAt:    "1590000000",
+				Expires:         "1700000000",
+				GracePeriodEnds: "1707776000",
+				Status:          models.ExpiryStatusActive,
+			}))
+		})
+
+		It("Returns no rows before the name was registered", func() {
+			createHeaders(map[int64]int64{3327419: 1580000000})
+
+			_, err := repo.GetExpiry(labelHash, 3327419)
+			Expect(err).To(Equal(sql.ErrNoRows))
+		})
+
+		It("Gets the names expiring within a window", func() {
+			otherLabelHash := utils.LabelHash("nick")
+			_, err := db.Exec(`INSERT INTO ens.name_registered (header_id, label_hash, owner, expires, tx_idx, log_idx) VALUES ($1, $2, 'fakeOwner', 1650000000, 1, 0)`,
+				headerIds[3327420], otherLabelHash)
+			Expect(err).NotTo(HaveOccurred())
+
+			expiries, err := repo.GetExpiringBetween("1590000000", "1610000000", 3327420)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(expiries)).To(Equal(1))
+			Expect(expiries[0].LabelHash).To(Equal(labelHash))
+
+			expiries, err = repo.GetExpiringBetween("1590000000", "1660000000", 3327420)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(expiries)).To(Equal(2))
+			Expect(expiries[1].LabelHash).To(Equal(otherLabelHash))
+		})
+	})
+})
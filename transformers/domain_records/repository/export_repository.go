@@ -0,0 +1,305 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	ensconfig "github.com/vulcanize/ens_transformers/transformers/domain_records/config"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+)
+
+const DomainRecordsTable = "domain_records"
+
+// The tables that can be exported, with the checked_headers column of each event table
+// The domain records are bounded by the checked_headers columns of the registry events instead, see ExportConfig
+var exportTables = map[string]string{
+	"auction_started":            constants.AuctionStartedChecked,
+	"bid_revealed":               constants.BidRevealedChecked,
+	"hash_invalidated":           constants.HashInvalidatedChecked,
+	"hash_registered":            constants.HashRegisteredChecked,
+	"hash_released":              constants.HashReleasedChecked,
+	"new_bid":                    constants.NewBidChecked,
+	"name_migrated":              constants.NameMigratedChecked,
+	"name_registered":            constants.NameRegisteredChecked,
+	"name_renewed":               constants.NameRenewedChecked,
+	"controller_added":           constants.ControllerAddedChecked,
+	"controller_removed":         constants.ControllerRemovedChecked,
+	"registrar_transfer":         constants.RegistrarTransferChecked,
+	"registrar_approval":         constants.RegistrarApprovalChecked,
+	"controller_name_registered": constants.ControllerNameRegisteredChecked,
+	"controller_name_renewed":    constants.ControllerNameRenewedChecked,
+	"new_price_oracle":           constants.NewPriceOracleChecked,
+	"reverse_claimed":            constants.ReverseClaimedChecked,
+	"new_owner":                  constants.NewOwnerChecked,
+	"new_resolver":               constants.NewResolverChecked,
+	"new_ttl":                    constants.NewTtlChecked,
+	"transfer":                   constants.TransferChecked,
+	"abi_changed":                constants.AbiChangedChecked,
+	"addr_changed":               constants.AddrChangedChecked,
+	"address_changed":            constants.AddressChangedChecked,
+	"authorisation_changed":      constants.AuthorisationChangedChecked,
+	"content_changed":            constants.ContentChangedChecked,
+	"contenthash_changed":        constants.ContenthashChangedChecked,
+	"dns_record_changed":         constants.DNSRecordChangedChecked,
+	"dns_record_deleted":         constants.DNSRecordDeletedChecked,
+	"dns_zonehash_changed":       constants.DNSZonehashChangedChecked,
+	"interface_changed":          constants.InterfaceChangedChecked,
+	"multihash_changed":          constants.MultihashChangedChecked,
+	"name_changed":               constants.NameChangedChecked,
+	"pubkey_changed":             constants.PubkeyChangedChecked,
+	"text_changed":               constants.TextChangedChecked,
+	"version_changed":            constants.VersionChangedChecked,
+	DomainRecordsTable:           "",
+}
+
+// Returns the names of the tables that can be exported: the ens event tables and the domain records
+func ExportTables() []string {
+	tables := make([]string, 0, len(exportTables))
+	for table := range exportTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	return tables
+}
+
+type ExportRepository interface {
+	LastExportableBlock(table string, confirmations int64) (int64, error)
+	StreamTable(table string, from, to int64, start func([]models.ExportColumn) error, handle func([]interface{}) error) error
+}
+
+// Where the transformers filling the exportable tables start checking headers, which bounds the blocks that can be exported
+type ExportConfig struct {
+	// The block the transformer of each event table starts at; tables that aren't listed start at block 0
+	StartingBlocks map[string]int64
+	// The registries the domain records transformer watches, whose events' checked_headers columns bound the domain records
+	Registry config.ContractConfig
+}
+
+type exportRepository struct {
+	db     *postgres.DB
+	config ExportConfig
+}
+
+func NewExportRepository(db *postgres.DB, exportConfig ExportConfig) *exportRepository {
+	return &exportRepository{
+		db:     db,
+		config: exportConfig,
+	}
+}
+
+// Returns the highest block whose rows of the table won't change as syncing continues, barring reorgs deeper than the confirmations:
+// the given number of blocks below the latest header, and below the first header at or above the transformer's starting block
+// that it hasn't checked yet, for the event of an event table or for any of the registry events for the domain records
+// Domain records are also written by resolver events, which are checked after the registry's and may lag behind it after a failure,
+// so they are also bounded by the first header at or above each watched resolver's starting block that isn't checked for its events
+func (r *exportRepository) LastExportableBlock(table string, confirmations int64) (int64, error) {
+	checked, ok := exportTables[table]
+	if !ok {
+		return 0, fmt.Errorf("%s is not an exportable table", table)
+	}
+	args := []interface{}{confirmations, r.config.StartingBlocks[table]}
+	conditions := []string{uncheckedCondition([]string{checked}, 2)}
+	if table == DomainRecordsTable {
+		columns, startingBlock, err := ensconfig.RegistryEventIds(r.config.Registry)
+		if err != nil {
+			return 0, fmt.Errorf("unable to find the registry events that bound %s: %v", table, err)
+		}
+		args[1] = startingBlock
+		conditions[0] = uncheckedCondition(columns, 2)
+		resolverConditions, resolverArgs, err := r.resolverConditions(len(args) + 1)
+		if err != nil {
+			return 0, fmt.Errorf("unable to find the resolver events that bound %s: %v", table, err)
+		}
+		conditions = append(conditions, resolverConditions...)
+		args = append(args, resolverArgs...)
+	}
+
+	var block int64
+	err := r.db.Get(&block,
+		`SELECT LEAST(
+			(SELECT COALESCE(MAX(block_number), 0) - $1 FROM public.headers),
+			(SELECT MIN(headers.block_number) - 1 FROM public.headers
+			 LEFT JOIN public.checked_headers ON headers.id = checked_headers.header_id
+			 WHERE `+strings.Join(conditions, " OR ")+`))`,
+		args...,
+	)
+
+	return block, err
+}
+
+// Returns a condition for each valid watched resolver matching the headers it hasn't been checked for, with the starting block of
+// each resolver as the arguments for the parameters numbered from the given one
+func (r *exportRepository) resolverConditions(firstParam int) ([]string, []interface{}, error) {
+	var resolvers []models.ResolverModel
+	err := r.db.Select(&resolvers,
+		`SELECT resolver_addr, starting_block, abi FROM ens.watched_resolvers
+		 WHERE valid
+		 ORDER BY id`,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	conditions := make([]string, 0, len(resolvers))
+	args := make([]interface{}, 0, len(resolvers))
+	for _, resolver := range resolvers {
+		columns, err := ensconfig.ResolverEventIds(resolver.Abi, resolver.Address)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		conditions = append(conditions, uncheckedCondition(columns, firstParam+len(args)))
+		args = append(args, resolver.StartingBlock)
+	}
+
+	return conditions, args, nil
+}
+
+// Returns the condition matching the headers at or above the starting block in the given parameter that haven't been checked
+// for every one of the checked_headers columns
+func uncheckedCondition(columns []string, startingBlockParam int) string {
+	unchecked := make([]string, len(columns))
+	for i, column := range columns {
+		unchecked[i] = `checked_headers.` + column + ` = 0`
+	}
+
+	return fmt.Sprintf(`(headers.block_number >= $%d AND (checked_headers.header_id IS NULL OR %s))`, startingBlockParam, strings.Join(unchecked, " OR "))
+}
+
+// Passes the columns of the table to start, then each of its rows in the block range to handle as the rows are read
+// Rows lead with their block number and hash, and event rows with the hash of their transaction, decoded from their raw log, which
+// is left out. Event rows are ordered by their position in the chain and domain records by block and id
+// Values are nil or of their column's kind: integers as int64, booleans as bool and everything else, including numerics, as strings
+// Stops and returns the error if start or handle return one
+func (r *exportRepository) StreamTable(table string, from, to int64, start func([]models.ExportColumn) error, handle func([]interface{}) error) error {
+	query, err := r.exportQuery(table)
+	if err != nil {
+		return err
+	}
+	rows, err := r.db.Query(query, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	columns := make([]models.ExportColumn, len(types))
+	for i, columnType := range types {
+		columns[i] = models.ExportColumn{Name: columnType.Name(), Kind: exportKind(columnType.DatabaseTypeName())}
+	}
+	err = start(columns)
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		err = rows.Scan(pointers...)
+		if err != nil {
+			return err
+		}
+		row := make([]interface{}, len(values))
+		for i, value := range values {
+			row[i] = exportValue(value)
+		}
+		err = handle(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *exportRepository) exportQuery(table string) (string, error) {
+	if _, ok := exportTables[table]; !ok {
+		return "", fmt.Errorf("%s is not an exportable table", table)
+	}
+	var names []string
+	err := r.db.Select(&names,
+		`SELECT column_name FROM information_schema.columns
+		 WHERE table_schema = 'ens' AND table_name = $1 AND column_name NOT IN ('block_number', 'raw_log')
+		 ORDER BY ordinal_position`,
+		table,
+	)
+	if err != nil {
+		return "", err
+	}
+	columns := make([]string, len(names))
+	for i, name := range names {
+		columns[i] = table + "." + pq.QuoteIdentifier(name)
+	}
+
+	if table == DomainRecordsTable {
+		return `SELECT domain_records.block_number, headers.hash AS block_hash, ` + strings.Join(columns, ", ") + `
+			FROM ens.domain_records
+			LEFT JOIN public.headers ON headers.id = domain_records.header_id
+			WHERE domain_records.block_number BETWEEN $1 AND $2
+			ORDER BY domain_records.block_number, domain_records.id`, nil
+	}
+	return `SELECT headers.block_number, ` + table + `.raw_log->>'blockHash' AS block_hash,
+			` + table + `.raw_log->>'transactionHash' AS tx_hash, ` + strings.Join(columns, ", ") + `
+		FROM ens.` + table + `
+		JOIN public.headers ON headers.id = ` + table + `.header_id
+		WHERE headers.block_number BETWEEN $1 AND $2
+		ORDER BY headers.block_number, ` + table + `.tx_idx, ` + table + `.log_idx`, nil
+}
+
+func exportKind(databaseType string) string {
+	switch databaseType {
+	case "INT2", "INT4", "INT8":
+		return models.ExportInt
+	case "BOOL":
+		return models.ExportBool
+	default:
+		return models.ExportString
+	}
+}
+
+func exportValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, int64, bool, string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,250 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/constants"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	ensconfig "github.com/vulcanize/ens_transformers/transformers/domain_records/config"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+var _ = Describe("Export Repository", func() {
+	var repo repository.ExportRepository
+	var db *postgres.DB
+	var headerIds map[int64]int64
+	rawLog := `{"blockHash": "0xfakeBlockHash", "transactionHash": "0xfakeTxHash"}`
+
+	createNewOwner := func(blockNumber int64, logIdx int, rawLog interface{}) int64 {
+		var id int64
+		err := db.Get(&id, `INSERT INTO ens.new_owner (header_id, node, label, owner, subnode, tx_idx, log_idx, raw_log)
+			VALUES ($1, 'fakeNode', 'fakeLabel', 'fakeOwner', 'fakeSubnode', 0, $2, $3) RETURNING id`, headerIds[blockNumber], logIdx, rawLog)
+		Expect(err).ToNot(HaveOccurred())
+		return id
+	}
+
+	// Streams the rows of the table in the block range, returning its columns and rows
+	streamTable := func(table string, from, to int64) ([]models.ExportColumn, [][]interface{}) {
+		var columns []models.ExportColumn
+		var rows [][]interface{}
+		err := repo.StreamTable(table, from, to, func(c []models.ExportColumn) error {
+			columns = c
+			return nil
+		}, func(row []interface{}) error {
+			rows = append(rows, row)
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return columns, rows
+	}
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		repo = repository.NewExportRepository(db, repository.ExportConfig{
+			StartingBlocks: map[string]int64{"new_owner": 3327421},
+			Registry:       ensconfig.RopstenENSConfig,
+		})
+		headerIds = test_helpers.CreateHeaders(db, 3327420, 3327422)
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	Describe("StreamTable", func() {
+		It("Streams the event rows in the block range in order, with hashes decoded from their raw logs", func() {
+			createNewOwner(3327420, 0, rawLog)
+			second := createNewOwner(3327421, 1, rawLog)
+			first := createNewOwner(3327421, 0, nil)
+			createNewOwner(3327422, 0, rawLog)
+
+			columns, rows := streamTable("new_owner", 3327421, 3327421)
+
+			Expect(columns).To(Equal([]models.ExportColumn{
+				{Name: "block_number", Kind: models.ExportInt},
+				{Name: "block_hash", Kind: models.ExportString},
+				{Name: "tx_hash", Kind: models.ExportString},
+				{Name: "id", Kind: models.ExportInt},
+				{Name: "header_id", Kind: models.ExportInt},
+				{Name: "node", Kind: models.ExportString},
+				{Name: "label", Kind: models.ExportString},
+				{Name: "owner", Kind: models.ExportString},
+				{Name: "subnode", Kind: models.ExportString},
+				{Name: "tx_idx", Kind: models.ExportInt},
+				{Name: "log_idx", Kind: models.ExportInt},
+			}))
+			Expect(rows).To(Equal([][]interface{}{
+				{int64(3327421), nil, nil, first, headerIds[3327421], "fakeNode", "fakeLabel", "fakeOwner", "fakeSubnode", int64(0), int64(0)},
+				{int64(3327421), "0xfakeBlockHash", "0xfakeTxHash", second, headerIds[3327421], "fakeNode", "fakeLabel", "fakeOwner", "fakeSubnode", int64(0), int64(1)},
+			}))
+		})
+
+		It("Streams the domain records with the hashes of their headers", func() {
+			ensRepo := repository.NewENSRepository(db)
+			err := ensRepo.CreateRecord(models.DomainModel{
+				NameHash:    utils.NameHash("eth"),
+				BlockNumber: 3327420,
+				HeaderID:    headerIds[3327420],
+				LabelHash:   utils.LabelHash("eth"),
+				ParentHash:  utils.RootNode,
+				Owner:       "fakeOwner",
+				FullName:    "eth",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			columns, rows := streamTable(repository.DomainRecordsTable, 3327420, 3327422)
+
+			Expect(columns[:3]).To(Equal([]models.ExportColumn{
+				{Name: "block_number", Kind: models.ExportInt},
+				{Name: "block_hash", Kind: models.ExportString},
+				{Name: "id", Kind: models.ExportInt},
+			}))
+			Expect(len(rows)).To(Equal(1))
+			Expect(rows[0][0]).To(Equal(int64(3327420)))
+			Expect(rows[0][1]).To(Equal(fakes.FakeHash.String()))
+			for i, column := range columns {
+				switch column.Name {
+				case "name_hash":
+					Expect(rows[0][i]).To(Equal(utils.NameHash("eth")))
+				case "full_name":
+					Expect(rows[0][i]).To(Equal("eth"))
+				case "migrated":
+					Expect(column.Kind).To(Equal(models.ExportBool))
+					Expect(rows[0][i]).To(Equal(false))
+				case "resolver_addr":
+					Expect(rows[0][i]).To(BeNil())
+				}
+			}
+		})
+
+		It("Returns an error for tables that can't be exported", func() {
+			err := repo.StreamTable("labels", 0, 3327422, nil, nil)
+
+			Expect(err).To(MatchError("labels is not an exportable table"))
+		})
+	})
+
+	Describe("LastExportableBlock", func() {
+		It("Leaves out the confirmations below the latest header", func() {
+			_, err := db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked) VALUES ($1, 1), ($2, 1), ($3, 1)`,
+				headerIds[3327420], headerIds[3327421], headerIds[3327422])
+			Expect(err).ToNot(HaveOccurred())
+
+			block, err := repo.LastExportableBlock("new_owner", 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327421)))
+		})
+
+		It("Ignores the headers below the starting block of the table's transformer", func() {
+			_, err := db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked) VALUES ($1, 1), ($2, 1)`,
+				headerIds[3327421], headerIds[3327422])
+			Expect(err).ToNot(HaveOccurred())
+
+			block, err := repo.LastExportableBlock("new_owner", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327422)))
+		})
+
+		It("Stops the domain records before the first header that hasn't been checked for every registry event", func() {
+			ids, _, err := ensconfig.RegistryEventIds(ensconfig.RopstenENSConfig)
+			Expect(err).ToNot(HaveOccurred())
+			for _, id := range ids {
+				_, err = db.Exec(`ALTER TABLE public.checked_headers ADD COLUMN IF NOT EXISTS ` + id + ` INTEGER NOT NULL DEFAULT 1`)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			_, err = db.Exec(`INSERT INTO public.checked_headers (header_id) VALUES ($1), ($2), ($3)`,
+				headerIds[3327420], headerIds[3327421], headerIds[3327422])
+			Expect(err).ToNot(HaveOccurred())
+			_, err = db.Exec(`UPDATE public.checked_headers SET `+ids[0]+` = 0 WHERE header_id = $1`, headerIds[3327421])
+			Expect(err).ToNot(HaveOccurred())
+
+			block, err := repo.LastExportableBlock(repository.DomainRecordsTable, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327420)))
+		})
+
+		It("Stops the domain records before the first header a watched resolver hasn't been checked for, once the registry has moved past it", func() {
+			registryIds, _, err := ensconfig.RegistryEventIds(ensconfig.RopstenENSConfig)
+			Expect(err).ToNot(HaveOccurred())
+			resolverAbi := "[" + constants.AddrChangeInterface + "]"
+			resolverIds, err := ensconfig.ResolverEventIds(resolverAbi, "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3")
+			Expect(err).ToNot(HaveOccurred())
+			for _, id := range append(registryIds, resolverIds...) {
+				_, err = db.Exec(`ALTER TABLE public.checked_headers ADD COLUMN IF NOT EXISTS ` + id + ` INTEGER NOT NULL DEFAULT 1`)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			err = repository.NewResolverRepository(db).CreateResolver(models.ResolverModel{
+				Address:       "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3",
+				StartingBlock: 3327421,
+				Abi:           resolverAbi,
+				Valid:         true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = db.Exec(`INSERT INTO public.checked_headers (header_id) VALUES ($1), ($2), ($3)`,
+				headerIds[3327420], headerIds[3327421], headerIds[3327422])
+			Expect(err).ToNot(HaveOccurred())
+			// The header below the resolver's starting block is never checked for its events
+			_, err = db.Exec(`UPDATE public.checked_headers SET `+resolverIds[0]+` = 0 WHERE header_id = $1`, headerIds[3327420])
+			Expect(err).ToNot(HaveOccurred())
+
+			block, err := repo.LastExportableBlock(repository.DomainRecordsTable, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327422)))
+
+			// Every header is checked for the registry events, but the resolver is lagging behind
+			_, err = db.Exec(`UPDATE public.checked_headers SET `+resolverIds[0]+` = 0 WHERE header_id = $1`, headerIds[3327422])
+			Expect(err).ToNot(HaveOccurred())
+			block, err = repo.LastExportableBlock(repository.DomainRecordsTable, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327421)))
+		})
+
+		It("Stops event tables before the first header that hasn't been checked for the event", func() {
+			_, err := db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked) VALUES ($1, 1), ($2, 0)`,
+				headerIds[3327420], headerIds[3327421])
+			Expect(err).ToNot(HaveOccurred())
+
+			block, err := repo.LastExportableBlock("new_owner", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327420)))
+
+			_, err = db.Exec(`UPDATE public.checked_headers SET new_owner_checked = 1`)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked) VALUES ($1, 1)`, headerIds[3327422])
+			Expect(err).ToNot(HaveOccurred())
+			block, err = repo.LastExportableBlock("new_owner", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327422)))
+		})
+	})
+
+	It("Lists the exportable tables", func() {
+		tables := repository.ExportTables()
+
+		Expect(tables).To(ContainElement("new_owner"))
+		Expect(tables).To(ContainElement(repository.DomainRecordsTable))
+		Expect(tables).NotTo(ContainElement("labels"))
+	})
+})
@@ -0,0 +1,173 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"bufio"
+	"database/sql"
+	"io"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type LabelRepository interface {
+	CreateLabel(label models.LabelModel) error
+	GetLabel(labelHash string) (string, error)
+	ImportWordlist(wordlist io.Reader, source string) (int, error)
+}
+
+type labelRepository struct {
+	db *postgres.DB
+}
+
+func NewLabelRepository(db *postgres.DB) *labelRepository {
+	return &labelRepository{
+		db: db,
+	}
+}
+
+// Persists the preimage of a label hash and fills it into the full names of the domain records that were waiting on it
+func (r *labelRepository) CreateLabel(label models.LabelModel) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	_, err = CreateLabelInTransaction(tx, label)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Gets the plaintext label for the given label hash, returns an empty string if the preimage is not known
+func (r *labelRepository) GetLabel(labelHash string) (string, error) {
+	var label string
+	err := r.db.Get(&label,
+		`SELECT label FROM ens.labels WHERE label_hash = $1`,
+		labelHash,
+	)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return label, err
+}
+
+// Imports a newline-separated wordlist of labels (or dotted names, which are split into their labels) into the label table
+// Returns the number of labels that were not already known
+func (r *labelRepository) ImportWordlist(wordlist io.Reader, source string) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	var imported int
+	scanner := bufio.NewScanner(wordlist)
+	for scanner.Scan() {
+		for _, label := range utils.SplitName(strings.TrimSpace(scanner.Text())) {
+			created, err := CreateLabelInTransaction(tx, models.LabelModel{
+				LabelHash: utils.LabelHash(label),
+				Label:     label,
+				Source:    source,
+			})
+			if err != nil {
+				rollbackErr := tx.Rollback()
+				if rollbackErr != nil {
+					log.Error("failed to rollback ", rollbackErr)
+				}
+				return 0, err
+			}
+			if created {
+				imported++
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return 0, err
+	}
+
+	return imported, tx.Commit()
+}
+
+// The nodes with the label hash $1 and their descendants, walked down the parent hashes of the domain records
+const waitingNodes = `WITH RECURSIVE waiting (name_hash) AS (
+				SELECT name_hash FROM ens.domain_records WHERE label_hash = $1
+				UNION
+				SELECT records.name_hash FROM ens.domain_records AS records
+				JOIN waiting ON records.parent_hash = waiting.name_hash
+			)
+			`
+
+// Persists a label within the given transaction so that it can be committed alongside other writes
+// If the label was not already known, the placeholder for its hash is replaced with the label in the full names of the domain records and current domains
+// Returns whether or not the label was newly created
+func CreateLabelInTransaction(tx *sqlx.Tx, label models.LabelModel) (bool, error) {
+	res, err := tx.Exec(
+		`INSERT INTO ens.labels (label_hash, label, source)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (label_hash) DO NOTHING`,
+		label.LabelHash,
+		label.Label,
+		label.Source,
+	)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil || inserted == 0 {
+		return false, err
+	}
+
+	// Only the nodes with this label hash and their descendants can be waiting on it, so only their names are rewritten
+	_, err = tx.Exec(
+		waitingNodes+`UPDATE ens.domain_records
+				SET full_name = replace(full_name, $2, $3)
+				WHERE name_hash IN (SELECT name_hash FROM waiting)
+				AND strpos(full_name, $2) > 0`,
+		label.LabelHash,
+		utils.UnknownLabel(label.LabelHash),
+		label.Label,
+	)
+	if err != nil {
+		return false, err
+	}
+	_, err = tx.Exec(
+		waitingNodes+`UPDATE ens.current_domains
+				SET full_name = replace(full_name, $2, $3)
+				WHERE name_hash IN (SELECT name_hash FROM waiting)
+				AND strpos(full_name, $2) > 0`,
+		label.LabelHash,
+		utils.UnknownLabel(label.LabelHash),
+		label.Label,
+	)
+
+	return err == nil, err
+}
@@ -0,0 +1,136 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+var _ = Describe("Label Repository", func() {
+	var repo repository.LabelRepository
+	var ensRepo repository.ENSRepository
+	var db *postgres.DB
+	ethLabel := models.LabelModel{
+		LabelHash: utils.LabelHash("eth"),
+		Label:     "eth",
+		Source:    "NameChanged",
+	}
+
+	var headerIds map[int64]int64
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		repo = repository.NewLabelRepository(db)
+		ensRepo = repository.NewENSRepository(db)
+		headerIds = test_helpers.CreateHeaders(db, 3327420, 3327421)
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	Describe("CreateLabel", func() {
+		It("Persists the label so that it can be looked up by its hash", func() {
+			err := repo.CreateLabel(ethLabel)
+			Expect(err).ToNot(HaveOccurred())
+
+			label, err := repo.GetLabel(ethLabel.LabelHash)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(label).To(Equal("eth"))
+		})
+
+		It("Returns an empty label if the preimage is not known", func() {
+			label, err := repo.GetLabel(ethLabel.LabelHash)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(label).To(Equal(""))
+		})
+
+		It("Fills the label into the full names of the records beneath its nodes that were waiting on it", func() {
+			err := ensRepo.CreateRecord(models.DomainModel{
+				NameHash:    "fakeEthNameHash",
+				BlockNumber: 3327420,
+				HeaderID:    headerIds[3327420],
+				LabelHash:   ethLabel.LabelHash,
+				ParentHash:  utils.RootNode,
+				FullName:    utils.UnknownLabel(ethLabel.LabelHash),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			err = ensRepo.CreateRecord(models.DomainModel{
+				NameHash:    "fakeNameHash",
+				BlockNumber: 3327421,
+				HeaderID:    headerIds[3327421],
+				LabelHash:   utils.LabelHash("vitalik"),
+				ParentHash:  "fakeEthNameHash",
+				FullName:    "vitalik." + utils.UnknownLabel(ethLabel.LabelHash),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			err = ensRepo.CreateRecord(models.DomainModel{
+				NameHash:    "fakeSubNameHash",
+				BlockNumber: 3327421,
+				HeaderID:    headerIds[3327421],
+				LabelHash:   utils.LabelHash("sub"),
+				ParentHash:  "fakeNameHash",
+				FullName:    "sub.vitalik." + utils.UnknownLabel(ethLabel.LabelHash),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = repo.CreateLabel(ethLabel)
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := ensRepo.GetRecord("fakeEthNameHash", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.FullName).To(Equal("eth"))
+			record, err = ensRepo.GetRecord("fakeNameHash", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.FullName).To(Equal("vitalik.eth"))
+			record, err = ensRepo.GetCurrentRecord("fakeNameHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.FullName).To(Equal("vitalik.eth"))
+			record, err = ensRepo.GetRecord("fakeSubNameHash", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.FullName).To(Equal("sub.vitalik.eth"))
+		})
+	})
+
+	Describe("ImportWordlist", func() {
+		It("Imports every label in the wordlist, splitting dotted names", func() {
+			err := repo.CreateLabel(ethLabel)
+			Expect(err).ToNot(HaveOccurred())
+
+			imported, err := repo.ImportWordlist(strings.NewReader("vitalik\n\nmyname.eth\n"), "wordlist")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(imported).To(Equal(2))
+
+			label, err := repo.GetLabel(utils.LabelHash("myname"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(label).To(Equal("myname"))
+			label, err = repo.GetLabel(utils.LabelHash("vitalik"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(label).To(Equal("vitalik"))
+		})
+	})
+})
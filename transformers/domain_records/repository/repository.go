@@ -18,6 +18,9 @@ package repository
 
 import (
 	"github.com/hashicorp/golang-lru"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
@@ -27,8 +30,49 @@ type ENSRepository interface {
 	RecordExists(node string) (bool, error)
 	CreateRecord(record models.DomainModel) error
 	GetRecord(node string, blockNumber int64) (*models.DomainModel, error)
+	GetCurrentRecord(node string) (*models.DomainModel, error)
+	GetClosestCurrentRecord(nodes []string) (*models.DomainModel, error)
+	GetRecordHistory(node string, blockNumber int64) ([]models.DomainModel, error)
+	GetChildren(node string, blockNumber int64) ([]models.DomainModel, error)
+	GetDescendants(node string, depth int, blockNumber int64) ([]models.DomainModel, error)
+	GetAncestors(node string) ([]models.DomainModel, error)
+	CreateTextRecord(record models.TextRecordModel) error
+	GetTextRecords(node string, blockNumber int64) ([]models.TextRecordModel, error)
+	GetTextRecordHistory(node, key string) ([]models.TextRecordModel, error)
+	CreateCoinAddress(address models.CoinAddressModel) error
+	GetCoinAddresses(node string, blockNumber int64) ([]models.CoinAddressModel, error)
+	CreateApproval(approval models.ApprovalModel) error
+	GetApprovals(owner string, blockNumber int64) ([]models.ApprovalModel, error)
+	DeleteRecordsFrom(blockNumber int64) error
 }
 
+// The columns of ens.domain_records that are read into a DomainModel
+const domainRecordColumns = `block_number,
+				name_hash,
+				label_hash,
+				parent_hash,
+				owner_addr,
+				resolver_addr,
+				points_to_addr,
+				resolved_name,
+				content_,
+				content_type,
+				pub_key_x,
+				pub_key_y,
+				ttl,
+				text_key,
+				indexed_text_key,
+				text_value,
+				multihash,
+				contenthash,
+				full_name,
+				header_id,
+				migrated,
+				contenthash_protocol,
+				contenthash_uri,
+				effective_content_protocol,
+				effective_content_uri`
+
 type ensRepository struct {
 	db          *postgres.DB
 	cachedNodes *lru.Cache
@@ -59,8 +103,14 @@ func (r *ensRepository) RecordExists(node string) (bool, error) {
 	return exists, err
 }
 
+// Persists the record, and makes it the current record of its node in ens.current_domains within the same transaction
+// unless the node already has a current record from a later block
 func (r *ensRepository) CreateRecord(record models.DomainModel) error {
-	_, err := r.db.Exec(
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
 		`INSERT INTO ens.domain_records
 			    (block_number, 
 			    name_hash, 
@@ -77,9 +127,17 @@ func (r *ensRepository) CreateRecord(record models.DomainModel) error {
 				ttl,
 				text_key,
 				indexed_text_key,
+				text_value,
 				multihash,
-				contenthash)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+				contenthash,
+				full_name,
+				header_id,
+				migrated,
+				contenthash_protocol,
+				contenthash_uri,
+				effective_content_protocol,
+				effective_content_uri)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)
 			    ON CONFLICT (block_number, name_hash) DO UPDATE SET
 				(block_number, 
 			    name_hash, 
@@ -96,8 +154,16 @@ func (r *ensRepository) CreateRecord(record models.DomainModel) error {
 				ttl,
 				text_key,
 				indexed_text_key,
+				text_value,
 				multihash,
-				contenthash) = ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+				contenthash,
+				full_name,
+				header_id,
+				migrated,
+				contenthash_protocol,
+				contenthash_uri,
+				effective_content_protocol,
+				effective_content_uri) = ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)`,
 		record.BlockNumber,
 		record.NameHash,
 		record.LabelHash,
@@ -113,10 +179,33 @@ func (r *ensRepository) CreateRecord(record models.DomainModel) error {
 		record.TTL,
 		record.TextKey,
 		record.IndexedTextKey,
+		record.TextValue,
 		record.Multihash,
 		record.Contenthash,
+		record.FullName,
+		record.HeaderID,
+		record.Migrated,
+		record.ContenthashProtocol,
+		record.ContenthashURI,
+		record.EffectiveContentProtocol,
+		record.EffectiveContentURI,
 	)
-
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	err = updateCurrentDomain(tx, record.NameHash, record.BlockNumber)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
@@ -126,6 +215,28 @@ func (r *ensRepository) CreateRecord(record models.DomainModel) error {
 	return nil
 }
 
+// Replaces the current record of the node with its record at the given blockheight, if that is at least as recent
+func updateCurrentDomain(tx *sqlx.Tx, node string, blockNumber int64) error {
+	_, err := tx.Exec(
+		`DELETE FROM ens.current_domains WHERE name_hash = $1 AND block_number <= $2`,
+		node, blockNumber,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO ens.current_domains (`+domainRecordColumns+`)
+		 SELECT `+domainRecordColumns+`
+		 FROM ens.domain_records
+		 WHERE name_hash = $1
+		 AND block_number = $2
+		 ON CONFLICT (name_hash) DO NOTHING`,
+		node, blockNumber,
+	)
+
+	return err
+}
+
 // Gets the record for the give node at the given blockheight
 // We only store a new records when something has changed, so if a record does not exist for that precise blockheight
 // The most recent record previous to that blockheight is the state of the record at that blockheight
@@ -141,23 +252,7 @@ func (r *ensRepository) GetRecord(node string, blockNumber int64) (*models.Domai
 	}
 	var result models.DomainModel
 	err = r.db.Get(&result,
-		`SELECT block_number, 
-			    name_hash, 
-				label_hash, 
-				parent_hash, 
-				owner_addr, 
-				resolver_addr, 
-				points_to_addr, 
-				resolved_name, 
-				content_,
-				content_type,
-				pub_key_x,
-				pub_key_y,
-				ttl,
-				text_key,
-				indexed_text_key,
-				multihash,
-				contenthash
+		`SELECT `+domainRecordColumns+`
 		 FROM ens.domain_records
 		 WHERE name_hash = $1
 		 AND block_number <= $2 
@@ -167,3 +262,288 @@ func (r *ensRepository) GetRecord(node string, blockNumber int64) (*models.Domai
 
 	return &result, err
 }
+
+// Gets the current record of the given node from ens.current_domains, without searching its history
+// Returns sql.ErrNoRows if the node has never been seen
+func (r *ensRepository) GetCurrentRecord(node string) (*models.DomainModel, error) {
+	var result models.DomainModel
+	err := r.db.Get(&result,
+		`SELECT `+domainRecordColumns+`
+		 FROM ens.current_domains
+		 WHERE name_hash = $1`,
+		node,
+	)
+
+	return &result, err
+}
+
+// Gets the current record of the first of the given nodes that has been seen, e.g. of the closest ancestor of a name when
+// given the nodes of the name and its ancestors from the name up
+// Returns sql.ErrNoRows if none of the nodes has been seen
+func (r *ensRepository) GetClosestCurrentRecord(nodes []string) (*models.DomainModel, error) {
+	var result models.DomainModel
+	err := r.db.Get(&result,
+		`SELECT `+domainRecordColumns+`
+		 FROM ens.current_domains
+		 WHERE name_hash = ANY($1::VARCHAR(66)[])
+		 ORDER BY array_position($1::VARCHAR(66)[], name_hash)
+		 LIMIT 1`,
+		pq.Array(nodes),
+	)
+
+	return &result, err
+}
+
+// Gets every record of the given node at or before the given blockheight, oldest first
+// Each record is the state of the node from its block until the next record's block
+func (r *ensRepository) GetRecordHistory(node string, blockNumber int64) ([]models.DomainModel, error) {
+	var results []models.DomainModel
+	err := r.db.Select(&results,
+		`SELECT `+domainRecordColumns+`
+		 FROM ens.domain_records
+		 WHERE name_hash = $1
+		 AND block_number <= $2
+		 ORDER BY block_number`,
+		node, blockNumber,
+	)
+
+	return results, err
+}
+
+// Gets the records of the nodes directly under the given node at the given blockheight, ordered by full name
+func (r *ensRepository) GetChildren(node string, blockNumber int64) ([]models.DomainModel, error) {
+	var results []models.DomainModel
+	err := r.db.Select(&results,
+		`SELECT `+domainRecordColumns+`
+		 FROM ens.domain_children($1, $2) WITH ORDINALITY
+		 ORDER BY ordinality`,
+		node, blockNumber,
+	)
+
+	return results, err
+}
+
+// Gets the records of every node below the given node at the given blockheight, down to the given depth
+// Children are at depth 1, and a depth below 1 gets the whole subtree; records are ordered by depth, then by full name
+func (r *ensRepository) GetDescendants(node string, depth int, blockNumber int64) ([]models.DomainModel, error) {
+	var results []models.DomainModel
+	err := r.db.Select(&results,
+		`SELECT `+domainRecordColumns+`
+		 FROM ens.domain_descendants($1, $2, $3) WITH ORDINALITY
+		 ORDER BY ordinality`,
+		node, depth, blockNumber,
+	)
+
+	return results, err
+}
+
+// Gets the current records of every ancestor of the given node, from its parent up to the root node
+// Ancestors above the first one that has never been seen can't be walked to, and are left out
+func (r *ensRepository) GetAncestors(node string) ([]models.DomainModel, error) {
+	var results []models.DomainModel
+	err := r.db.Select(&results,
+		`SELECT `+domainRecordColumns+`
+		 FROM ens.domain_ancestors($1, (SELECT MAX(block_number) FROM public.headers)) WITH ORDINALITY
+		 ORDER BY ordinality`,
+		node,
+	)
+
+	return results, err
+}
+
+func (r *ensRepository) CreateTextRecord(record models.TextRecordModel) error {
+	_, err := r.db.Exec(
+		`INSERT INTO ens.domain_text_records (block_number, name_hash, key, value, resolver_addr, header_id)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (name_hash, key, block_number) DO UPDATE SET
+				(value, resolver_addr, header_id) = ($4, $5, $6)`,
+		record.BlockNumber,
+		record.NameHash,
+		record.Key,
+		record.Value,
+		record.ResolverAddr,
+		record.HeaderID,
+	)
+
+	return err
+}
+
+// Gets the text records for the given node at the given blockheight, one for each key that has been set on the node
+// As with domain records, the most recent record previous to the blockheight is the state of that key at the blockheight
+func (r *ensRepository) GetTextRecords(node string, blockNumber int64) ([]models.TextRecordModel, error) {
+	var results []models.TextRecordModel
+	err := r.db.Select(&results,
+		`SELECT DISTINCT ON (key) block_number, header_id, name_hash, key, value, resolver_addr
+		 FROM ens.domain_text_records
+		 WHERE name_hash = $1
+		 AND block_number <= $2
+		 ORDER BY key, block_number DESC`,
+		node, blockNumber,
+	)
+
+	return results, err
+}
+
+// Gets every change to the given text key on the given node, oldest first
+func (r *ensRepository) GetTextRecordHistory(node, key string) ([]models.TextRecordModel, error) {
+	var results []models.TextRecordModel
+	err := r.db.Select(&results,
+		`SELECT block_number, header_id, name_hash, key, value, resolver_addr
+		 FROM ens.domain_text_records
+		 WHERE name_hash = $1
+		 AND key = $2
+		 ORDER BY block_number`,
+		node, key,
+	)
+
+	return results, err
+}
+
+func (r *ensRepository) CreateCoinAddress(address models.CoinAddressModel) error {
+	_, err := r.db.Exec(
+		`INSERT INTO ens.domain_coin_addresses (block_number, name_hash, coin_type, address, raw_address, resolver_addr, header_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (name_hash, coin_type, block_number) DO UPDATE SET
+				(address, raw_address, resolver_addr, header_id) = ($4, $5, $6, $7)`,
+		address.BlockNumber,
+		address.NameHash,
+		address.CoinType,
+		address.Address,
+		address.RawAddress,
+		address.ResolverAddr,
+		address.HeaderID,
+	)
+
+	return err
+}
+
+// Gets the addresses set on the given node for each coin type as of the given blockheight
+// Coin types whose address has been cleared are returned with an empty address
+func (r *ensRepository) GetCoinAddresses(node string, blockNumber int64) ([]models.CoinAddressModel, error) {
+	var results []models.CoinAddressModel
+	err := r.db.Select(&results,
+		`SELECT DISTINCT ON (coin_type) block_number, header_id, name_hash, coin_type, address, raw_address, resolver_addr
+		 FROM ens.domain_coin_addresses
+		 WHERE name_hash = $1
+		 AND block_number <= $2
+		 ORDER BY coin_type, block_number DESC`,
+		node, blockNumber,
+	)
+
+	return results, err
+}
+
+func (r *ensRepository) CreateApproval(approval models.ApprovalModel) error {
+	_, err := r.db.Exec(
+		`INSERT INTO ens.registry_approvals (block_number, registry_addr, owner_addr, operator_addr, approved, header_id)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (registry_addr, owner_addr, operator_addr, block_number) DO UPDATE SET
+				(approved, header_id) = ($5, $6)`,
+		approval.BlockNumber,
+		approval.RegistryAddr,
+		approval.Owner,
+		approval.Operator,
+		approval.Approved,
+		approval.HeaderID,
+	)
+
+	return err
+}
+
+// Gets the operators the given owner has approved or revoked as of the given blockheight, one for each registry and operator
+// Operators whose approval has been revoked are returned with Approved set to false
+func (r *ensRepository) GetApprovals(owner string, blockNumber int64) ([]models.ApprovalModel, error) {
+	var results []models.ApprovalModel
+	err := r.db.Select(&results,
+		`SELECT DISTINCT ON (registry_addr, operator_addr) block_number, header_id, registry_addr, owner_addr, operator_addr, approved
+		 FROM ens.registry_approvals
+		 WHERE owner_addr = $1
+		 AND block_number <= $2
+		 ORDER BY registry_addr, operator_addr, block_number DESC`,
+		owner, blockNumber,
+	)
+
+	return results, err
+}
+
+// Deletes the domain records, text records, coin addresses, approvals, and reverse records derived from the registry at or above the given blockheight so that they can be re-derived after a reorg
+// The state of every node then reverts to its most recent record below the blockheight
+func (r *ensRepository) DeleteRecordsFrom(blockNumber int64) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM ens.domain_records WHERE block_number >= $1`, blockNumber)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	// Current records at or above the blockheight, including those of replaced headers, revert to the node's most recent remaining record
+	var reverted []string
+	err = tx.Select(&reverted, `DELETE FROM ens.current_domains WHERE block_number >= $1 RETURNING name_hash`, blockNumber)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO ens.current_domains (`+domainRecordColumns+`)
+		 SELECT DISTINCT ON (name_hash) `+domainRecordColumns+`
+		 FROM ens.domain_records
+		 WHERE name_hash = ANY($1)
+		 ORDER BY name_hash, block_number DESC`,
+		pq.Array(reverted),
+	)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM ens.domain_text_records WHERE block_number >= $1`, blockNumber)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM ens.registry_approvals WHERE block_number >= $1`, blockNumber)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM ens.domain_coin_addresses WHERE block_number >= $1`, blockNumber)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM ens.reverse_records WHERE block_number >= $1 AND source = $2`, blockNumber, models.ReverseSourceNewOwner)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	// Nodes that were first seen in the deleted range no longer exist
+	r.cachedNodes.Purge()
+
+	return nil
+}
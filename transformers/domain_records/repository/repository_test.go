@@ -17,6 +17,8 @@
 package repository_test
 
 import (
+	"database/sql"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -24,6 +26,8 @@ import (
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 )
 
 var _ = Describe("Repository", func() {
@@ -38,9 +42,13 @@ var _ = Describe("Repository", func() {
 		Owner:       "fakeOwnerAddress",
 	}
 
+	var headerIds map[int64]int64
+
 	BeforeEach(func() {
 		db, _ = test_helpers.SetupENSRepo(3327417)
 		repo = repository.NewENSRepository(db)
+		headerIds = test_helpers.CreateHeaders(db, 3327417, 3327425)
+		mockRecord.HeaderID = headerIds[3327420]
 	})
 
 	AfterEach(func() {
@@ -87,11 +95,13 @@ var _ = Describe("Repository", func() {
 
 			mockRecord2 := mockRecord
 			mockRecord2.BlockNumber = 3327421
+			mockRecord2.HeaderID = headerIds[3327421]
 			err = repo.CreateRecord(mockRecord2)
 			Expect(err).ToNot(HaveOccurred())
 
 			mockRecord3 := mockRecord
 			mockRecord3.BlockNumber = 3327422
+			mockRecord3.HeaderID = headerIds[3327422]
 			err = repo.CreateRecord(mockRecord3)
 			Expect(err).ToNot(HaveOccurred())
 
@@ -99,6 +109,24 @@ var _ = Describe("Repository", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(*record).To(Equal(mockRecord3))
 		})
+
+		It("Gets every record of a node up to the given blockheight, oldest first", func() {
+			err := repo.CreateRecord(mockRecord)
+			Expect(err).ToNot(HaveOccurred())
+			mockRecord2 := mockRecord
+			mockRecord2.BlockNumber = 3327422
+			mockRecord2.HeaderID = headerIds[3327422]
+			mockRecord2.Owner = "fakeNewOwnerAddress"
+			err = repo.CreateRecord(mockRecord2)
+			Expect(err).ToNot(HaveOccurred())
+
+			history, err := repo.GetRecordHistory("fakeNameHash", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(history).To(Equal([]models.DomainModel{mockRecord}))
+			history, err = repo.GetRecordHistory("fakeNameHash", 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(history).To(Equal([]models.DomainModel{mockRecord, mockRecord2}))
+		})
 	})
 
 	Describe("CreateRecord", func() {
@@ -158,5 +186,384 @@ var _ = Describe("Repository", func() {
 			Expect(record.PointsToAddr).To(Equal("fakePointsToAddress"))
 			Expect(record.ResolverAddr).To(Equal("fakeResolverAddress"))
 		})
+
+		It("Persists whether the node has been migrated to the registry with fallback", func() {
+			migratedRecord := mockRecord
+			migratedRecord.BlockNumber = 3327421
+			migratedRecord.HeaderID = headerIds[3327421]
+			migratedRecord.Migrated = true
+			err := repo.CreateRecord(migratedRecord)
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := repo.GetRecord("fakeNameHash", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.Migrated).To(Equal(true))
+		})
+	})
+
+	Describe("Text records", func() {
+		avatar := models.TextRecordModel{
+			BlockNumber:  3327420,
+			NameHash:     "fakeNameHash",
+			Key:          "avatar",
+			Value:        "https://example.com/avatar.png",
+			ResolverAddr: "fakeResolverAddress",
+		}
+		url := models.TextRecordModel{
+			BlockNumber:  3327421,
+			NameHash:     "fakeNameHash",
+			Key:          "url",
+			Value:        "https://example.com",
+			ResolverAddr: "fakeResolverAddress",
+		}
+		updatedAvatar := models.TextRecordModel{
+			BlockNumber:  3327422,
+			NameHash:     "fakeNameHash",
+			Key:          "avatar",
+			Value:        "https://example.com/updated.png",
+			ResolverAddr: "fakeResolverAddress",
+		}
+
+		BeforeEach(func() {
+			avatar.HeaderID = headerIds[avatar.BlockNumber]
+			url.HeaderID = headerIds[url.BlockNumber]
+			updatedAvatar.HeaderID = headerIds[updatedAvatar.BlockNumber]
+			for _, record := range []models.TextRecordModel{avatar, url, updatedAvatar} {
+				err := repo.CreateTextRecord(record)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
+		It("Gets the most recent record for every key set on the node at the given blockheight", func() {
+			records, err := repo.GetTextRecords("fakeNameHash", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]models.TextRecordModel{avatar, url}))
+
+			records, err = repo.GetTextRecords("fakeNameHash", 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]models.TextRecordModel{updatedAvatar, url}))
+		})
+
+		It("Gets the history of a key on the node", func() {
+			history, err := repo.GetTextRecordHistory("fakeNameHash", "avatar")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(history).To(Equal([]models.TextRecordModel{avatar, updatedAvatar}))
+		})
+
+		It("Updates the text record if one already exists for the node and key at the blockheight", func() {
+			movedAvatar := updatedAvatar
+			movedAvatar.ResolverAddr = "fakeResolverAddress2"
+			err := repo.CreateTextRecord(movedAvatar)
+			Expect(err).ToNot(HaveOccurred())
+
+			history, err := repo.GetTextRecordHistory("fakeNameHash", "avatar")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(history).To(Equal([]models.TextRecordModel{avatar, movedAvatar}))
+		})
+	})
+
+	Describe("Coin addresses", func() {
+		ethAddress := models.CoinAddressModel{
+			BlockNumber:  3327420,
+			NameHash:     "fakeNameHash",
+			CoinType:     "60",
+			Address:      "0x314159265dD8dbb310642f98f50C066173C1259b",
+			RawAddress:   "0x314159265dd8dbb310642f98f50c066173c1259b",
+			ResolverAddr: "fakeResolverAddress",
+		}
+		btcAddress := models.CoinAddressModel{
+			BlockNumber:  3327421,
+			NameHash:     "fakeNameHash",
+			CoinType:     "0",
+			Address:      "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			RawAddress:   "0x76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac",
+			ResolverAddr: "fakeResolverAddress",
+		}
+
+		BeforeEach(func() {
+			ethAddress.HeaderID = headerIds[ethAddress.BlockNumber]
+			btcAddress.HeaderID = headerIds[btcAddress.BlockNumber]
+			for _, address := range []models.CoinAddressModel{ethAddress, btcAddress} {
+				err := repo.CreateCoinAddress(address)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
+		It("Gets the most recent address for every coin type set on the node at the given blockheight", func() {
+			addresses, err := repo.GetCoinAddresses("fakeNameHash", 3327420)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(addresses).To(Equal([]models.CoinAddressModel{ethAddress}))
+
+			clearedEth := ethAddress
+			clearedEth.BlockNumber = 3327422
+			clearedEth.HeaderID = headerIds[3327422]
+			clearedEth.Address = ""
+			clearedEth.RawAddress = "0x"
+			err = repo.CreateCoinAddress(clearedEth)
+			Expect(err).ToNot(HaveOccurred())
+
+			addresses, err = repo.GetCoinAddresses("fakeNameHash", 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(addresses).To(Equal([]models.CoinAddressModel{btcAddress, clearedEth}))
+		})
+
+		It("Deletes the addresses at or above the blockheight", func() {
+			err := repo.DeleteRecordsFrom(3327421)
+			Expect(err).ToNot(HaveOccurred())
+
+			addresses, err := repo.GetCoinAddresses("fakeNameHash", 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(addresses).To(Equal([]models.CoinAddressModel{ethAddress}))
+		})
+	})
+
+	Describe("Approvals", func() {
+		approval := models.ApprovalModel{
+			BlockNumber:  3327420,
+			RegistryAddr: "fakeRegistryAddress",
+			Owner:        "fakeOwnerAddress",
+			Operator:     "fakeOperatorAddress",
+			Approved:     true,
+		}
+
+		It("Gets the latest approval of each operator at the given blockheight", func() {
+			approval.HeaderID = headerIds[3327420]
+			err := repo.CreateApproval(approval)
+			Expect(err).ToNot(HaveOccurred())
+			revocation := approval
+			revocation.BlockNumber = 3327422
+			revocation.HeaderID = headerIds[3327422]
+			revocation.Approved = false
+			err = repo.CreateApproval(revocation)
+			Expect(err).ToNot(HaveOccurred())
+
+			approvals, err := repo.GetApprovals("fakeOwnerAddress", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(approvals).To(Equal([]models.ApprovalModel{approval}))
+			approvals, err = repo.GetApprovals("fakeOwnerAddress", 3327422)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(approvals).To(Equal([]models.ApprovalModel{revocation}))
+		})
+	})
+
+	Describe("Current domains", func() {
+		It("Keeps the most recent record of each node as its current record", func() {
+			err := repo.CreateRecord(mockRecord)
+			Expect(err).ToNot(HaveOccurred())
+			laterRecord := mockRecord
+			laterRecord.BlockNumber = 3327422
+			laterRecord.HeaderID = headerIds[3327422]
+			laterRecord.Owner = "fakeLaterOwnerAddress"
+			err = repo.CreateRecord(laterRecord)
+			Expect(err).ToNot(HaveOccurred())
+			earlierRecord := mockRecord
+			earlierRecord.BlockNumber = 3327421
+			earlierRecord.HeaderID = headerIds[3327421]
+			earlierRecord.Owner = "fakeEarlierOwnerAddress"
+			err = repo.CreateRecord(earlierRecord)
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := repo.GetCurrentRecord("fakeNameHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*record).To(Equal(laterRecord))
+		})
+
+		It("Gets the current record of the first of the given nodes that has been seen", func() {
+			err := repo.CreateRecord(mockRecord)
+			Expect(err).ToNot(HaveOccurred())
+			childRecord := mockRecord
+			childRecord.NameHash = "fakeChildNameHash"
+			childRecord.ParentHash = "fakeNameHash"
+			err = repo.CreateRecord(childRecord)
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := repo.GetClosestCurrentRecord([]string{"fakeUnseenNameHash", "fakeChildNameHash", "fakeNameHash"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*record).To(Equal(childRecord))
+			record, err = repo.GetClosestCurrentRecord([]string{"fakeUnseenNameHash", "fakeNameHash", "fakeChildNameHash"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*record).To(Equal(mockRecord))
+			_, err = repo.GetClosestCurrentRecord([]string{"fakeUnseenNameHash"})
+			Expect(err).To(Equal(sql.ErrNoRows))
+		})
+
+		It("Reverts current records to the most recent record below a reorg", func() {
+			err := repo.CreateRecord(mockRecord)
+			Expect(err).ToNot(HaveOccurred())
+			reorgedRecord := mockRecord
+			reorgedRecord.BlockNumber = 3327421
+			reorgedRecord.HeaderID = headerIds[3327421]
+			reorgedRecord.Owner = "fakeReorgedOwnerAddress"
+			err = repo.CreateRecord(reorgedRecord)
+			Expect(err).ToNot(HaveOccurred())
+			newRecord := mockRecord
+			newRecord.NameHash = "fakeNewNameHash"
+			newRecord.BlockNumber = 3327421
+			newRecord.HeaderID = headerIds[3327421]
+			err = repo.CreateRecord(newRecord)
+			Expect(err).ToNot(HaveOccurred())
+
+			replacement := fakes.GetFakeHeader(3327421)
+			replacement.Hash = "fakeReplacementHash"
+			_, err = repositories.NewHeaderRepository(db).CreateOrUpdateHeader(replacement)
+			Expect(err).ToNot(HaveOccurred())
+			err = repo.DeleteRecordsFrom(3327421)
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := repo.GetCurrentRecord("fakeNameHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*record).To(Equal(mockRecord))
+			_, err = repo.GetCurrentRecord("fakeNewNameHash")
+			Expect(err).To(Equal(sql.ErrNoRows))
+		})
+	})
+
+	Describe("Subdomain tree", func() {
+		ethRecord := models.DomainModel{
+			NameHash:    "fakeEthNameHash",
+			BlockNumber: 3327420,
+			LabelHash:   "fakeEthLabelHash",
+			ParentHash:  "0x0000000000000000000000000000000000000000000000000000000000000000",
+			Owner:       "fakeOwnerAddress",
+			FullName:    "eth",
+		}
+		vitalikRecord := models.DomainModel{
+			NameHash:    "fakeVitalikNameHash",
+			BlockNumber: 3327421,
+			LabelHash:   "fakeVitalikLabelHash",
+			ParentHash:  "fakeEthNameHash",
+			Owner:       "fakeOwnerAddress",
+			FullName:    "vitalik.eth",
+		}
+		aliceRecord := models.DomainModel{
+			NameHash:    "fakeAliceNameHash",
+			BlockNumber: 3327422,
+			LabelHash:   "fakeAliceLabelHash",
+			ParentHash:  "fakeEthNameHash",
+			Owner:       "fakeOwnerAddress",
+			FullName:    "alice.eth",
+		}
+		walletRecord := models.DomainModel{
+			NameHash:    "fakeWalletNameHash",
+			BlockNumber: 3327423,
+			LabelHash:   "fakeWalletLabelHash",
+			ParentHash:  "fakeVitalikNameHash",
+			Owner:       "fakeOwnerAddress",
+			FullName:    "wallet.vitalik.eth",
+		}
+		var transferredVitalikRecord models.DomainModel
+
+		BeforeEach(func() {
+			ethRecord.HeaderID = headerIds[3327420]
+			vitalikRecord.HeaderID = headerIds[3327421]
+			aliceRecord.HeaderID = headerIds[3327422]
+			walletRecord.HeaderID = headerIds[3327423]
+			transferredVitalikRecord = vitalikRecord
+			transferredVitalikRecord.BlockNumber = 3327424
+			transferredVitalikRecord.HeaderID = headerIds[3327424]
+			transferredVitalikRecord.Owner = "fakeNewOwnerAddress"
+			for _, record := range []models.DomainModel{ethRecord, vitalikRecord, aliceRecord, walletRecord, transferredVitalikRecord} {
+				err := repo.CreateRecord(record)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
+		It("Gets the latest record of each child that exists at the blockheight, ordered by full name", func() {
+			children, err := repo.GetChildren("fakeEthNameHash", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(children).To(Equal([]models.DomainModel{vitalikRecord}))
+
+			children, err = repo.GetChildren("fakeEthNameHash", 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(children).To(Equal([]models.DomainModel{aliceRecord, transferredVitalikRecord}))
+		})
+
+		It("Gets the descendants of a node down to the given depth, ordered by depth", func() {
+			descendants, err := repo.GetDescendants("fakeEthNameHash", 1, 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(descendants).To(Equal([]models.DomainModel{aliceRecord, transferredVitalikRecord}))
+
+			descendants, err = repo.GetDescendants("fakeEthNameHash", 0, 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(descendants).To(Equal([]models.DomainModel{aliceRecord, transferredVitalikRecord, walletRecord}))
+
+			descendants, err = repo.GetDescendants("fakeEthNameHash", 0, 3327422)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(descendants).To(Equal([]models.DomainModel{aliceRecord, vitalikRecord}))
+		})
+
+		It("Gets the current records of the ancestors of a node, from its parent up", func() {
+			ancestors, err := repo.GetAncestors("fakeWalletNameHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ancestors).To(Equal([]models.DomainModel{transferredVitalikRecord, ethRecord}))
+
+			ancestors, err = repo.GetAncestors("fakeEthNameHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(ancestors)).To(Equal(0))
+		})
+	})
+
+	Describe("Reorgs", func() {
+		It("Deletes the records of a header when it is replaced", func() {
+			err := repo.CreateRecord(mockRecord)
+			Expect(err).ToNot(HaveOccurred())
+			reorgedRecord := mockRecord
+			reorgedRecord.BlockNumber = 3327421
+			reorgedRecord.HeaderID = headerIds[3327421]
+			reorgedRecord.Owner = "fakeReorgedOwnerAddress"
+			err = repo.CreateRecord(reorgedRecord)
+			Expect(err).ToNot(HaveOccurred())
+
+			// Replace the header at the same height with one that has a different hash
+			replacement := fakes.GetFakeHeader(3327421)
+			replacement.Hash = "fakeReplacementHash"
+			_, err = repositories.NewHeaderRepository(db).CreateOrUpdateHeader(replacement)
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := repo.GetRecord("fakeNameHash", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*record).To(Equal(mockRecord))
+		})
+
+		It("Deletes the domain and text records at or above the blockheight", func() {
+			err := repo.CreateRecord(mockRecord)
+			Expect(err).ToNot(HaveOccurred())
+			laterRecord := mockRecord
+			laterRecord.BlockNumber = 3327422
+			laterRecord.HeaderID = headerIds[3327422]
+			laterRecord.Owner = "fakeLaterOwnerAddress"
+			err = repo.CreateRecord(laterRecord)
+			Expect(err).ToNot(HaveOccurred())
+			err = repo.CreateTextRecord(models.TextRecordModel{
+				BlockNumber:  3327422,
+				HeaderID:     headerIds[3327422],
+				NameHash:     "fakeNameHash",
+				Key:          "avatar",
+				ResolverAddr: "fakeResolverAddress",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = repo.DeleteRecordsFrom(3327421)
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := repo.GetRecord("fakeNameHash", 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*record).To(Equal(mockRecord))
+			textRecords, err := repo.GetTextRecords("fakeNameHash", 3327425)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(textRecords)).To(Equal(0))
+		})
+
+		It("Forgets nodes that were first seen at or above the blockheight", func() {
+			err := repo.CreateRecord(mockRecord)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = repo.DeleteRecordsFrom(3327420)
+			Expect(err).ToNot(HaveOccurred())
+
+			exists, err := repo.RecordExists("fakeNameHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(Equal(false))
+		})
 	})
 })
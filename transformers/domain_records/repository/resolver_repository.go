@@ -0,0 +1,166 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type ResolverRepository interface {
+	CreateResolver(resolver models.ResolverModel) error
+	GetResolvers() ([]models.ResolverModel, error)
+	CreateDetection(detection models.ResolverDetectionModel) error
+	GetDetections(resolverAddr string) ([]models.ResolverDetectionModel, error)
+	FirstUncheckedBlock(startingBlock int64, ids []string) (int64, error)
+	CheckedAfter(blockNumber int64, ids []string) (bool, error)
+	UncheckHeaders(startingBlock int64, ids []string) error
+}
+
+type resolverRepository struct {
+	db *postgres.DB
+}
+
+func NewResolverRepository(db *postgres.DB) *resolverRepository {
+	return &resolverRepository{
+		db: db,
+	}
+}
+
+// Persists a resolver we have configured (or found to be invalid) so that it can be reloaded after a restart
+func (r *resolverRepository) CreateResolver(resolver models.ResolverModel) error {
+	_, err := r.db.Exec(
+		`INSERT INTO ens.watched_resolvers (resolver_addr, starting_block, abi, valid, upgradeable, detected_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (resolver_addr) DO UPDATE SET
+				(starting_block, abi, valid, upgradeable, detected_at) = ($2, $3, $4, $5, $6)`,
+		resolver.Address,
+		resolver.StartingBlock,
+		resolver.Abi,
+		resolver.Valid,
+		resolver.Upgradeable,
+		resolver.DetectedAt,
+	)
+
+	return err
+}
+
+// Gets all of the resolvers that have been seen emitted from the registry, in the order they were first seen
+func (r *resolverRepository) GetResolvers() ([]models.ResolverModel, error) {
+	var resolvers []models.ResolverModel
+	err := r.db.Select(&resolvers,
+		`SELECT resolver_addr, starting_block, abi, valid, upgradeable, detected_at
+		 FROM ens.watched_resolvers
+		 ORDER BY starting_block, id`,
+	)
+
+	return resolvers, err
+}
+
+// Logs how the abi of a resolver was detected, or that it could not be
+func (r *resolverRepository) CreateDetection(detection models.ResolverDetectionModel) error {
+	_, err := r.db.Exec(
+		`INSERT INTO ens.resolver_detections (resolver_addr, block_number, method, code_hash, abi, upgradeable)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+		detection.Address,
+		detection.BlockNumber,
+		detection.Method,
+		detection.CodeHash,
+		detection.Abi,
+		detection.Upgradeable,
+	)
+
+	return err
+}
+
+// Gets every detection logged for a resolver, in the order they were made
+func (r *resolverRepository) GetDetections(resolverAddr string) ([]models.ResolverDetectionModel, error) {
+	var detections []models.ResolverDetectionModel
+	err := r.db.Select(&detections,
+		`SELECT resolver_addr, block_number, method, code_hash, abi, upgradeable
+		 FROM ens.resolver_detections
+		 WHERE resolver_addr = $1
+		 ORDER BY id`,
+		resolverAddr,
+	)
+
+	return detections, err
+}
+
+// Returns the lowest block number at or above the starting block whose header has not been checked for all of the given checked_headers columns
+// If every header has been checked it returns the block after the highest header, and if there are no headers it returns the starting block
+// Used to find where to resume watching a contract after a restart
+func (r *resolverRepository) FirstUncheckedBlock(startingBlock int64, ids []string) (int64, error) {
+	uncheckedQuery := `SELECT MIN(headers.block_number) FROM headers
+				  LEFT JOIN checked_headers on headers.id = header_id
+				  WHERE (header_id ISNULL`
+	for _, id := range ids {
+		uncheckedQuery += ` OR checked_headers.` + id + `= 0`
+	}
+	uncheckedQuery += `) AND headers.block_number >= $1
+				  AND headers.eth_node_fingerprint = $2`
+
+	var block int64
+	err := r.db.Get(&block,
+		`SELECT COALESCE(
+			(`+uncheckedQuery+`),
+			(SELECT MAX(block_number) + 1 FROM headers WHERE block_number >= $1 AND eth_node_fingerprint = $2),
+			$1)`,
+		startingBlock, r.db.Node.ID,
+	)
+
+	return block, err
+}
+
+// Returns whether any header above the given block has been checked for all of the given checked_headers columns
+// When the block is the first unchecked block, this means the headers before it were replaced (reorged) after we checked past them
+func (r *resolverRepository) CheckedAfter(blockNumber int64, ids []string) (bool, error) {
+	pgStr := `SELECT EXISTS(SELECT 1 FROM headers
+				JOIN checked_headers on headers.id = header_id
+				WHERE headers.block_number > $1
+				AND headers.eth_node_fingerprint = $2`
+	for _, id := range ids {
+		pgStr += ` AND checked_headers.` + id + ` > 0`
+	}
+	pgStr += `)`
+
+	var checked bool
+	err := r.db.Get(&checked, pgStr, blockNumber, r.db.Node.ID)
+
+	return checked, err
+}
+
+// Resets the given checked_headers columns for every header at or above the starting block, so that they are checked again
+func (r *resolverRepository) UncheckHeaders(startingBlock int64, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	pgStr := `UPDATE checked_headers SET `
+	for i, id := range ids {
+		if i > 0 {
+			pgStr += `, `
+		}
+		pgStr += id + ` = 0`
+	}
+	pgStr += ` FROM headers
+			WHERE headers.id = checked_headers.header_id
+			AND headers.block_number >= $1
+			AND headers.eth_node_fingerprint = $2`
+	_, err := r.db.Exec(pgStr, startingBlock, r.db.Node.ID)
+
+	return err
+}
@@ -0,0 +1,190 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+)
+
+var _ = Describe("Resolver Repository", func() {
+	var repo repository.ResolverRepository
+	var db *postgres.DB
+	validResolver := models.ResolverModel{
+		Address:       "fakeResolverAddress",
+		StartingBlock: 3327420,
+		Abi:           "fakeAbi",
+		Valid:         true,
+		Upgradeable:   true,
+		DetectedAt:    3327420,
+	}
+	invalidResolver := models.ResolverModel{
+		Address:       "fakeInvalidResolverAddress",
+		StartingBlock: 3327421,
+		Valid:         false,
+	}
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		repo = repository.NewResolverRepository(db)
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	Describe("CreateResolver", func() {
+		It("Persists valid and invalid resolvers", func() {
+			err := repo.CreateResolver(invalidResolver)
+			Expect(err).ToNot(HaveOccurred())
+			err = repo.CreateResolver(validResolver)
+			Expect(err).ToNot(HaveOccurred())
+
+			resolvers, err := repo.GetResolvers()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolvers).To(Equal([]models.ResolverModel{validResolver, invalidResolver}))
+		})
+
+		It("Updates the resolver if it has already been persisted", func() {
+			err := repo.CreateResolver(validResolver)
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedResolver := validResolver
+			updatedResolver.Abi = "updatedAbi"
+			err = repo.CreateResolver(updatedResolver)
+			Expect(err).ToNot(HaveOccurred())
+
+			resolvers, err := repo.GetResolvers()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolvers).To(Equal([]models.ResolverModel{updatedResolver}))
+		})
+	})
+
+	Describe("CreateDetection", func() {
+		It("Logs every detection made for a resolver in order", func() {
+			legacyDetection := models.ResolverDetectionModel{
+				Address:     "fakeResolverAddress",
+				BlockNumber: 3327420,
+				Method:      models.DetectionAllowList,
+				CodeHash:    "fakeCodeHash",
+				Abi:         "fakeAbi",
+				Upgradeable: true,
+			}
+			upgradedDetection := models.ResolverDetectionModel{
+				Address:     "fakeResolverAddress",
+				BlockNumber: 3333180,
+				Method:      models.DetectionInterface,
+				CodeHash:    "fakeUpgradedCodeHash",
+				Abi:         "fakeUpgradedAbi",
+				Upgradeable: true,
+			}
+			err := repo.CreateDetection(legacyDetection)
+			Expect(err).ToNot(HaveOccurred())
+			err = repo.CreateDetection(upgradedDetection)
+			Expect(err).ToNot(HaveOccurred())
+			err = repo.CreateDetection(models.ResolverDetectionModel{
+				Address:     "fakeInvalidResolverAddress",
+				BlockNumber: 3327421,
+				Method:      models.DetectionNone,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			detections, err := repo.GetDetections("fakeResolverAddress")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(detections).To(Equal([]models.ResolverDetectionModel{legacyDetection, upgradedDetection}))
+		})
+	})
+
+	Describe("FirstUncheckedBlock", func() {
+		var headerIds []int64
+
+		BeforeEach(func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerIds = []int64{}
+			for i := int64(3327417); i <= 3327420; i++ {
+				id, err := headerRepository.CreateOrUpdateHeader(fakes.GetFakeHeader(i))
+				Expect(err).ToNot(HaveOccurred())
+				headerIds = append(headerIds, id)
+			}
+		})
+
+		It("Returns the starting block if no headers have been checked", func() {
+			block, err := repo.FirstUncheckedBlock(3327417, []string{"new_owner_checked"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327417)))
+		})
+
+		It("Returns the first block that has not been checked for all of the ids", func() {
+			_, err := db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked, new_ttl_checked) VALUES ($1, 1, 1), ($2, 1, 0)`,
+				headerIds[0], headerIds[1])
+			Expect(err).ToNot(HaveOccurred())
+
+			block, err := repo.FirstUncheckedBlock(3327417, []string{"new_owner_checked", "new_ttl_checked"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327418)))
+		})
+
+		It("Returns the block after the last header if every header has been checked", func() {
+			for _, id := range headerIds {
+				_, err := db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked) VALUES ($1, 1)`, id)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			block, err := repo.FirstUncheckedBlock(3327417, []string{"new_owner_checked"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327421)))
+		})
+
+		It("Reports whether headers above a block have been checked", func() {
+			_, err := db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked) VALUES ($1, 1), ($2, 1)`,
+				headerIds[0], headerIds[2])
+			Expect(err).ToNot(HaveOccurred())
+
+			checked, err := repo.CheckedAfter(3327418, []string{"new_owner_checked"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checked).To(Equal(true))
+			checked, err = repo.CheckedAfter(3327419, []string{"new_owner_checked"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checked).To(Equal(false))
+		})
+
+		It("Unchecks the headers at or above the starting block", func() {
+			for _, id := range headerIds {
+				_, err := db.Exec(`INSERT INTO public.checked_headers (header_id, new_owner_checked, new_ttl_checked) VALUES ($1, 1, 1)`, id)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			err := repo.UncheckHeaders(3327419, []string{"new_owner_checked", "new_ttl_checked"})
+			Expect(err).ToNot(HaveOccurred())
+
+			block, err := repo.FirstUncheckedBlock(3327417, []string{"new_owner_checked", "new_ttl_checked"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(block).To(Equal(int64(3327419)))
+			checked, err := repo.CheckedAfter(3327419, []string{"new_ttl_checked"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checked).To(Equal(false))
+		})
+	})
+})
@@ -0,0 +1,139 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type ReverseRepository interface {
+	CreateReverseRecord(record models.ReverseRecordModel) error
+	GetReverseRecords(address string) ([]models.ReverseRecordModel, error)
+	GetPrimaryName(address string, blockNumber int64) (models.PrimaryNameModel, error)
+	GetTransactionSender(headerID int64, txHash string) (string, error)
+}
+
+type reverseRepository struct {
+	db *postgres.DB
+}
+
+func NewReverseRepository(db *postgres.DB) *reverseRepository {
+	return &reverseRepository{
+		db: db,
+	}
+}
+
+// Persists the claim of a reverse node by an address
+func (r *reverseRepository) CreateReverseRecord(record models.ReverseRecordModel) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	err = CreateReverseRecordInTransaction(tx, record)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Gets every claim of a reverse node by the given address, in the order they were made
+func (r *reverseRepository) GetReverseRecords(address string) ([]models.ReverseRecordModel, error) {
+	var records []models.ReverseRecordModel
+	err := r.db.Select(&records,
+		`SELECT block_number, header_id, address, node, source
+		 FROM ens.reverse_records
+		 WHERE LOWER(address) = LOWER($1)
+		 ORDER BY block_number, id`,
+		address,
+	)
+
+	return records, err
+}
+
+// Gets the primary name of the given address as of the given block, and whether the name resolves back to the address
+// The name is looked up by the namehash of its normalized form, so that it is found whatever its case and whether or not
+// the full names of its records are complete
+// Returns sql.ErrNoRows if the address had not claimed its reverse node at that block
+func (r *reverseRepository) GetPrimaryName(address string, blockNumber int64) (models.PrimaryNameModel, error) {
+	var primaryName models.PrimaryNameModel
+	err := r.db.Get(&primaryName,
+		`SELECT address, node, block_number, name FROM ens.primary_names_at($2, $1)`,
+		address,
+		blockNumber,
+	)
+	if err != nil || primaryName.Name == "" {
+		return primaryName, err
+	}
+
+	var pointsTo string
+	err = r.db.Get(&pointsTo,
+		`SELECT COALESCE(points_to_addr, '') FROM ens.domain_record_at($1, $2)`,
+		utils.NameHash(utils.NormalizeName(primaryName.Name)),
+		blockNumber,
+	)
+	if err == sql.ErrNoRows {
+		return primaryName, nil
+	}
+	primaryName.Verified = strings.EqualFold(pointsTo, primaryName.Address)
+
+	return primaryName, err
+}
+
+// Gets the sender of the given transaction in the given header
+// Returns an empty string if the transaction hasn't been synced
+func (r *reverseRepository) GetTransactionSender(headerID int64, txHash string) (string, error) {
+	var sender string
+	err := r.db.Get(&sender,
+		`SELECT COALESCE(tx_from, '') FROM public.light_sync_transactions WHERE header_id = $1 AND hash = $2`,
+		headerID,
+		txHash,
+	)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return sender, err
+}
+
+// Persists a reverse record within the given transaction so that it can be committed alongside the event it was taken from
+// The block number is taken from the record's header
+func CreateReverseRecordInTransaction(tx *sqlx.Tx, record models.ReverseRecordModel) error {
+	_, err := tx.Exec(
+		`INSERT INTO ens.reverse_records (header_id, block_number, address, node, source)
+				SELECT $1, block_number, $2, $3, $4 FROM public.headers WHERE id = $1
+				ON CONFLICT (header_id, address, node, source) DO NOTHING`,
+		record.HeaderID,
+		record.Address,
+		record.Node,
+		record.Source,
+	)
+
+	return err
+}
@@ -0,0 +1,209 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository_test
+
+import (
+	"database/sql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+var _ = Describe("Reverse Repository", func() {
+	var repo repository.ReverseRepository
+	var ensRepo repository.ENSRepository
+	var db *postgres.DB
+	address := "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"
+	reverseNode := utils.ReverseNodeOf(address)
+	forwardNode := utils.CreateSubnode(utils.CreateSubnode(utils.RootNode, utils.LabelHash("eth")), utils.LabelHash("vitalik"))
+
+	var headerIds map[int64]int64
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		repo = repository.NewReverseRepository(db)
+		ensRepo = repository.NewENSRepository(db)
+		headerIds = test_helpers.CreateHeaders(db, 3327420, 3327423)
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	Describe("CreateReverseRecord", func() {
+		It("Persists every claim made by an address, taking the block number from its header", func() {
+			registrarClaim := models.ReverseRecordModel{
+				BlockNumber: 3327420,
+				HeaderID:    headerIds[3327420],
+				Address:     address,
+				Node:        reverseNode,
+				Source:      models.ReverseSourceRegistrar,
+			}
+			registryClaim := models.ReverseRecordModel{
+				BlockNumber: 3327421,
+				HeaderID:    headerIds[3327421],
+				Address:     address,
+				Node:        reverseNode,
+				Source:      models.ReverseSourceNewOwner,
+			}
+			err := repo.CreateReverseRecord(models.ReverseRecordModel{
+				HeaderID: registrarClaim.HeaderID,
+				Address:  registrarClaim.Address,
+				Node:     registrarClaim.Node,
+				Source:   registrarClaim.Source,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			err = repo.CreateReverseRecord(registryClaim)
+			Expect(err).ToNot(HaveOccurred())
+			err = repo.CreateReverseRecord(registryClaim)
+			Expect(err).ToNot(HaveOccurred())
+
+			records, err := repo.GetReverseRecords("0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]models.ReverseRecordModel{registrarClaim, registryClaim}))
+		})
+	})
+
+	Describe("GetPrimaryName", func() {
+		BeforeEach(func() {
+			err := repo.CreateReverseRecord(models.ReverseRecordModel{
+				HeaderID: headerIds[3327420],
+				Address:  address,
+				Node:     reverseNode,
+				Source:   models.ReverseSourceRegistrar,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			err = ensRepo.CreateRecord(models.DomainModel{
+				NameHash:    reverseNode,
+				BlockNumber: 3327421,
+				HeaderID:    headerIds[3327421],
+				ParentHash:  utils.ReverseNode,
+				LabelHash:   utils.LabelHash(utils.ReverseLabel(address)),
+				Name:        "vitalik.eth",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Returns sql.ErrNoRows if the address had not claimed its reverse node", func() {
+			_, err := repo.GetPrimaryName(address, 3327419)
+			Expect(err).To(Equal(sql.ErrNoRows))
+		})
+
+		It("Returns the name set on the reverse node, unverified until it resolves back to the address", func() {
+			err := ensRepo.CreateRecord(models.DomainModel{
+				NameHash:     forwardNode,
+				BlockNumber:  3327422,
+				HeaderID:     headerIds[3327422],
+				FullName:     "vitalik.eth",
+				PointsToAddr: "0x0000000000000000000000000000000000000001",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			err = ensRepo.CreateRecord(models.DomainModel{
+				NameHash:     forwardNode,
+				BlockNumber:  3327423,
+				HeaderID:     headerIds[3327423],
+				FullName:     "vitalik.eth",
+				PointsToAddr: "0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			primaryName, err := repo.GetPrimaryName(address, 3327420)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(primaryName).To(Equal(models.PrimaryNameModel{
+				Address:     address,
+				Node:        reverseNode,
+				BlockNumber: 3327420,
+				Name:        "",
+				Verified:    false,
+			}))
+			primaryName, err = repo.GetPrimaryName(address, 3327422)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(primaryName.Name).To(Equal("vitalik.eth"))
+			Expect(primaryName.Verified).To(BeFalse())
+			primaryName, err = repo.GetPrimaryName(address, 3327423)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(primaryName.Name).To(Equal("vitalik.eth"))
+			Expect(primaryName.Verified).To(BeTrue())
+		})
+
+		It("Verifies names whatever their case, and whether or not the full names of their records are complete", func() {
+			err := ensRepo.CreateRecord(models.DomainModel{
+				NameHash:    reverseNode,
+				BlockNumber: 3327422,
+				HeaderID:    headerIds[3327422],
+				ParentHash:  utils.ReverseNode,
+				LabelHash:   utils.LabelHash(utils.ReverseLabel(address)),
+				Name:        "Vitalik.ETH",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			err = ensRepo.CreateRecord(models.DomainModel{
+				NameHash:     forwardNode,
+				BlockNumber:  3327422,
+				HeaderID:     headerIds[3327422],
+				FullName:     utils.UnknownLabel(utils.LabelHash("vitalik")) + ".eth",
+				PointsToAddr: "0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			primaryName, err := repo.GetPrimaryName("0xFFD1AC3E8818ADCBE5C597EA076E8D3210B45DF5", 3327422)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(primaryName.Address).To(Equal(address))
+			Expect(primaryName.Name).To(Equal("Vitalik.ETH"))
+			Expect(primaryName.Verified).To(BeTrue())
+		})
+
+		It("Only returns the claim of the given address", func() {
+			err := repo.CreateReverseRecord(models.ReverseRecordModel{
+				HeaderID: headerIds[3327421],
+				Address:  "0x42032C22C510AD0698f16bE9b99640eFDEB02832",
+				Node:     utils.ReverseNodeOf("0x42032C22C510AD0698f16bE9b99640eFDEB02832"),
+				Source:   models.ReverseSourceNewOwner,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			primaryName, err := repo.GetPrimaryName(address, 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(primaryName.Address).To(Equal(address))
+			Expect(primaryName.Node).To(Equal(reverseNode))
+		})
+	})
+
+	Describe("GetTransactionSender", func() {
+		It("Gets the sender of a synced transaction", func() {
+			_, err := db.Exec(`INSERT INTO public.light_sync_transactions (header_id, hash, tx_from) VALUES ($1, $2, $3)`,
+				headerIds[3327420], "0xfakeTxHash", "0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5")
+			Expect(err).ToNot(HaveOccurred())
+
+			sender, err := repo.GetTransactionSender(headerIds[3327420], "0xfakeTxHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sender).To(Equal("0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5"))
+		})
+
+		It("Returns an empty string if the transaction hasn't been synced", func() {
+			sender, err := repo.GetTransactionSender(headerIds[3327420], "0xfakeTxHash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sender).To(Equal(""))
+		})
+	})
+})
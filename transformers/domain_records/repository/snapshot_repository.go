@@ -0,0 +1,144 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type SnapshotRepository interface {
+	GetSnapshot(blockNumber int64, filter models.SnapshotFilter) ([]models.SnapshotModel, error)
+	StreamSnapshot(blockNumber int64, filter models.SnapshotFilter, handle func(models.SnapshotModel) error) error
+	GetCurrentSnapshot(filter models.SnapshotFilter) ([]models.SnapshotModel, error)
+	GetAddressSnapshot(address string, blockNumber int64) ([]models.SnapshotModel, error)
+	GetCurrentAddressSnapshot(address string) ([]models.SnapshotModel, error)
+}
+
+type snapshotRepository struct {
+	db *postgres.DB
+}
+
+func NewSnapshotRepository(db *postgres.DB) *snapshotRepository {
+	return &snapshotRepository{
+		db: db,
+	}
+}
+
+const snapshotColumns = `name_hash, full_name, parent_hash, owner_addr, COALESCE(resolver_addr, '') AS resolver_addr,
+		COALESCE(points_to_addr, '') AS points_to_addr, COALESCE(ttl, '') AS ttl, block_number`
+
+// Empty filter fields match every node
+const snapshotFilter = `($1 = '' OR parent_hash = $1)
+		 AND ($2 = '' OR LOWER(owner_addr) = LOWER($2))
+		 AND ($3 = '' OR LOWER(resolver_addr) = LOWER($3))
+		 AND ($4 = '' OR LOWER(points_to_addr) = LOWER($4))`
+
+const snapshotQuery = `SELECT ` + snapshotColumns + `
+		 FROM ens.domain_snapshot_at($5)
+		 WHERE ` + snapshotFilter + `
+		 ORDER BY name_hash`
+
+// Gets the state of every node matching the filter as of the given block, ordered by name hash
+// Loads the whole snapshot into memory; use StreamSnapshot for large snapshots
+func (r *snapshotRepository) GetSnapshot(blockNumber int64, filter models.SnapshotFilter) ([]models.SnapshotModel, error) {
+	var snapshot []models.SnapshotModel
+	err := r.db.Select(&snapshot, snapshotQuery,
+		filter.ParentHash,
+		filter.Owner,
+		filter.ResolverAddr,
+		filter.PointsToAddr,
+		blockNumber,
+	)
+
+	return snapshot, err
+}
+
+// Passes the state of every node matching the filter as of the given block to handle, ordered by name hash, as the rows are read
+// Stops and returns the error if handle returns one
+func (r *snapshotRepository) StreamSnapshot(blockNumber int64, filter models.SnapshotFilter, handle func(models.SnapshotModel) error) error {
+	rows, err := r.db.Queryx(snapshotQuery,
+		filter.ParentHash,
+		filter.Owner,
+		filter.ResolverAddr,
+		filter.PointsToAddr,
+		blockNumber,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var node models.SnapshotModel
+		err = rows.StructScan(&node)
+		if err != nil {
+			return err
+		}
+		err = handle(node)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Gets the current state of every node matching the filter from ens.current_domains, ordered by name hash
+func (r *snapshotRepository) GetCurrentSnapshot(filter models.SnapshotFilter) ([]models.SnapshotModel, error) {
+	var snapshot []models.SnapshotModel
+	err := r.db.Select(&snapshot,
+		`SELECT `+snapshotColumns+`
+		 FROM ens.current_domains
+		 WHERE `+snapshotFilter+`
+		 ORDER BY name_hash`,
+		filter.ParentHash,
+		filter.Owner,
+		filter.ResolverAddr,
+		filter.PointsToAddr,
+	)
+
+	return snapshot, err
+}
+
+// Gets the state as of the given block of every node owned by or resolving to the address at that block, ordered by name hash
+// Reads the address indexes of ens.domain_records instead of the whole snapshot
+func (r *snapshotRepository) GetAddressSnapshot(address string, blockNumber int64) ([]models.SnapshotModel, error) {
+	var snapshot []models.SnapshotModel
+	err := r.db.Select(&snapshot,
+		`SELECT `+snapshotColumns+`
+		 FROM ens.address_snapshot_at($1, $2)
+		 ORDER BY name_hash`,
+		address,
+		blockNumber,
+	)
+
+	return snapshot, err
+}
+
+// Gets the current state of every node owned by or resolving to the address from ens.current_domains, ordered by name hash
+func (r *snapshotRepository) GetCurrentAddressSnapshot(address string) ([]models.SnapshotModel, error) {
+	var snapshot []models.SnapshotModel
+	err := r.db.Select(&snapshot,
+		`SELECT `+snapshotColumns+`
+		 FROM ens.current_domains
+		 WHERE LOWER(owner_addr) = LOWER($1) OR LOWER(points_to_addr) = LOWER($1)
+		 ORDER BY name_hash`,
+		address,
+	)
+
+	return snapshot, err
+}
@@ -0,0 +1,189 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+)
+
+var _ = Describe("Snapshot Repository", func() {
+	var repo repository.SnapshotRepository
+	var ensRepo repository.ENSRepository
+	var db *postgres.DB
+	ethRecord := models.DomainModel{
+		NameHash:     "fakeEthNameHash",
+		BlockNumber:  3327420,
+		LabelHash:    "fakeEthLabelHash",
+		ParentHash:   "0x0000000000000000000000000000000000000000000000000000000000000000",
+		Owner:        "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5",
+		ResolverAddr: "fakeResolverAddress",
+		TTL:          "0",
+		FullName:     "eth",
+	}
+	vitalikRecord := models.DomainModel{
+		NameHash:     "fakeVitalikNameHash",
+		BlockNumber:  3327421,
+		LabelHash:    "fakeVitalikLabelHash",
+		ParentHash:   "fakeEthNameHash",
+		Owner:        "fakeOwnerAddress",
+		ResolverAddr: "fakeResolverAddress",
+		PointsToAddr: "fakePointsToAddress",
+		TTL:          "3600",
+		FullName:     "vitalik.eth",
+	}
+	var transferredEthRecord models.DomainModel
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		repo = repository.NewSnapshotRepository(db)
+		ensRepo = repository.NewENSRepository(db)
+		headerIds := test_helpers.CreateHeaders(db, 3327420, 3327422)
+		ethRecord.HeaderID = headerIds[3327420]
+		vitalikRecord.HeaderID = headerIds[3327421]
+		transferredEthRecord = ethRecord
+		transferredEthRecord.BlockNumber = 3327422
+		transferredEthRecord.HeaderID = headerIds[3327422]
+		transferredEthRecord.Owner = "fakeNewOwnerAddress"
+		for _, record := range []models.DomainModel{ethRecord, vitalikRecord, transferredEthRecord} {
+			err := ensRepo.CreateRecord(record)
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	snapshotOf := func(record models.DomainModel) models.SnapshotModel {
+		return models.SnapshotModel{
+			NameHash:     record.NameHash,
+			FullName:     record.FullName,
+			ParentHash:   record.ParentHash,
+			Owner:        record.Owner,
+			ResolverAddr: record.ResolverAddr,
+			PointsToAddr: record.PointsToAddr,
+			TTL:          record.TTL,
+			BlockNumber:  record.BlockNumber,
+		}
+	}
+
+	Describe("GetSnapshot", func() {
+		It("Gets the state of every node as of the given block", func() {
+			snapshot, err := repo.GetSnapshot(3327420, models.SnapshotFilter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(ethRecord)}))
+
+			snapshot, err = repo.GetSnapshot(3327421, models.SnapshotFilter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(ethRecord), snapshotOf(vitalikRecord)}))
+
+			snapshot, err = repo.GetSnapshot(3327422, models.SnapshotFilter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(transferredEthRecord), snapshotOf(vitalikRecord)}))
+		})
+
+		It("Filters the snapshot on the state of each node as of the given block", func() {
+			snapshot, err := repo.GetSnapshot(3327421, models.SnapshotFilter{Owner: "0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(ethRecord)}))
+
+			snapshot, err = repo.GetSnapshot(3327422, models.SnapshotFilter{Owner: "0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(snapshot)).To(Equal(0))
+
+			snapshot, err = repo.GetSnapshot(3327422, models.SnapshotFilter{ParentHash: "fakeEthNameHash", ResolverAddr: "fakeResolverAddress"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(vitalikRecord)}))
+		})
+	})
+
+	Describe("GetCurrentSnapshot", func() {
+		It("Gets the current state of every node matching the filter", func() {
+			snapshot, err := repo.GetCurrentSnapshot(models.SnapshotFilter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(transferredEthRecord), snapshotOf(vitalikRecord)}))
+
+			snapshot, err = repo.GetCurrentSnapshot(models.SnapshotFilter{PointsToAddr: "fakePointsToAddress"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(vitalikRecord)}))
+		})
+	})
+
+	Describe("GetAddressSnapshot", func() {
+		It("Gets the nodes owned by or resolving to the address as of the given block", func() {
+			snapshot, err := repo.GetAddressSnapshot("0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5", 3327421)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(ethRecord)}))
+
+			snapshot, err = repo.GetAddressSnapshot("0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5", 3327422)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(snapshot)).To(Equal(0))
+
+			snapshot, err = repo.GetAddressSnapshot("fakePointsToAddress", 3327420)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(snapshot)).To(Equal(0))
+
+			snapshot, err = repo.GetAddressSnapshot("fakePointsToAddress", 3327422)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(vitalikRecord)}))
+		})
+	})
+
+	Describe("GetCurrentAddressSnapshot", func() {
+		It("Gets the current state of every node owned by or resolving to the address", func() {
+			snapshot, err := repo.GetCurrentAddressSnapshot("fakeNewOwnerAddress")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(transferredEthRecord)}))
+
+			snapshot, err = repo.GetCurrentAddressSnapshot("fakePointsToAddress")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot).To(Equal([]models.SnapshotModel{snapshotOf(vitalikRecord)}))
+		})
+	})
+
+	Describe("StreamSnapshot", func() {
+		It("Passes each node in the snapshot to the handler in order", func() {
+			var streamed []models.SnapshotModel
+			err := repo.StreamSnapshot(3327422, models.SnapshotFilter{}, func(node models.SnapshotModel) error {
+				streamed = append(streamed, node)
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(streamed).To(Equal([]models.SnapshotModel{snapshotOf(transferredEthRecord), snapshotOf(vitalikRecord)}))
+		})
+
+		It("Stops streaming when the handler returns an error", func() {
+			handlerErr := errors.New("handler error")
+			calls := 0
+			err := repo.StreamSnapshot(3327422, models.SnapshotFilter{}, func(node models.SnapshotModel) error {
+				calls++
+				return handlerErr
+			})
+			Expect(err).To(Equal(handlerErr))
+			Expect(calls).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,499 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+)
+
+type SubgraphRepository interface {
+	GetDomains(query models.EntityQuery) ([]models.SubgraphDomainModel, error)
+	CountDomains(query models.EntityQuery) (int, error)
+	GetAccounts(query models.EntityQuery) ([]models.SubgraphAccountModel, error)
+	GetResolvers(query models.EntityQuery) ([]models.SubgraphResolverModel, error)
+	GetResolverTextKeys(resolverAddr, node string, blockNumber int64) ([]string, error)
+	GetResolverCoinTypes(resolverAddr, node string, blockNumber int64) ([]string, error)
+	GetRegistrations(query models.EntityQuery) ([]models.SubgraphRegistrationModel, error)
+	GetDomainEvents(eventType string, query models.EntityQuery) ([]models.SubgraphDomainEventModel, error)
+}
+
+type subgraphRepository struct {
+	db *postgres.DB
+}
+
+func NewSubgraphRepository(db *postgres.DB) *subgraphRepository {
+	return &subgraphRepository{
+		db: db,
+	}
+}
+
+// Returned for entity queries that filter or order by an unknown field, or compare a field with a value of the wrong type
+type QueryError string
+
+func (e QueryError) Error() string {
+	return string(e)
+}
+
+type fieldKind int
+
+const (
+	// Hashes and addresses, compared case-insensitively
+	idField fieldKind = iota
+	stringField
+	numberField
+	boolField
+)
+
+// A field of a subgraph entity and the SQL expression it is filtered and ordered by; empty values are NULL
+type entityField struct {
+	expression string
+	kind       fieldKind
+}
+
+// Entities are selected from their view as of the latest synced header, and otherwise from a source that reads them as of $1
+// Entities with a byID source are read from it instead when the query filters on an id it can split
+type subgraphEntity struct {
+	latest       string
+	at           string
+	byID         func(id, block string, param func(interface{}) string) (string, bool)
+	columns      string
+	defaultOrder string
+	fields       map[string]entityField
+}
+
+const latestHeaderBlock = "(SELECT MAX(block_number) FROM public.headers)"
+
+// The subgraph identifies resolvers by their lower cased address and node, and has no resolver for the zero address
+const domainResolverID = `CASE WHEN resolver_addr IN ('', '0x0000000000000000000000000000000000000000') THEN NULL
+		ELSE LOWER(resolver_addr) || '-' || name_hash END`
+
+var subgraphDomains = subgraphEntity{
+	latest: "ens.subgraph_domains",
+	at:     "ens.subgraph_domains_at($1)",
+	columns: `name_hash, full_name, label, label_hash, parent_hash, owner_addr, resolver_addr, points_to_addr, ttl, migrated,
+		block_number`,
+	defaultOrder: "name_hash",
+	fields: map[string]entityField{
+		"id":              {"name_hash", idField},
+		"name":            {"NULLIF(full_name, '')", stringField},
+		"labelName":       {"NULLIF(label, '')", stringField},
+		"labelhash":       {"label_hash", idField},
+		"parent":          {"parent_hash", idField},
+		"owner":           {"owner_addr", idField},
+		"resolver":        {domainResolverID, idField},
+		"resolvedAddress": {"NULLIF(points_to_addr, '')", idField},
+		"ttl":             {"NULLIF(ttl, '')::NUMERIC", numberField},
+		"isMigrated":      {"migrated", boolField},
+	},
+}
+
+var subgraphAccounts = subgraphEntity{
+	latest:       "ens.subgraph_accounts",
+	at:           "ens.subgraph_accounts_at($1)",
+	columns:      "address",
+	defaultOrder: "address",
+	fields: map[string]entityField{
+		"id": {"address", idField},
+	},
+}
+
+var subgraphResolvers = subgraphEntity{
+	latest:       "ens.subgraph_resolvers",
+	at:           "ens.subgraph_resolvers_at($1)",
+	byID:         resolverSource,
+	columns:      "id, resolver_addr, name_hash, points_to_addr, contenthash, block_number",
+	defaultOrder: "id",
+	fields: map[string]entityField{
+		"id":          {"id", idField},
+		"address":     {"resolver_addr", idField},
+		"domain":      {"name_hash", idField},
+		"addr":        {"NULLIF(points_to_addr, '')", idField},
+		"contentHash": {"NULLIF(contenthash, '')", stringField},
+	},
+}
+
+// Reads only the records of the node while it used the resolver, rather than every resolver, for a resolver id
+func resolverSource(id, block string, param func(interface{}) string) (string, bool) {
+	separator := strings.Index(id, "-")
+	if separator < 0 {
+		return "", false
+	}
+	resolverAddr, node := id[:separator], id[separator+1:]
+
+	return "ens.subgraph_resolvers_at(" + block + ", " + param(resolverAddr) + ", " + param(node) + ")", true
+}
+
+var subgraphRegistrations = subgraphEntity{
+	latest:       "ens.subgraph_registrations",
+	at:           "ens.subgraph_registrations_at($1)",
+	columns:      "label_hash, label, registered_at, expires, registrant, block_number",
+	defaultOrder: "label_hash",
+	fields: map[string]entityField{
+		"id":               {"label_hash", idField},
+		"labelName":        {"NULLIF(label, '')", stringField},
+		"registrationDate": {"NULLIF(registered_at, '')::NUMERIC", numberField},
+		"expiryDate":       {"NULLIF(expires, '')::NUMERIC", numberField},
+		"registrant":       {"NULLIF(registrant, '')", idField},
+	},
+}
+
+var subgraphDomainEvents = subgraphEntity{
+	latest:       "ens.subgraph_domain_events",
+	at:           "(SELECT * FROM ens.subgraph_domain_events WHERE block_number <= $1)",
+	columns:      "id, type, name_hash, parent_hash, owner_addr, resolver_addr, ttl, transaction_hash, block_number",
+	defaultOrder: "block_number, tx_idx, log_idx",
+	fields: map[string]entityField{
+		"id":            {"id", stringField},
+		"domain":        {"name_hash", idField},
+		"blockNumber":   {"block_number", numberField},
+		"transactionID": {"transaction_hash", idField},
+		"owner":         {"NULLIF(owner_addr, '')", idField},
+		"parentDomain":  {"NULLIF(parent_hash, '')", idField},
+		"resolver":      {domainResolverID, idField},
+		"ttl":           {"NULLIF(ttl, '')::NUMERIC", numberField},
+	},
+}
+
+// Gets the domains matching the query as of its block, ordered by namehash unless the query orders them otherwise
+func (r *subgraphRepository) GetDomains(query models.EntityQuery) ([]models.SubgraphDomainModel, error) {
+	var domains []models.SubgraphDomainModel
+	sql, args, err := subgraphDomains.selectQuery(query, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Select(&domains, sql, args...)
+
+	return domains, err
+}
+
+// Counts the domains matching the query's filter as of its block, ignoring its ordering and pagination
+func (r *subgraphRepository) CountDomains(query models.EntityQuery) (int, error) {
+	var count int
+	source, conditions, args, err := subgraphDomains.filter(query, nil)
+	if err != nil {
+		return 0, err
+	}
+	err = r.db.Get(&count, `SELECT COUNT(*) FROM `+source+conditions, args...)
+
+	return count, err
+}
+
+// Gets the accounts matching the query as of its block, ordered by address unless the query orders them otherwise
+func (r *subgraphRepository) GetAccounts(query models.EntityQuery) ([]models.SubgraphAccountModel, error) {
+	var accounts []models.SubgraphAccountModel
+	sql, args, err := subgraphAccounts.selectQuery(query, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Select(&accounts, sql, args...)
+
+	return accounts, err
+}
+
+// Gets the resolvers matching the query as of its block, ordered by id unless the query orders them otherwise
+func (r *subgraphRepository) GetResolvers(query models.EntityQuery) ([]models.SubgraphResolverModel, error) {
+	var resolvers []models.SubgraphResolverModel
+	sql, args, err := subgraphResolvers.selectQuery(query, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Select(&resolvers, sql, args...)
+
+	return resolvers, err
+}
+
+// Gets the keys of the text records the resolver had set on the node as of the given block (0 for the latest), in the order they were first set
+func (r *subgraphRepository) GetResolverTextKeys(resolverAddr, node string, blockNumber int64) ([]string, error) {
+	var keys []string
+	err := r.db.Select(&keys,
+		`SELECT key
+		 FROM ens.domain_text_records
+		 WHERE LOWER(resolver_addr) = LOWER($1) AND name_hash = $2 AND ($3::BIGINT = 0 OR block_number <= $3)
+		 GROUP BY key
+		 ORDER BY MIN(block_number), key`,
+		resolverAddr,
+		node,
+		blockNumber,
+	)
+
+	return keys, err
+}
+
+// Gets the coin types the resolver had set addresses for on the node as of the given block (0 for the latest), in the order they were first set
+func (r *subgraphRepository) GetResolverCoinTypes(resolverAddr, node string, blockNumber int64) ([]string, error) {
+	var coinTypes []string
+	err := r.db.Select(&coinTypes,
+		`SELECT coin_type::TEXT
+		 FROM ens.domain_coin_addresses
+		 WHERE LOWER(resolver_addr) = LOWER($1) AND name_hash = $2 AND ($3::BIGINT = 0 OR block_number <= $3)
+		 GROUP BY coin_type
+		 ORDER BY MIN(block_number), coin_type`,
+		resolverAddr,
+		node,
+		blockNumber,
+	)
+
+	return coinTypes, err
+}
+
+// Gets the .eth registrations matching the query as of its block, ordered by label hash unless the query orders them otherwise
+func (r *subgraphRepository) GetRegistrations(query models.EntityQuery) ([]models.SubgraphRegistrationModel, error) {
+	var registrations []models.SubgraphRegistrationModel
+	sql, args, err := subgraphRegistrations.selectQuery(query, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Select(&registrations, sql, args...)
+
+	return registrations, err
+}
+
+// Gets the registry events of the given type (every type if it is empty) matching the query as of its block
+// Events are in the order they were emitted unless the query orders them otherwise
+func (r *subgraphRepository) GetDomainEvents(eventType string, query models.EntityQuery) ([]models.SubgraphDomainEventModel, error) {
+	var events []models.SubgraphDomainEventModel
+	var typeCondition func(param func(interface{}) string) string
+	if eventType != "" {
+		typeCondition = func(param func(interface{}) string) string {
+			return "type = " + param(eventType)
+		}
+	}
+	sql, args, err := subgraphDomainEvents.selectQuery(query, typeCondition)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Select(&events, sql, args...)
+
+	return events, err
+}
+
+func (e subgraphEntity) selectQuery(query models.EntityQuery, extra func(param func(interface{}) string) string) (string, []interface{}, error) {
+	source, conditions, args, err := e.filter(query, extra)
+	if err != nil {
+		return "", nil, err
+	}
+	order := e.defaultOrder
+	if query.OrderBy != "" {
+		field, ok := e.fields[query.OrderBy]
+		if !ok {
+			return "", nil, QueryError(fmt.Sprintf("cannot order by unknown field %q", query.OrderBy))
+		}
+		direction := "ASC"
+		switch strings.ToLower(query.OrderDirection) {
+		case "", "asc":
+		case "desc":
+			direction = "DESC"
+		default:
+			return "", nil, QueryError(fmt.Sprintf("invalid order direction %q", query.OrderDirection))
+		}
+		order = field.expression + " " + direction + " NULLS LAST, " + e.defaultOrder
+	}
+
+	args = append(args, query.First)
+	sql := "SELECT " + e.columns + " FROM " + source + conditions + " ORDER BY " + order + " LIMIT $" + strconv.Itoa(len(args))
+	if query.Skip > 0 {
+		args = append(args, query.Skip)
+		sql += " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	return sql, args, nil
+}
+
+// Builds the source the query's entities are read from and the WHERE clause of its filter
+// Conditions are built in the order of their keys so that the same query always produces the same SQL
+func (e subgraphEntity) filter(query models.EntityQuery, extra func(param func(interface{}) string) string) (string, string, []interface{}, error) {
+	var args []interface{}
+	source, block := e.latest, latestHeaderBlock
+	if query.BlockNumber != 0 {
+		args = append(args, query.BlockNumber)
+		source, block = e.at, "$1"
+	}
+	param := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if id, ok := query.Where["id"].(string); ok && e.byID != nil {
+		if idSource, ok := e.byID(strings.ToLower(id), block, param); ok {
+			source = idSource
+		}
+	}
+	source += " AS entities"
+
+	var conditions []string
+	if extra != nil {
+		conditions = append(conditions, extra(param))
+	}
+	keys := make([]string, 0, len(query.Where))
+	for key := range query.Where {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		condition, err := e.condition(key, query.Where[key], param)
+		if err != nil {
+			return "", "", nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return source, "", args, nil
+	}
+
+	return source, " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// Comparisons a field name can be suffixed with; longer suffixes come first so that _not_in isn't read as _in
+var comparisons = []string{"_not_starts_with", "_not_ends_with", "_not_contains", "_starts_with", "_ends_with", "_contains",
+	"_not_in", "_gte", "_lte", "_not", "_gt", "_lt", "_in"}
+
+func (e subgraphEntity) condition(key string, value interface{}, param func(interface{}) string) (string, error) {
+	name, comparison := key, ""
+	field, ok := e.fields[key]
+	for _, suffix := range comparisons {
+		if ok {
+			break
+		}
+		if strings.HasSuffix(key, suffix) {
+			name, comparison = strings.TrimSuffix(key, suffix), suffix
+			field, ok = e.fields[name]
+		}
+	}
+	if !ok {
+		return "", QueryError(fmt.Sprintf("cannot filter by unknown field %q", key))
+	}
+
+	expression := field.expression
+	if field.kind == idField {
+		expression = "LOWER(" + expression + ")"
+	}
+	if value == nil {
+		switch comparison {
+		case "":
+			return expression + " IS NULL", nil
+		case "_not":
+			return expression + " IS NOT NULL", nil
+		}
+		return "", QueryError(fmt.Sprintf("%s cannot be compared with null", key))
+	}
+
+	switch comparison {
+	case "_in", "_not_in":
+		values, ok := value.([]interface{})
+		if !ok {
+			return "", QueryError(fmt.Sprintf("%s must be a list", key))
+		}
+		array := pq.StringArray{}
+		for _, element := range values {
+			converted, err := field.convert(key, element)
+			if err != nil {
+				return "", err
+			}
+			array = append(array, fmt.Sprint(converted))
+		}
+		placeholder := param(array)
+		if field.kind == numberField {
+			placeholder += "::NUMERIC[]"
+		}
+		if comparison == "_in" {
+			return expression + " = ANY(" + placeholder + ")", nil
+		}
+		return expression + " <> ALL(" + placeholder + ")", nil
+	}
+
+	converted, err := field.convert(key, value)
+	if err != nil {
+		return "", err
+	}
+	placeholder := param(converted)
+	if field.kind == numberField {
+		placeholder += "::NUMERIC"
+	}
+	switch comparison {
+	case "":
+		return expression + " = " + placeholder, nil
+	case "_not":
+		return expression + " <> " + placeholder, nil
+	}
+	if field.kind == boolField {
+		return "", QueryError(fmt.Sprintf("%s cannot be compared with %s", name, strings.TrimPrefix(comparison, "_")))
+	}
+	switch comparison {
+	case "_gt":
+		return expression + " > " + placeholder, nil
+	case "_gte":
+		return expression + " >= " + placeholder, nil
+	case "_lt":
+		return expression + " < " + placeholder, nil
+	case "_lte":
+		return expression + " <= " + placeholder, nil
+	}
+	if field.kind == numberField {
+		return "", QueryError(fmt.Sprintf("%s cannot be compared with %s", name, strings.TrimPrefix(comparison, "_")))
+	}
+	switch comparison {
+	case "_contains":
+		return "STRPOS(" + expression + ", " + placeholder + ") > 0", nil
+	case "_not_contains":
+		return "STRPOS(" + expression + ", " + placeholder + ") = 0", nil
+	case "_starts_with":
+		return "LEFT(" + expression + ", LENGTH(" + placeholder + ")) = " + placeholder, nil
+	case "_not_starts_with":
+		return "LEFT(" + expression + ", LENGTH(" + placeholder + ")) <> " + placeholder, nil
+	case "_ends_with":
+		return "RIGHT(" + expression + ", LENGTH(" + placeholder + ")) = " + placeholder, nil
+	}
+	return "RIGHT(" + expression + ", LENGTH(" + placeholder + ")) <> " + placeholder, nil
+}
+
+// Checks a value has the type of the field; hashes and addresses are lower cased and numbers converted to strings
+func (f entityField) convert(key string, value interface{}) (interface{}, error) {
+	switch f.kind {
+	case idField, stringField:
+		s, ok := value.(string)
+		if !ok {
+			return nil, QueryError(fmt.Sprintf("%s must be a string", key))
+		}
+		if f.kind == idField {
+			return strings.ToLower(s), nil
+		}
+		return s, nil
+	case numberField:
+		switch n := value.(type) {
+		case int64, float64:
+			return fmt.Sprint(n), nil
+		case string:
+			_, err := strconv.ParseFloat(n, 64)
+			if err == nil {
+				return n, nil
+			}
+		}
+		return nil, QueryError(fmt.Sprintf("%s must be a number", key))
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return nil, QueryError(fmt.Sprintf("%s must be a boolean", key))
+	}
+	return b, nil
+}
@@ -0,0 +1,350 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repository_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+var _ = Describe("Subgraph Repository", func() {
+	var repo repository.SubgraphRepository
+	var db *postgres.DB
+	var headerIds map[int64]int64
+	owner := "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"
+	resolverAddr := "0x5FfC014343cd971B7eb70732021E26C35B744cc4"
+	ethNode := utils.NameHash("eth")
+	vitalikNode := utils.NameHash("vitalik.eth")
+	ethDomain := models.SubgraphDomainModel{
+		NameHash:    ethNode,
+		FullName:    "eth",
+		Label:       "eth",
+		LabelHash:   utils.LabelHash("eth"),
+		ParentHash:  utils.RootNode,
+		Owner:       owner,
+		BlockNumber: 3327420,
+	}
+	vitalikDomain := models.SubgraphDomainModel{
+		NameHash:     vitalikNode,
+		FullName:     "vitalik.eth",
+		Label:        "vitalik",
+		LabelHash:    utils.LabelHash("vitalik"),
+		ParentHash:   ethNode,
+		Owner:        owner,
+		ResolverAddr: resolverAddr,
+		TTL:          "300",
+		BlockNumber:  3327421,
+	}
+	pointedVitalikDomain := vitalikDomain
+	pointedVitalikDomain.PointsToAddr = owner
+	pointedVitalikDomain.BlockNumber = 3327422
+
+	BeforeEach(func() {
+		db, _ = test_helpers.SetupENSRepo(3327417)
+		repo = repository.NewSubgraphRepository(db)
+		headerIds = test_helpers.CreateHeaders(db, 3327420, 3327422)
+		ensRepo := repository.NewENSRepository(db)
+		for _, domain := range []models.SubgraphDomainModel{ethDomain, vitalikDomain, pointedVitalikDomain} {
+			err := ensRepo.CreateRecord(models.DomainModel{
+				NameHash:     domain.NameHash,
+				BlockNumber:  domain.BlockNumber,
+				HeaderID:     headerIds[domain.BlockNumber],
+				LabelHash:    domain.LabelHash,
+				ParentHash:   domain.ParentHash,
+				Owner:        domain.Owner,
+				ResolverAddr: domain.ResolverAddr,
+				PointsToAddr: domain.PointsToAddr,
+				TTL:          domain.TTL,
+				FullName:     domain.FullName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		labelRepo := repository.NewLabelRepository(db)
+		for _, label := range []string{"eth", "vitalik"} {
+			err := labelRepo.CreateLabel(models.LabelModel{LabelHash: utils.LabelHash(label), Label: label, Source: "wordlist"})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		test_helpers.TearDown(db)
+	})
+
+	Describe("GetDomains", func() {
+		It("Gets the latest state of the domains matching the query", func() {
+			domains, err := repo.GetDomains(models.EntityQuery{First: 100, Where: map[string]interface{}{"parent": ethNode}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domains).To(Equal([]models.SubgraphDomainModel{pointedVitalikDomain}))
+		})
+
+		It("Gets the state of the domains as of the query's block", func() {
+			domains, err := repo.GetDomains(models.EntityQuery{
+				First:       100,
+				Where:       map[string]interface{}{"id": vitalikNode},
+				BlockNumber: 3327421,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domains).To(Equal([]models.SubgraphDomainModel{vitalikDomain}))
+
+			domains, err = repo.GetDomains(models.EntityQuery{First: 100, BlockNumber: 3327420})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domains).To(Equal([]models.SubgraphDomainModel{ethDomain}))
+		})
+
+		It("Compares hashes and addresses case-insensitively and supports the subgraph's comparisons", func() {
+			filters := []map[string]interface{}{
+				{"owner": strings.ToLower(owner), "name_ends_with": ".eth"},
+				{"name_in": []interface{}{"vitalik.eth", "unknown.eth"}},
+				{"resolvedAddress_not": nil},
+				{"ttl_gte": int64(300), "isMigrated": false},
+				{"labelName_starts_with": "vit", "resolver": strings.ToLower(resolverAddr) + "-" + vitalikNode},
+			}
+			for _, where := range filters {
+				domains, err := repo.GetDomains(models.EntityQuery{First: 100, Where: where})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(domains).To(Equal([]models.SubgraphDomainModel{pointedVitalikDomain}))
+			}
+		})
+
+		It("Orders and paginates the domains", func() {
+			domains, err := repo.GetDomains(models.EntityQuery{OrderBy: "name", OrderDirection: "desc", First: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domains).To(Equal([]models.SubgraphDomainModel{pointedVitalikDomain}))
+
+			domains, err = repo.GetDomains(models.EntityQuery{OrderBy: "name", OrderDirection: "desc", First: 1, Skip: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(domains).To(Equal([]models.SubgraphDomainModel{ethDomain}))
+		})
+
+		It("Returns a query error for unknown fields and values of the wrong type", func() {
+			_, err := repo.GetDomains(models.EntityQuery{First: 100, Where: map[string]interface{}{"unknown": "value"}})
+			Expect(err).To(Equal(repository.QueryError(`cannot filter by unknown field "unknown"`)))
+			_, err = repo.GetDomains(models.EntityQuery{First: 100, Where: map[string]interface{}{"ttl_contains": "3"}})
+			Expect(err).To(Equal(repository.QueryError("ttl cannot be compared with contains")))
+			_, err = repo.GetDomains(models.EntityQuery{First: 100, Where: map[string]interface{}{"name_in": "eth"}})
+			Expect(err).To(Equal(repository.QueryError("name_in must be a list")))
+			_, err = repo.GetDomains(models.EntityQuery{First: 100, OrderBy: "unknown"})
+			Expect(err).To(Equal(repository.QueryError(`cannot order by unknown field "unknown"`)))
+		})
+	})
+
+	Describe("CountDomains", func() {
+		It("Counts the domains matching the query as of its block", func() {
+			count, err := repo.CountDomains(models.EntityQuery{Where: map[string]interface{}{"parent": ethNode}, First: 100})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+
+			count, err = repo.CountDomains(models.EntityQuery{Where: map[string]interface{}{"parent": ethNode}, BlockNumber: 3327420})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+	})
+
+	Describe("GetAccounts", func() {
+		It("Gets the lower cased addresses that had owned a domain", func() {
+			accounts, err := repo.GetAccounts(models.EntityQuery{First: 100})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accounts).To(Equal([]models.SubgraphAccountModel{{Address: strings.ToLower(owner)}}))
+		})
+	})
+
+	Describe("Resolvers", func() {
+		It("Gets every resolver set on a node, with the state the node had while it used the resolver", func() {
+			resolvers, err := repo.GetResolvers(models.EntityQuery{First: 100})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolvers).To(Equal([]models.SubgraphResolverModel{{
+				ID:           strings.ToLower(resolverAddr) + "-" + vitalikNode,
+				Address:      resolverAddr,
+				NameHash:     vitalikNode,
+				PointsToAddr: owner,
+				BlockNumber:  3327422,
+			}}))
+
+			resolvers, err = repo.GetResolvers(models.EntityQuery{First: 100, BlockNumber: 3327421})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(resolvers)).To(Equal(1))
+			Expect(resolvers[0].PointsToAddr).To(Equal(""))
+		})
+
+		It("Looks up a resolver by its id as of the query's block", func() {
+			id := resolverAddr + "-" + vitalikNode
+			resolvers, err := repo.GetResolvers(models.EntityQuery{First: 1, Where: map[string]interface{}{"id": id}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(resolvers)).To(Equal(1))
+			Expect(resolvers[0].ID).To(Equal(strings.ToLower(id)))
+			Expect(resolvers[0].PointsToAddr).To(Equal(owner))
+
+			resolvers, err = repo.GetResolvers(models.EntityQuery{First: 1, Where: map[string]interface{}{"id": id}, BlockNumber: 3327421})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(resolvers)).To(Equal(1))
+			Expect(resolvers[0].PointsToAddr).To(Equal(""))
+
+			resolvers, err = repo.GetResolvers(models.EntityQuery{First: 1, Where: map[string]interface{}{"id": resolverAddr + "-" + ethNode}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(resolvers)).To(Equal(0))
+
+			resolvers, err = repo.GetResolvers(models.EntityQuery{First: 1, Where: map[string]interface{}{"id": vitalikNode}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(resolvers)).To(Equal(0))
+		})
+
+		It("Gets the text keys and coin types the resolver had set on the node", func() {
+			ensRepo := repository.NewENSRepository(db)
+			err := ensRepo.CreateTextRecord(models.TextRecordModel{
+				BlockNumber:  3327422,
+				HeaderID:     headerIds[3327422],
+				NameHash:     vitalikNode,
+				Key:          "url",
+				Value:        "https://vitalik.ca",
+				ResolverAddr: resolverAddr,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			err = ensRepo.CreateCoinAddress(models.CoinAddressModel{
+				BlockNumber:  3327421,
+				HeaderID:     headerIds[3327421],
+				NameHash:     vitalikNode,
+				CoinType:     "60",
+				Address:      owner,
+				RawAddress:   strings.ToLower(owner),
+				ResolverAddr: resolverAddr,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			keys, err := repo.GetResolverTextKeys(strings.ToLower(resolverAddr), vitalikNode, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(Equal([]string{"url"}))
+			keys, err = repo.GetResolverTextKeys(resolverAddr, vitalikNode, 3327421)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(keys)).To(Equal(0))
+			coinTypes, err := repo.GetResolverCoinTypes(resolverAddr, vitalikNode, 3327421)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(coinTypes).To(Equal([]string{"60"}))
+		})
+	})
+
+	Describe("GetRegistrations", func() {
+		It("Gets the registrations of .eth names with their latest registrant", func() {
+			labelHash := utils.LabelHash("vitalik")
+			_, err := db.Exec(`INSERT INTO ens.name_registered (header_id, label_hash, owner, expires, tx_idx, log_idx)
+				VALUES ($1, $2, $3, 1600000000, 0, 0)`, headerIds[3327421], labelHash, owner)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec(`INSERT INTO ens.registrar_transfer (header_id, from_addr, to_addr, label_hash, tx_idx, log_idx)
+				VALUES ($1, $2, $3, $4, 0, 0)`, headerIds[3327422], owner, resolverAddr, labelHash)
+			Expect(err).NotTo(HaveOccurred())
+
+			registrations, err := repo.GetRegistrations(models.EntityQuery{First: 100, Where: map[string]interface{}{"expiryDate_gt": int64(1500000000)}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(registrations)).To(Equal(1))
+			Expect(registrations[0].LabelHash).To(Equal(labelHash))
+			Expect(registrations[0].Label).To(Equal("vitalik"))
+			Expect(registrations[0].Expires).To(Equal("1600000000"))
+			Expect(registrations[0].Registrant).To(Equal(resolverAddr))
+
+			registrations, err = repo.GetRegistrations(models.EntityQuery{
+				First:       100,
+				Where:       map[string]interface{}{"registrant": strings.ToLower(owner)},
+				BlockNumber: 3327421,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(registrations)).To(Equal(1))
+		})
+	})
+
+	Describe("GetDomainEvents", func() {
+		BeforeEach(func() {
+			_, err := db.Exec(`INSERT INTO ens.new_owner (header_id, node, label, owner, subnode, tx_idx, log_idx, raw_log)
+				VALUES ($1, $2, $3, $4, $5, 0, 1, '{"transactionHash": "0xabc"}')`,
+				headerIds[3327421], ethNode, utils.LabelHash("vitalik"), owner, vitalikNode)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec(`INSERT INTO ens.new_ttl (header_id, node, ttl, tx_idx, log_idx, raw_log)
+				VALUES ($1, $2, 300, 0, 2, '{"transactionHash": "0xabc"}')`, headerIds[3327421], vitalikNode)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec(`INSERT INTO ens.transfer (header_id, node, owner, tx_idx, log_idx, raw_log)
+				VALUES ($1, $2, $3, 0, 0, '{"transactionHash": "0xdef"}')`, headerIds[3327422], vitalikNode, resolverAddr)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Gets the registry's events in the order they were emitted", func() {
+			events, err := repo.GetDomainEvents("", models.EntityQuery{First: 100, Where: map[string]interface{}{"domain": vitalikNode}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Equal([]models.SubgraphDomainEventModel{{
+				ID:              "3327421-1",
+				Type:            models.DomainEventNewOwner,
+				NameHash:        vitalikNode,
+				ParentHash:      ethNode,
+				Owner:           owner,
+				TransactionHash: "0xabc",
+				BlockNumber:     3327421,
+			}, {
+				ID:              "3327421-2",
+				Type:            models.DomainEventNewTTL,
+				NameHash:        vitalikNode,
+				TTL:             "300",
+				TransactionHash: "0xabc",
+				BlockNumber:     3327421,
+			}, {
+				ID:              "3327422-0",
+				Type:            models.DomainEventTransfer,
+				NameHash:        vitalikNode,
+				Owner:           resolverAddr,
+				TransactionHash: "0xdef",
+				BlockNumber:     3327422,
+			}}))
+		})
+
+		It("Gets the events of a type as of the query's block", func() {
+			events, err := repo.GetDomainEvents(models.DomainEventTransfer, models.EntityQuery{First: 100})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(events)).To(Equal(1))
+
+			events, err = repo.GetDomainEvents("", models.EntityQuery{First: 100, BlockNumber: 3327421, OrderBy: "id", OrderDirection: "desc"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(events)).To(Equal(2))
+			Expect(events[0].ID).To(Equal("3327421-2"))
+		})
+
+		It("Leaves out the original registry's events for a node once the fallback registry has set its owner", func() {
+			_, err := db.Exec(`INSERT INTO ens.new_owner (header_id, node, label, owner, subnode, tx_idx, log_idx, raw_log)
+				VALUES ($1, $2, $3, $4, $5, 0, 3, '{"transactionHash": "0x123", "address": "0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e"}')`,
+				headerIds[3327421], ethNode, utils.LabelHash("vitalik"), resolverAddr, vitalikNode)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec(`INSERT INTO ens.new_ttl (header_id, node, ttl, tx_idx, log_idx, raw_log)
+				VALUES ($1, $2, 600, 0, 1, '{"transactionHash": "0xdef", "address": "0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e"}')`,
+				headerIds[3327422], vitalikNode)
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := repo.GetDomainEvents("", models.EntityQuery{First: 100, Where: map[string]interface{}{"domain": vitalikNode}})
+			Expect(err).NotTo(HaveOccurred())
+			var ids []string
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			Expect(ids).To(Equal([]string{"3327421-1", "3327421-2", "3327421-3", "3327422-1"}))
+			Expect(events[2].Owner).To(Equal(resolverAddr))
+			Expect(events[3].TTL).To(Equal("600"))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package mocks
+
+import (
+	"reflect"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+)
+
+// Mock blockchain whose contract calls return string values looked up by the last string argument passed to the method
+// e.g. a resolver's text(node, key) method returns the value stored under key
+// Bool results (supportsInterface calls) are looked up by the interface id passed to the method
+type MockBlockChain struct {
+	*fakes.MockBlockChain
+	StringValues                       map[string]string
+	SupportedInterfaces                map[[4]byte]bool
+	fetchContractDataErr               error
+	fetchContractDataPassedAddress     string
+	fetchContractDataPassedMethod      string
+	fetchContractDataPassedMethodArgs  []interface{}
+	fetchContractDataPassedBlockNumber int64
+}
+
+func NewMockBlockChain() *MockBlockChain {
+	return &MockBlockChain{
+		MockBlockChain:      fakes.NewMockBlockChain(),
+		StringValues:        map[string]string{},
+		SupportedInterfaces: map[[4]byte]bool{},
+	}
+}
+
+func (chain *MockBlockChain) SetFetchContractDataErr(err error) {
+	chain.fetchContractDataErr = err
+}
+
+func (chain *MockBlockChain) FetchContractData(abiJSON string, address string, method string, methodArgs []interface{}, result interface{}, blockNumber int64) error {
+	chain.fetchContractDataPassedAddress = address
+	chain.fetchContractDataPassedMethod = method
+	chain.fetchContractDataPassedMethodArgs = methodArgs
+	chain.fetchContractDataPassedBlockNumber = blockNumber
+	if chain.fetchContractDataErr != nil {
+		return chain.fetchContractDataErr
+	}
+
+	var key string
+	var interfaceId [4]byte
+	for _, arg := range methodArgs {
+		switch a := arg.(type) {
+		case string:
+			key = a
+		case [4]byte:
+			interfaceId = a
+		}
+	}
+	// Follow the result pointer(s) down to the value they point to
+	value := reflect.ValueOf(result)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(chain.StringValues[key])
+	case reflect.Bool:
+		value.SetBool(chain.SupportedInterfaces[interfaceId])
+	}
+
+	return nil
+}
+
+func (chain *MockBlockChain) AssertFetchContractDataCalledWith(address string, method string, methodArgs []interface{}, blockNumber int64) {
+	Expect(chain.fetchContractDataPassedAddress).To(Equal(address))
+	Expect(chain.fetchContractDataPassedMethod).To(Equal(method))
+	Expect(chain.fetchContractDataPassedMethodArgs).To(Equal(methodArgs))
+	Expect(chain.fetchContractDataPassedBlockNumber).To(Equal(blockNumber))
+}
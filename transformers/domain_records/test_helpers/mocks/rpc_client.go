@@ -0,0 +1,72 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+)
+
+// Mock rpc client whose eth_getCode and eth_getStorageAt calls return the code and storage set for the contract address passed to them
+type MockRpcClient struct {
+	*fakes.MockRpcClient
+	Code              map[common.Address][]byte
+	Storage           map[common.Address]map[common.Hash]common.Hash
+	PassedBlockNumber string
+}
+
+func NewMockRpcClient() *MockRpcClient {
+	return &MockRpcClient{
+		MockRpcClient: fakes.NewMockRpcClient(),
+		Code:          map[common.Address][]byte{},
+		Storage:       map[common.Address]map[common.Hash]common.Hash{},
+	}
+}
+
+func (client *MockRpcClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	address := args[0].(common.Address)
+	client.PassedBlockNumber = args[len(args)-1].(string)
+	switch method {
+	case "eth_getCode":
+		*result.(*hexutil.Bytes) = client.Code[address]
+	case "eth_getStorageAt":
+		value := client.Storage[address][args[1].(common.Hash)]
+		*result.(*hexutil.Bytes) = value.Bytes()
+	default:
+		return client.MockRpcClient.CallContext(ctx, result, method, args...)
+	}
+
+	return nil
+}
+
+// Error the eth node returns in the response to a call, e.g. when the called contract reverts
+type RpcError struct {
+	Code    int
+	Message string
+}
+
+func (err RpcError) Error() string {
+	return err.Message
+}
+
+func (err RpcError) ErrorCode() int {
+	return err.Code
+}
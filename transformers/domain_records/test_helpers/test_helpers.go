@@ -28,6 +28,8 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/helpers/test_helpers/mocks"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
 	rpc2 "github.com/vulcanize/vulcanizedb/pkg/geth/converters/rpc"
@@ -69,6 +71,19 @@ func SetupENSRepo(start int64) (*postgres.DB, *contract.Contract) {
 	return db, info
 }
 
+// Creates fake headers for every block in the given range, returning their ids by block number
+func CreateHeaders(db *postgres.DB, start, end int64) map[int64]int64 {
+	headerRepository := repositories.NewHeaderRepository(db)
+	headerIds := make(map[int64]int64)
+	for i := start; i <= end; i++ {
+		id, err := headerRepository.CreateOrUpdateHeader(fakes.GetFakeHeader(i))
+		Expect(err).NotTo(HaveOccurred())
+		headerIds[i] = id
+	}
+
+	return headerIds
+}
+
 func TearDown(db *postgres.DB) {
 	tx, err := db.Beginx()
 	Expect(err).NotTo(HaveOccurred())
@@ -94,6 +109,26 @@ func TearDown(db *postgres.DB) {
 	Expect(err).NotTo(HaveOccurred())
 	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS new_bid_checked INTEGER NOT NULL DEFAULT 0`)
 	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS name_migrated_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS name_registered_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS name_renewed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS controller_added_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS controller_removed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS registrar_transfer_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS registrar_approval_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS controller_name_registered_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS controller_name_renewed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS new_price_oracle_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
 	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS new_owner_checked INTEGER NOT NULL DEFAULT 0`)
 	Expect(err).NotTo(HaveOccurred())
 	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS new_resolver_checked INTEGER NOT NULL DEFAULT 0`)
@@ -106,6 +141,8 @@ func TearDown(db *postgres.DB) {
 	Expect(err).NotTo(HaveOccurred())
 	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS addr_changed_checked INTEGER NOT NULL DEFAULT 0`)
 	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS address_changed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
 	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS content_changed_checked INTEGER NOT NULL DEFAULT 0`)
 	Expect(err).NotTo(HaveOccurred())
 	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS contenthash_changed_checked INTEGER NOT NULL DEFAULT 0`)
@@ -118,12 +155,48 @@ func TearDown(db *postgres.DB) {
 	Expect(err).NotTo(HaveOccurred())
 	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS text_changed_checked INTEGER NOT NULL DEFAULT 0`)
 	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS interface_changed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS authorisation_changed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS version_changed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS dns_record_changed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS dns_record_deleted_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS dns_zonehash_changed_checked INTEGER NOT NULL DEFAULT 0`)
+	Expect(err).NotTo(HaveOccurred())
 	_, err = tx.Exec(`ALTER TABLE checked_headers ADD column IF NOT EXISTS name_changed_checked INTEGER NOT NULL DEFAULT 0`)
 	Expect(err).NotTo(HaveOccurred())
 
 	_, err = tx.Exec(`DELETE FROM ens.domain_records`)
 	Expect(err).NotTo(HaveOccurred())
 
+	_, err = tx.Exec(`DELETE FROM ens.current_domains`)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = tx.Exec(`DELETE FROM ens.watched_resolvers`)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = tx.Exec(`DELETE FROM ens.resolver_detections`)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = tx.Exec(`DELETE FROM ens.domain_text_records`)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = tx.Exec(`DELETE FROM ens.domain_coin_addresses`)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = tx.Exec(`DELETE FROM ens.labels`)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = tx.Exec(`DELETE FROM ens.registry_approvals`)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = tx.Exec(`DELETE FROM ens.reverse_records`)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = tx.Commit()
 	Expect(err).NotTo(HaveOccurred())
 }
@@ -17,14 +17,22 @@
 package domain_records
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
 
+	factory "github.com/vulcanize/vulcanizedb/libraries/shared/factories/event"
+	"github.com/vulcanize/vulcanizedb/libraries/shared/transactions"
 	"github.com/vulcanize/vulcanizedb/libraries/shared/transformer"
 	"github.com/vulcanize/vulcanizedb/pkg/config"
-	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/light/converter"
 	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/light/fetcher"
 	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/light/repository"
 	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/light/retriever"
@@ -36,19 +44,34 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
 
+	ensconfig "github.com/vulcanize/ens_transformers/transformers/domain_records/config"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/converter"
+	tgetter "github.com/vulcanize/ens_transformers/transformers/domain_records/getter"
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
 	trep "github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_record_changed"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_record_deleted"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_zonehash_changed"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/version_changed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/coins"
+	"github.com/vulcanize/ens_transformers/transformers/shared/contenthash"
 )
 
-// This transformer watches a single ENS Registry, using the resolver addresses emitted from NewResolver events
+// This transformer watches an ENS Registry, using the resolver addresses emitted from NewResolver events
 // it configures and watches every Resolver contract associated with this Registry
 // It compiles data from the Registry and all the Resolver contracts together into a domain_record Postgres table
+// If configured with the ENSRegistryWithFallback alongside the original Registry, it watches both; once a node has been
+// set on the new registry it is migrated, and its state comes from the new registry only
 
 // Requires a light synced vDB (headers) and a running eth node (or infura)
 type Transformer struct {
 	// Database interfaces
 	trep.ENSRepository          // Repository for ENS domain records
+	trep.ResolverRepository     // Repository for the resolvers we have seen emitted from the registry
+	trep.LabelRepository        // Repository for the plaintext labels (preimages) of label hashes
+	trep.ReverseRepository      // Repository for the reverse nodes claimed by addresses
+	trep.DNSRepository          // Repository for the DNS records and zonehashes set on resolvers
 	repository.HeaderRepository // Interface for interaction with header repositories
 
 	// Pre-processing interfaces
@@ -59,14 +82,23 @@ type Transformer struct {
 	// Processing interfaces
 	fetcher.Fetcher     // Fetches event logs, using header hashes
 	converter.Converter // Converts watched event logs into custom log
+	tgetter.TextGetter  // Fetches the values of text records from resolvers
+	tgetter.CodeGetter  // Fetches the bytecode of resolvers; set by the initializer, since the BlockChain interface doesn't expose it
+
+	// Syncs the transactions that claimed reverse nodes, whose sender is the claiming address
+	transactions.ITransactionsSyncer
 
 	// Config for the registry contract
 	RegistryConfig config.ContractConfig
 
-	// Registry contract
+	// Config for detecting the abi of resolvers that don't support ERC-165
+	ResolverDetection ensconfig.ResolverDetection
+
+	// Registry contracts; FallbackRegistry is nil unless the transformer is configured with the ENSRegistryWithFallback
 	Registry             *contract.Contract
+	FallbackRegistry     *contract.Contract
 	registryEventIds     []string
-	registryEventFilters []common.Hash
+	registryEventFilters map[string][]common.Hash
 
 	// Resolver addresses and contracts
 	ResolverAddresses    map[string]bool
@@ -75,9 +107,26 @@ type Transformer struct {
 	resolverEventFilters map[string][]common.Hash
 	invalidResolvers     map[string]bool
 
+	// Blocks the abi of each upgradeable resolver was last detected at
+	upgradeableResolvers map[string]int64
+
 	// Indexes aid in maintaining header continuity
 	registryIndex int64
 	resolverIndex int64
+
+	// Blocks to resume watching reloaded resolvers from, for resolvers that fell behind the registry before a restart
+	resolverResumeBlocks map[string]int64
+}
+
+const emptyAddress = "0x0000000000000000000000000000000000000000"
+
+// Converters for the resolver events that are persisted into the event tables the DNS zones are derived from
+// The DNS event transformers only watch the configured resolver, so the events of every other resolver are persisted from here
+var dnsEventConverters = map[string]factory.Converter{
+	"DNSRecordChanged":   dns_record_changed.DNSRecordChangedConverter{},
+	"DNSRecordDeleted":   dns_record_deleted.DNSRecordDeletedConverter{},
+	"DNSZonehashChanged": dns_zonehash_changed.DNSZonehashChangedConverter{},
+	"VersionChanged":     version_changed.VersionChangedConverter{},
 }
 
 // Order-of-operations:
@@ -95,7 +144,13 @@ func (tr Transformer) NewTransformer(db *postgres.DB, bc core.BlockChain) transf
 	tr.Converter = converter.Converter{}
 	tr.Resolvers = map[string]*contract.Contract{}
 	tr.ENSRepository = trep.NewENSRepository(db)
-	tr.InterfaceGetter = getter.NewInterfaceGetter(bc)
+	tr.ResolverRepository = trep.NewResolverRepository(db)
+	tr.LabelRepository = trep.NewLabelRepository(db)
+	tr.ReverseRepository = trep.NewReverseRepository(db)
+	tr.DNSRepository = trep.NewDNSRepository(db)
+	tr.InterfaceGetter = tgetter.NewInterfaceGetter(bc)
+	tr.TextGetter = tgetter.NewTextGetter(bc)
+	tr.ITransactionsSyncer = transactions.NewTransactionsSyncer(db, bc)
 	tr.BlockRetriever = retriever.NewBlockRetriever(db)
 
 	return &tr
@@ -109,62 +164,119 @@ func (tr *Transformer) Init() error {
 		return err
 	}
 
-	var address string
-	if len(tr.RegistryConfig.Addresses) != 1 {
+	if len(tr.RegistryConfig.Addresses) < 1 || len(tr.RegistryConfig.Addresses) > 2 {
 		return errors.New("transformer configured with incorrect number of registry addresses")
 	}
-	for addr := range tr.RegistryConfig.Addresses {
-		address = addr
+	tr.registryEventIds = make([]string, 0, 9)
+	tr.registryEventFilters = make(map[string][]common.Hash)
+	for address := range tr.RegistryConfig.Addresses {
+		registry, err := tr.configRegistry(address)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(address, ensconfig.RegistryWithFallbackAddress) {
+			tr.FallbackRegistry = registry
+		} else {
+			tr.Registry = registry
+		}
+	}
+	if tr.Registry == nil {
+		return errors.New("transformer configured without the original registry address")
 	}
-	err = tr.Parser.ParseAbiStr(tr.RegistryConfig.Abis[address])
+
+	// Pick up from the first header that has not yet been checked for registry events
+	tr.registryIndex, err = tr.ResolverRepository.FirstUncheckedBlock(tr.Registry.StartingBlock, tr.registryEventIds)
 	if err != nil {
 		return err
 	}
+	tr.resolverIndex = tr.registryIndex
+
+	tr.ResolverAddresses = make(map[string]bool)
+	tr.Resolvers = make(map[string]*contract.Contract)
+	tr.resolverEventIds = make(map[string][]string)
+	tr.resolverEventFilters = make(map[string][]common.Hash)
+	tr.resolverResumeBlocks = make(map[string]int64)
+	tr.invalidResolvers = make(map[string]bool)
+	tr.invalidResolvers[emptyAddress] = true
+	tr.upgradeableResolvers = make(map[string]int64)
+
+	// Reload the resolvers seen before a restart, since the registry headers they were emitted in are already checked
+	return tr.loadResolvers()
+}
+
+// Configures a registry contract for watching using the abi it is configured with
+func (tr *Transformer) configRegistry(address string) (*contract.Contract, error) {
+	err := tr.Parser.ParseAbiStr(tr.RegistryConfig.Abis[address])
+	if err != nil {
+		return nil, err
+	}
 
 	// Aggregate info into registry contract object and store for execution
-	tr.Registry = contract.Contract{
+	registry := contract.Contract{
 		Name:          "ENS-Registry",
 		Network:       tr.RegistryConfig.Network,
 		Address:       address,
 		Abi:           tr.Parser.Abi(),
 		ParsedAbi:     tr.Parser.ParsedAbi(),
 		StartingBlock: tr.RegistryConfig.StartingBlocks[address],
-		Events:        tr.Parser.GetEvents([]string{}), // Watch all events (NewOwner, Transfer, NewTTL, and NewResolver)
+		Events:        tr.Parser.GetEvents([]string{}), // Watch all events (NewOwner, Transfer, NewTTL, NewResolver, and ApprovalForAll)
 		Methods:       nil,
 		FilterArgs:    map[string]bool{},
 		MethodArgs:    map[string]bool{},
 	}.Init()
-	tr.registryIndex = tr.Registry.StartingBlock
-	tr.registryEventIds = make([]string, 0, 4)
-	tr.registryEventFilters = make([]common.Hash, 0, 4)
-	tr.resolverEventIds = make(map[string][]string)
-	tr.resolverEventFilters = make(map[string][]common.Hash)
 
-	for _, e := range tr.Registry.Events {
+	for _, e := range registry.Events {
 		// Generate eventID and use it to create a checked_header column if one does not already exist
-		eventId := strings.ToLower(e.Name + "_" + address)
+		eventId := ensconfig.RegistryEventId(e.Name, address)
 		err := tr.HeaderRepository.AddCheckColumn(eventId)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		tr.registryEventIds = append(tr.registryEventIds, eventId)
-		tr.registryEventFilters = append(tr.registryEventFilters, e.Sig())
+		tr.registryEventFilters[address] = append(tr.registryEventFilters[address], e.Sig())
+	}
+
+	return registry, nil
+}
+
+// Reloads the resolvers persisted during previous runs and configures the valid ones for watching
+func (tr *Transformer) loadResolvers() error {
+	resolvers, err := tr.ResolverRepository.GetResolvers()
+	if err != nil {
+		return err
+	}
+	for _, resolver := range resolvers {
+		tr.ResolverAddresses[resolver.Address] = true
+		if resolver.Upgradeable {
+			tr.upgradeableResolvers[resolver.Address] = resolver.DetectedAt
+		}
+		if !resolver.Valid {
+			tr.invalidResolvers[resolver.Address] = true
+			continue
+		}
+		err = tr.configResolver(resolver.Address, resolver.Abi, resolver.StartingBlock)
+		if err != nil {
+			return err
+		}
+		// Resume watching this resolver from the first header it has not yet been checked for
+		resumeBlock, err := tr.ResolverRepository.FirstUncheckedBlock(resolver.StartingBlock, tr.resolverEventIds[resolver.Address])
+		if err != nil {
+			return err
+		}
+		tr.resolverResumeBlocks[resolver.Address] = resumeBlock
 	}
 
-	tr.ResolverAddresses = make(map[string]bool)
-	tr.Resolvers = make(map[string]*contract.Contract)
-	tr.resolverEventIds = make(map[string][]string)
-	tr.resolverEventFilters = make(map[string][]common.Hash)
-	tr.invalidResolvers = make(map[string]bool)
-	tr.invalidResolvers["0x0000000000000000000000000000000000000000"] = true
 	return nil
 }
 
 // Executes over registry contract
 // Also finds new resolver contracts emitted from NewResolver events and executes over them
 func (tr *Transformer) Execute() error {
-	// Configure converter with the registry contract
-	tr.Converter.Update(tr.Registry)
+	// Re-derive the domain records of any headers that have been replaced by a reorg
+	err := tr.rollbackReorgs()
+	if err != nil {
+		return err
+	}
 
 	// Retrieve unchecked headers for the registry
 	missingHeaders, err := tr.HeaderRepository.MissingHeadersForAll(tr.registryIndex, -1, tr.registryEventIds)
@@ -173,31 +285,18 @@ func (tr *Transformer) Execute() error {
 	}
 	// Iterate over headers
 	for _, header := range missingHeaders {
-		// And collect registry event logs
-		logs, err := tr.Fetcher.FetchLogs([]string{tr.Registry.Address}, tr.registryEventFilters, header)
+		// Collect registry event logs, converted into batches of log mappings (registry address => eventName => []types.Log)
+		logs, err := tr.fetchRegistryLogs(header)
 		if err != nil {
 			return err
 		}
 
-		// If no logs are found mark the header checked for all of these eventIDs and continue
-		if len(logs) < 1 {
-			err = tr.HeaderRepository.MarkHeaderCheckedForAll(header.Id, tr.registryEventIds)
+		// Process the registry log data into our domain records
+		if len(logs) > 0 {
+			err = tr.processRegistryLogs(logs, header)
 			if err != nil {
 				return err
 			}
-			continue
-		}
-
-		// Convert logs into batches of log mappings (eventName => []types.Log)
-		convertedLogs, err := tr.Converter.ConvertBatch(logs, tr.Registry.Events, header.Id)
-		if err != nil {
-			return err
-		}
-
-		// Process the registry log data into our domain records
-		err = tr.processRegistryLogs(convertedLogs, header.BlockNumber)
-		if err != nil {
-			return err
 		}
 
 		// Mark this header checked for registry events
@@ -225,340 +324,773 @@ func (tr *Transformer) Execute() error {
 	return nil
 }
 
-// Process the log data from Registry events into domain record objects
-// Keeps track of Resolver addresses that are seen emitted so that we can watch them downstream
-func (tr *Transformer) processRegistryLogs(logs map[string][]types.Log, blockNumber int64) error {
-	// Process registry NewOwner logs
-	for _, newOwner := range logs["NewOwner"] {
-		parentHash := newOwner.Values["node"]
-		labelHash := newOwner.Values["label"]
-		subnode := utils.CreateSubnode(parentHash, labelHash)
-		var record *models.DomainModel
-		exists, err := tr.ENSRepository.RecordExists(subnode)
-		if err != nil {
-			return err
+// Fetches and converts the logs each registry emitted in the given header
+// The logs of each registry are converted separately, since the converter matches logs to events by signature alone
+// and both registries emit the same events
+func (tr *Transformer) fetchRegistryLogs(header core.Header) (map[string]map[string][]types.Log, error) {
+	registries := []*contract.Contract{tr.Registry}
+	if tr.FallbackRegistry != nil {
+		registries = append(registries, tr.FallbackRegistry)
+	}
+	logs := make(map[string]map[string][]types.Log)
+	for _, registry := range registries {
+		if header.BlockNumber < registry.StartingBlock {
+			continue
 		}
-		if exists { // If a record already exists for this subdomain, retrieve it for updating
-			record, err = tr.ENSRepository.GetRecord(subnode, blockNumber)
-			if err != nil {
-				return err
-			}
-		} else { // If no previous record exists for this subdomain, create a new one
-			record = &models.DomainModel{}
-		}
-		// Update the new or retrieved record with values emitted from this log
-		record.NameHash = subnode
-		record.ParentHash = parentHash
-		record.LabelHash = labelHash
-		record.Owner = newOwner.Values["owner"]
-		record.BlockNumber = blockNumber
-		// Persist the new or updated record
-		err = tr.ENSRepository.CreateRecord(*record)
+		registryLogs, err := tr.Fetcher.FetchLogs([]string{registry.Address}, tr.registryEventFilters[registry.Address], header)
 		if err != nil {
-			return err
+			return nil, err
 		}
-	}
-
-	// Note that for all other logs a record should already exist (NewOwner event from domain's creation must have already occurred)
-	// Process registry Transfer logs
-	for _, transfer := range logs["Transfer"] {
-		// Get most recent/current record
-		lastRecord, err := tr.ENSRepository.GetRecord(transfer.Values["node"], blockNumber)
-		if err != nil {
-			return err
+		if len(registryLogs) < 1 {
+			continue
 		}
-		// Update with changed owner and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.Owner = transfer.Values["owner"]
-		// Persist updated record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
+		// Configure converter with this registry contract
+		tr.Converter.Update(registry)
+		convertedLogs, err := tr.Converter.ConvertBatch(registryLogs, registry.Events, header.Id)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		logs[registry.Address] = convertedLogs
 	}
 
-	// Process registry NewTTL logs
-	for _, ttl := range logs["NewTTL"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(ttl.Values["node"], blockNumber)
-		if err != nil {
-			return err
-		}
-		// Update with changed ttl and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.TTL = ttl.Values["ttl"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
-		if err != nil {
+	return logs, nil
+}
+
+// When vDB replaces a reorged header, the old header's checked_headers row and domain records are deleted with it (ON DELETE CASCADE)
+// If the first unchecked registry header is below our index, or headers above it have already been checked (e.g. before a restart),
+// the range from that header up was rolled back
+// The records derived in that range are deleted and its headers are unchecked so that the registry and resolvers are processed over it again,
+// re-deriving each node's state from its most recent record before the range (its previous canonical record)
+func (tr *Transformer) rollbackReorgs() error {
+	firstUnchecked, err := tr.ResolverRepository.FirstUncheckedBlock(tr.Registry.StartingBlock, tr.registryEventIds)
+	if err != nil {
+		return err
+	}
+	if firstUnchecked >= tr.registryIndex {
+		reorged, err := tr.ResolverRepository.CheckedAfter(firstUnchecked, tr.registryEventIds)
+		if err != nil || !reorged {
 			return err
 		}
 	}
+	log.Infof("headers from block %d have been replaced, re-deriving domain records from that block", firstUnchecked)
 
-	// Process registry NewResolver logs
-	for _, newResolver := range logs["NewResolver"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(newResolver.Values["node"], blockNumber)
-		if err != nil {
-			return err
-		}
-		// Update with changed resolver address and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.ResolverAddr = newResolver.Values["resolver"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
-		if err != nil {
-			return err
+	err = tr.ENSRepository.DeleteRecordsFrom(firstUnchecked)
+	if err != nil {
+		return err
+	}
+	ids := append([]string{}, tr.registryEventIds...)
+	for _, resolverIds := range tr.resolverEventIds {
+		ids = append(ids, resolverIds...)
+	}
+	err = tr.ResolverRepository.UncheckHeaders(firstUnchecked, ids)
+	if err != nil {
+		return err
+	}
+
+	// Rewind the indexes to the start of the rolled back range
+	tr.registryIndex = firstUnchecked
+	tr.resolverIndex = firstUnchecked
+	for addr, resumeBlock := range tr.resolverResumeBlocks {
+		if resumeBlock > firstUnchecked {
+			tr.resolverResumeBlocks[addr] = firstUnchecked
 		}
-		// Add resolver address to list of resolver addresses
-		tr.ResolverAddresses[newResolver.Values["resolver"]] = true
 	}
 
 	return nil
 }
 
-// Configures contracts for watching Resolvers we found emitted from the Registry's NewResolver events
-func (tr *Transformer) configResolvers(blockNumber int64) error {
-	for resolverAddr := range tr.ResolverAddresses {
-		_, ok := tr.Resolvers[resolverAddr]
-		if ok { // Resolver contract has either already been setup or we already know it is invalid
+// Process the log data from Registry events into domain record objects
+// Keeps track of Resolver addresses that are seen emitted so that we can watch them downstream
+func (tr *Transformer) processRegistryLogs(logs map[string]map[string][]types.Log, header core.Header) error {
+	// Apply the logs in the order they were emitted, so that multiple changes to a domain within one block resolve to its final state
+	for _, event := range utils.OrderContractLogs(logs) {
+		fromFallback := tr.FallbackRegistry != nil && event.Address == tr.FallbackRegistry.Address
+		if event.Name == "ApprovalForAll" {
+			err := tr.processApprovalForAll(event, header)
+			if err != nil {
+				return err
+			}
 			continue
 		}
-		_, ok = tr.invalidResolvers[resolverAddr]
-		if ok {
+		// Get most recent state, or a new record for a subdomain that has not been seen before
+		node := event.Values["node"]
+		if event.Name == "NewOwner" {
+			node = utils.CreateSubnode(event.Values["node"], event.Values["label"])
+		}
+		record, err := tr.ENSRepository.GetRecord(node, header.BlockNumber)
+		if err != nil {
+			return err
+		}
+		// Once a node has been migrated the old registry no longer determines its state
+		if record.Migrated && !fromFallback {
 			continue
 		}
-		// Construct the abi for this resolver
-		abiStr := tr.InterfaceGetter.GetABI(resolverAddr, -1)
-		if abiStr == "" {
-			// If abi is empty and we don't support any of the desired interfaces, skip configuring this resolver and add it to the list of invalid resolver so
-			// we don't keep checking the domain records that use this resolver will be incomplete, but we can continue to collect their data from the registry
-			tr.invalidResolvers[resolverAddr] = true
+		if fromFallback && !record.Migrated {
+			// The new registry does not carry over the old registry's resolver or ttl, it reads through to them only until the node is migrated
+			record.Migrated = true
+			record.ResolverAddr = emptyAddress
+			record.TTL = "0"
+		}
+		// Update with the values emitted from this log and block height
+		record.BlockNumber = header.BlockNumber
+		record.HeaderID = header.Id
+		switch event.Name {
+		case "NewOwner":
+			err = tr.processNewOwner(record, event.Log)
+		case "Transfer":
+			record.Owner = event.Values["owner"]
+		case "NewTTL":
+			record.TTL = event.Values["ttl"]
+		case "NewResolver":
+			record.ResolverAddr = event.Values["resolver"]
+			// Add resolver address to list of resolver addresses
+			tr.ResolverAddresses[event.Values["resolver"]] = true
+		default:
 			continue
 		}
-
-		// Load this abi into the abi parser
-		err := tr.Parser.ParseAbiStr(abiStr)
 		if err != nil {
 			return err
 		}
-
-		// Aggregate info into resolver contract object and store for execution
-		tr.Resolvers[resolverAddr] = &contract.Contract{
-			Name:          "ENS-Resolver",
-			Network:       tr.RegistryConfig.Network,
-			Address:       resolverAddr,
-			Abi:           tr.Parser.Abi(),
-			ParsedAbi:     tr.Parser.ParsedAbi(),
-			StartingBlock: blockNumber,                     // Start the resolver contract at the blockheight it was first seen emitted by the Registry from a NewResolver event
-			Events:        tr.Parser.GetEvents([]string{}), // Watch all resolver events
-			Methods:       nil,
-			FilterArgs:    map[string]bool{},
-			MethodArgs:    map[string]bool{},
-		}
-
-		// Create checked_headers columns, event ids, and event sigs for this resolver
-		for _, e := range tr.Resolvers[resolverAddr].Events {
-			eventId := strings.ToLower(e.Name + "_" + resolverAddr)
-			err := tr.HeaderRepository.AddCheckColumn(eventId)
+		// Persist new or updated record
+		err = tr.ENSRepository.CreateRecord(*record)
+		if err != nil {
+			return err
+		}
+		// Subnodes of addr.reverse are claimed by the address their label encodes
+		if event.Name == "NewOwner" && event.Values["node"] == utils.ReverseNode {
+			err = tr.processReverseClaim(record, event, header)
 			if err != nil {
 				return err
 			}
-			tr.resolverEventIds[resolverAddr] = append(tr.resolverEventIds[resolverAddr], eventId)
-			tr.resolverEventFilters[resolverAddr] = append(tr.resolverEventFilters[resolverAddr], e.Sig())
 		}
 	}
 
 	return nil
 }
 
-// Watches the configured Resolvers
-func (tr *Transformer) watchResolvers() error {
-	// Iterate over resolver contracts
-	for addr, resolver := range tr.Resolvers {
-		// Update converter with this contract
-		tr.Converter.Update(resolver)
+// Records the claim of a reverse node set on the registry
+// Claims whose address can't be determined are skipped
+func (tr *Transformer) processReverseClaim(record *models.DomainModel, event utils.EventLog, header core.Header) error {
+	address, err := tr.reverseClaimant(record, event, header)
+	if err != nil {
+		return err
+	}
+	if address == "" {
+		log.Debugf("unable to determine the address claiming reverse node %s", record.NameHash)
+		return nil
+	}
+	address = common.HexToAddress(address).Hex()
+	err = tr.LabelRepository.CreateLabel(models.LabelModel{
+		LabelHash: record.LabelHash,
+		Label:     utils.ReverseLabel(address),
+		Source:    models.ReverseSourceNewOwner,
+	})
+	if err != nil {
+		return err
+	}
 
-		// Retrieve unchecked headers for this resolver
-		missingHeaders, err := tr.HeaderRepository.MissingHeadersForAll(tr.resolverIndex, tr.registryIndex-1, tr.resolverEventIds[addr])
-		if err != nil {
-			return err
-		}
+	return tr.ReverseRepository.CreateReverseRecord(models.ReverseRecordModel{
+		BlockNumber: record.BlockNumber,
+		HeaderID:    record.HeaderID,
+		Address:     address,
+		Node:        record.NameHash,
+		Source:      models.ReverseSourceNewOwner,
+	})
+}
 
-		// Iterate over headers
-		for _, header := range missingHeaders {
-			// And collect event logs for this resolver
-			logs, err := tr.Fetcher.FetchLogs([]string{addr}, tr.resolverEventFilters[addr], header)
-			if err != nil {
-				return err
-			}
+// Returns the address the label of a reverse node encodes: the new owner if the label is the owner's hex address, the label's
+// preimage if that is known to be an address, or otherwise the sender of the transaction that claimed the node if the label is its hex address
+// Before 2023 the ReverseRegistrar made itself the owner of the nodes it claimed through setName and claimWithResolver,
+// and the label encodes the address that called it
+// Returns an empty string if none of these are the address
+func (tr *Transformer) reverseClaimant(record *models.DomainModel, event utils.EventLog, header core.Header) (string, error) {
+	if record.Owner != "" && utils.LabelHash(utils.ReverseLabel(record.Owner)) == record.LabelHash {
+		return record.Owner, nil
+	}
+	label, err := tr.LabelRepository.GetLabel(record.LabelHash)
+	if err != nil {
+		return "", err
+	}
+	if len(label) == 40 && common.IsHexAddress(label) {
+		return label, nil
+	}
+	sender, err := tr.transactionSender(event, header)
+	if err != nil || sender == "" {
+		return "", err
+	}
+	if utils.LabelHash(utils.ReverseLabel(sender)) != record.LabelHash {
+		return "", nil
+	}
 
-			// If no logs are found mark the header checked for all of these eventIDs and continue
-			if len(logs) < 1 {
-				err = tr.HeaderRepository.MarkHeaderCheckedForAll(header.Id, tr.resolverEventIds[addr])
-				if err != nil {
-					return err
-				}
-				continue
-			}
+	return sender, nil
+}
 
-			// Convert logs into batches of log mappings (eventName => []types.Log)
-			convertedLogs, err := tr.Converter.ConvertBatch(logs, resolver.Events, header.Id)
-			if err != nil {
-				return err
-			}
+// Returns the sender of the transaction that emitted the log, syncing the transaction if it hasn't been synced yet
+// Returns an error if the transaction can't be fetched from the eth node
+func (tr *Transformer) transactionSender(event utils.EventLog, header core.Header) (string, error) {
+	var ethLog gethTypes.Log
+	err := json.Unmarshal(event.Raw, &ethLog)
+	if err != nil {
+		return "", err
+	}
+	txHash := ethLog.TxHash.Hex()
+	sender, err := tr.ReverseRepository.GetTransactionSender(header.Id, txHash)
+	if err != nil || sender != "" || tr.ITransactionsSyncer == nil {
+		return sender, err
+	}
+	err = tr.ITransactionsSyncer.SyncTransactions(header.Id, []gethTypes.Log{ethLog})
+	if err != nil {
+		return "", err
+	}
 
-			// Process the resolver log data into our domain records
-			err = tr.processResolverLogs(convertedLogs, header.BlockNumber)
-			if err != nil {
-				return err
-			}
+	return tr.ReverseRepository.GetTransactionSender(header.Id, txHash)
+}
 
-			// Mark this header checked for resolver events
-			err = tr.HeaderRepository.MarkHeaderCheckedForAll(header.Id, tr.resolverEventIds[addr])
-			if err != nil {
-				return err
-			}
-		}
+// Updates the new or retrieved record of a subdomain with the values emitted from a registry NewOwner log
+func (tr *Transformer) processNewOwner(record *models.DomainModel, newOwner types.Log) error {
+	parentHash := newOwner.Values["node"]
+	labelHash := newOwner.Values["label"]
+	record.ParentHash = parentHash
+	record.LabelHash = labelHash
+	record.Owner = newOwner.Values["owner"]
+	if record.FullName != "" {
+		return nil
 	}
+	var err error
+	record.FullName, err = tr.fullName(parentHash, labelHash, record.BlockNumber)
 
-	return nil
+	return err
 }
 
-// Processes Resolver event log data into our domain records
-func (tr *Transformer) processResolverLogs(logs map[string][]types.Log, blockNumber int64) error {
-	// Process resolver AddrChanged logs
-	for _, addrChanged := range logs["AddrChanged"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(addrChanged.Values["node"], blockNumber)
+// Persists the approval or revocation of an operator for all of an owner's nodes on a registry
+func (tr *Transformer) processApprovalForAll(event utils.EventLog, header core.Header) error {
+	return tr.ENSRepository.CreateApproval(models.ApprovalModel{
+		BlockNumber:  header.BlockNumber,
+		HeaderID:     header.Id,
+		RegistryAddr: event.Address,
+		Owner:        event.Values["owner"],
+		Operator:     event.Values["operator"],
+		Approved:     event.Values["approved"] == "true",
+	})
+}
+
+// Walks up the parent chain from the given parent to the root node to build the dotted full name of a node with the given label
+// Labels whose preimage is not yet known are represented by their hash in square brackets; they are filled in once the label is learned
+// Returns an empty string if an ancestor has never been seen, since the name can't be completed
+func (tr *Transformer) fullName(parentHash, labelHash string, blockNumber int64) (string, error) {
+	label, err := tr.LabelRepository.GetLabel(labelHash)
+	if err != nil {
+		return "", err
+	}
+	if label == "" {
+		label = utils.UnknownLabel(labelHash)
+	}
+	labels := []string{label}
+	for node := parentHash; node != utils.RootNode; {
+		parent, err := tr.ENSRepository.GetRecord(node, blockNumber)
 		if err != nil {
-			return err
+			return "", err
 		}
-		// Update with changed address and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.PointsToAddr = addrChanged.Values["a"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
+		// If the ancestor already has its full name we can stop walking
+		if parent.FullName != "" {
+			labels = append(labels, parent.FullName)
+			break
+		}
+		if parent.LabelHash == "" {
+			return "", nil
+		}
+		label, err := tr.LabelRepository.GetLabel(parent.LabelHash)
 		if err != nil {
-			return err
+			return "", err
+		}
+		if label == "" {
+			label = utils.UnknownLabel(parent.LabelHash)
 		}
+		labels = append(labels, label)
+		node = parent.ParentHash
 	}
 
-	// Process resolver NameChanged logs
-	for _, nameChanged := range logs["NameChanged"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(nameChanged.Values["node"], blockNumber)
+	return strings.Join(labels, "."), nil
+}
+
+// Configures contracts for watching Resolvers we found emitted from the Registry's NewResolver events
+// Upgradeable resolvers are detected again once the configured interval has passed since they were last detected
+func (tr *Transformer) configResolvers(blockNumber int64) error {
+	for resolverAddr := range tr.ResolverAddresses {
+		_, configured := tr.Resolvers[resolverAddr]
+		_, invalid := tr.invalidResolvers[resolverAddr]
+		if configured || invalid { // Resolver contract has either already been setup or we already know it is invalid
+			detectedAt, upgradeable := tr.upgradeableResolvers[resolverAddr]
+			interval := tr.ResolverDetection.RedetectInterval
+			if !upgradeable || interval <= 0 || blockNumber-detectedAt < interval {
+				continue
+			}
+		}
+		err := tr.detectResolver(resolverAddr, blockNumber)
 		if err != nil {
 			return err
 		}
-		// Update with changed name and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.Name = nameChanged.Values["name"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
+	}
+
+	return nil
+}
+
+// Detects the abi of a resolver and (re)configures it for watching from the given block, logging the decision
+// If no abi can be detected we skip configuring this resolver and add it to the list of invalid resolvers so we don't keep checking;
+// the domain records that use this resolver will be incomplete, but we can continue to collect their data from the registry
+func (tr *Transformer) detectResolver(resolverAddr string, blockNumber int64) error {
+	detection := tr.detectAbi(resolverAddr, blockNumber)
+	err := tr.ResolverRepository.CreateDetection(detection)
+	if err != nil {
+		return err
+	}
+	if detection.Upgradeable {
+		tr.upgradeableResolvers[resolverAddr] = blockNumber
+	}
+
+	resolver := models.ResolverModel{
+		Address:       resolverAddr,
+		StartingBlock: blockNumber,
+		Abi:           detection.Abi,
+		Valid:         detection.Abi != "",
+		Upgradeable:   detection.Upgradeable,
+		DetectedAt:    blockNumber,
+	}
+	existing, configured := tr.Resolvers[resolverAddr]
+	if configured && existing.Abi == detection.Abi {
+		// Nothing changed, keep watching the resolver from where it started
+		resolver.StartingBlock = existing.StartingBlock
+		return tr.ResolverRepository.CreateResolver(resolver)
+	}
+	if configured {
+		// The resolver has been upgraded; from this block on it is watched for the events of its new abi
+		log.Infof("abi of resolver %s changed at block %d", resolverAddr, blockNumber)
+		delete(tr.Resolvers, resolverAddr)
+		delete(tr.resolverEventIds, resolverAddr)
+		delete(tr.resolverEventFilters, resolverAddr)
+		delete(tr.resolverResumeBlocks, resolverAddr)
+	}
+
+	if resolver.Valid {
+		delete(tr.invalidResolvers, resolverAddr)
+		// Start the resolver contract at the blockheight it was first seen emitted by the Registry from a NewResolver event (or was upgraded at)
+		err = tr.configResolver(resolverAddr, detection.Abi, blockNumber)
 		if err != nil {
 			return err
 		}
+	} else {
+		tr.invalidResolvers[resolverAddr] = true
 	}
 
-	// Process resolver ContentChanged logs
-	for _, contentChanged := range logs["ContentChanged"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(contentChanged.Values["node"], blockNumber)
+	// Persist the resolver so that we can continue watching it after a restart
+	return tr.ResolverRepository.CreateResolver(resolver)
+}
+
+// Detects the abi to watch a resolver with from the interfaces it reports through ERC-165
+// Resolvers that predate ERC-165 are watched with the fallback abi if they are on the allow-list, or their bytecode matches a known resolver's
+// The interfaces, bytecode and storage are read at the given block, so that the detection logged for the block reflects the resolver as it was then
+func (tr *Transformer) detectAbi(resolverAddr string, blockNumber int64) models.ResolverDetectionModel {
+	detection := models.ResolverDetectionModel{
+		Address:     resolverAddr,
+		BlockNumber: blockNumber,
+		Method:      models.DetectionNone,
+		Abi:         tr.InterfaceGetter.GetABI(resolverAddr, blockNumber),
+		Upgradeable: containsAddress(tr.ResolverDetection.UpgradeableResolvers, resolverAddr),
+	}
+	if tr.CodeGetter != nil {
+		code, err := tr.CodeGetter.GetCode(resolverAddr, blockNumber)
 		if err != nil {
-			return err
+			log.Warnf("unable to fetch the bytecode of resolver %s: %v", resolverAddr, err)
+		} else {
+			detection.CodeHash = crypto.Keccak256Hash(code).Hex()
 		}
-		// Update with changed content hash and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.Content = contentChanged.Values["hash"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
+		// EIP-1967 proxies keep the address of their implementation in a fixed slot
+		implementation, err := tr.CodeGetter.GetStorageAt(resolverAddr, tgetter.ImplementationSlot, blockNumber)
 		if err != nil {
-			return err
+			log.Warnf("unable to fetch the implementation slot of resolver %s: %v", resolverAddr, err)
+		} else if implementation != (common.Hash{}) {
+			detection.Upgradeable = true
 		}
 	}
 
-	// Process resolver AbiChanged logs
-	for _, abiChanged := range logs["ABIChanged"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(abiChanged.Values["node"], blockNumber)
+	fallbackAbi := tr.ResolverDetection.FallbackAbi
+	if fallbackAbi == "" {
+		fallbackAbi = tgetter.LegacyResolverABI
+	}
+	switch {
+	case detection.Abi != "":
+		detection.Method = models.DetectionInterface
+	case containsAddress(tr.ResolverDetection.LegacyResolvers, resolverAddr):
+		detection.Method = models.DetectionAllowList
+		detection.Abi = fallbackAbi
+	case detection.CodeHash != "" && containsAddress(tr.ResolverDetection.LegacyCodeHashes, detection.CodeHash):
+		detection.Method = models.DetectionBytecode
+		detection.Abi = fallbackAbi
+	}
+	log.Infof("detected abi of resolver %s at block %d with method %s (upgradeable: %t)", resolverAddr, blockNumber, detection.Method, detection.Upgradeable)
+
+	return detection
+}
+
+// Returns whether the set contains the given hex address or hash, ignoring case
+func containsAddress(set map[string]bool, hex string) bool {
+	for key, ok := range set {
+		if ok && strings.EqualFold(key, hex) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Configures a resolver contract for watching using the provided abi
+func (tr *Transformer) configResolver(resolverAddr, abiStr string, startingBlock int64) error {
+	// Load this abi into the abi parser
+	err := tr.Parser.ParseAbiStr(abiStr)
+	if err != nil {
+		return err
+	}
+
+	// Aggregate info into resolver contract object and store for execution
+	tr.Resolvers[resolverAddr] = &contract.Contract{
+		Name:          "ENS-Resolver",
+		Network:       tr.RegistryConfig.Network,
+		Address:       resolverAddr,
+		Abi:           tr.Parser.Abi(),
+		ParsedAbi:     tr.Parser.ParsedAbi(),
+		StartingBlock: startingBlock,
+		Events:        tr.Parser.GetEvents([]string{}), // Watch all resolver events
+		Methods:       nil,
+		FilterArgs:    map[string]bool{},
+		MethodArgs:    map[string]bool{},
+	}
+
+	// Create checked_headers columns, event ids, and event sigs for this resolver
+	for _, e := range tr.Resolvers[resolverAddr].Events {
+		eventId := ensconfig.ResolverEventId(e.Name, resolverAddr)
+		err := tr.HeaderRepository.AddCheckColumn(eventId)
 		if err != nil {
 			return err
 		}
-		// Update with changed content type and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.ContentType = abiChanged.Values["contentType"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
+		tr.resolverEventIds[resolverAddr] = append(tr.resolverEventIds[resolverAddr], eventId)
+		tr.resolverEventFilters[resolverAddr] = append(tr.resolverEventFilters[resolverAddr], e.Sig())
+	}
+
+	return nil
+}
+
+// Watches the configured Resolvers
+// A resolver that fails is retried from the header it failed at on the next execution, without holding up the other resolvers
+func (tr *Transformer) watchResolvers() error {
+	var watchErr error
+	for addr, resolver := range tr.Resolvers {
+		err := tr.watchResolver(addr, resolver)
+		if err != nil {
+			log.Errorf("unable to watch resolver %s: %v", addr, err)
+			if watchErr == nil {
+				watchErr = err
+			}
+		}
+	}
+
+	return watchErr
+}
+
+// Watches a resolver contract over the headers it has not yet been checked for, up to the registry's index
+func (tr *Transformer) watchResolver(addr string, resolver *contract.Contract) error {
+	// Resolvers reloaded after a restart, or that failed during a previous execution, may need to catch up from before the current range
+	startingBlock := tr.resolverIndex
+	if resumeBlock, ok := tr.resolverResumeBlocks[addr]; ok {
+		startingBlock = resumeBlock
+	}
+
+	// Retrieve unchecked headers for this resolver
+	missingHeaders, err := tr.HeaderRepository.MissingHeadersForAll(startingBlock, tr.registryIndex-1, tr.resolverEventIds[addr])
+	if err != nil {
+		tr.resolverResumeBlocks[addr] = startingBlock
+		return err
+	}
+
+	// Iterate over headers
+	for _, header := range missingHeaders {
+		err = tr.checkResolverHeader(addr, resolver, header)
 		if err != nil {
+			// Retry the resolver from this header on the next execution, even once the registry has moved past it
+			tr.resolverResumeBlocks[addr] = header.BlockNumber
 			return err
 		}
 	}
+	delete(tr.resolverResumeBlocks, addr)
 
-	// Process resolver PubkeyChanged logs
-	for _, pubkeyChanged := range logs["PubkeyChanged"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(pubkeyChanged.Values["node"], blockNumber)
+	return nil
+}
+
+// Collects and processes the event logs a resolver emitted in the given header, then marks the header checked for them
+func (tr *Transformer) checkResolverHeader(addr string, resolver *contract.Contract, header core.Header) error {
+	logs, err := tr.Fetcher.FetchLogs([]string{addr}, tr.resolverEventFilters[addr], header)
+	if err != nil {
+		return err
+	}
+
+	// If logs are found, convert them into batches of log mappings (eventName => []types.Log) and process them into our domain records
+	if len(logs) > 0 {
+		// Update converter with this contract
+		tr.Converter.Update(resolver)
+		convertedLogs, err := tr.Converter.ConvertBatch(logs, resolver.Events, header.Id)
 		if err != nil {
 			return err
 		}
-		// Update with changed pubkey variables and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.PubKeyX = pubkeyChanged.Values["x"]
-		lastRecord.PubKeyY = pubkeyChanged.Values["y"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
+		err = tr.processResolverLogs(convertedLogs, addr, header)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Process resolver TextChanged logs
-	for _, textChanged := range logs["TextChanged"] {
+	// Mark this header checked for resolver events
+	return tr.HeaderRepository.MarkHeaderCheckedForAll(header.Id, tr.resolverEventIds[addr])
+}
+
+// Processes Resolver event log data into our domain records
+func (tr *Transformer) processResolverLogs(logs map[string][]types.Log, resolverAddr string, header core.Header) error {
+	// Apply the logs in the order they were emitted, so that multiple changes to a domain within one block resolve to its final state
+	for _, event := range utils.OrderLogs(logs) {
 		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(textChanged.Values["node"], blockNumber)
+		lastRecord, err := tr.ENSRepository.GetRecord(event.Values["node"], header.BlockNumber)
 		if err != nil {
 			return err
 		}
-		// Update with changed pubkey variables and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.TextKey = textChanged.Values["key"]
-		lastRecord.IndexedTextKey = textChanged.Values["indexedKey"]
+		// Update with the changed values and block height
+		lastRecord.BlockNumber = header.BlockNumber
+		lastRecord.HeaderID = header.Id
+		switch event.Name {
+		case "AddrChanged":
+			lastRecord.PointsToAddr = event.Values["a"]
+			// The legacy addr record is the EIP-2304 address for ether
+			err = tr.createCoinAddress(lastRecord.NameHash, resolverAddr, strconv.FormatUint(coins.Ether, 10), event.Values["a"], header)
+			if err != nil {
+				return err
+			}
+		case "NameChanged":
+			lastRecord.Name = event.Values["name"]
+		case "ContentChanged":
+			lastRecord.Content = event.Values["hash"]
+			lastRecord.EffectiveContentProtocol, lastRecord.EffectiveContentURI = decodeContent(contenthash.DecodeSwarmHash, lastRecord.NameHash, event.Values["hash"])
+		case "ABIChanged":
+			lastRecord.ContentType = event.Values["contentType"]
+		case "PubkeyChanged":
+			lastRecord.PubKeyX = event.Values["x"]
+			lastRecord.PubKeyY = event.Values["y"]
+		case "TextChanged":
+			// The event only emits the key, so fetch the value it was set to from the resolver at this blockheight
+			// If the resolver gives no value (it reverts, or the eth node isn't an archive node) the key is kept with an empty value
+			// If the eth node can't be reached the header is left unchecked, so that the value is fetched when it is retried
+			value, err := tr.TextGetter.GetText(resolverAddr, lastRecord.NameHash, event.Values["key"], header.BlockNumber)
+			if err == tgetter.ErrNoTextValue {
+				log.Warnf("no text value for key %s on node %s from resolver %s at block %d, keeping it empty", event.Values["key"], lastRecord.NameHash, resolverAddr, header.BlockNumber)
+			} else if err != nil {
+				return fmt.Errorf("unable to fetch text value for key %s on node %s at block %d: %v", event.Values["key"], lastRecord.NameHash, header.BlockNumber, err)
+			}
+			lastRecord.TextKey = event.Values["key"]
+			lastRecord.IndexedTextKey = event.Values["indexedKey"]
+			lastRecord.TextValue = value
+			// Keep a history of every key set on the node, since the domain record only holds the most recently changed key
+			err = tr.ENSRepository.CreateTextRecord(models.TextRecordModel{
+				HeaderID:     header.Id,
+				BlockNumber:  header.BlockNumber,
+				NameHash:     lastRecord.NameHash,
+				Key:          event.Values["key"],
+				Value:        value,
+				ResolverAddr: resolverAddr,
+			})
+			if err != nil {
+				return err
+			}
+		case "AddressChanged":
+			// Multicoin addresses are kept in their own table, the domain record is left unchanged
+			err = tr.createCoinAddress(lastRecord.NameHash, resolverAddr, event.Values["coinType"], event.Values["newAddress"], header)
+			if err != nil {
+				return err
+			}
+			continue
+		case "MultihashChanged":
+			lastRecord.Multihash = event.Values["hash"]
+			lastRecord.EffectiveContentProtocol, lastRecord.EffectiveContentURI = decodeContent(contenthash.DecodeMultihash, lastRecord.NameHash, event.Values["hash"])
+		case "ContenthashChanged":
+			lastRecord.Contenthash = event.Values["hash"]
+			lastRecord.ContenthashProtocol, lastRecord.ContenthashURI = decodeContent(contenthash.Decode, lastRecord.NameHash, event.Values["hash"])
+			lastRecord.EffectiveContentProtocol, lastRecord.EffectiveContentURI = lastRecord.ContenthashProtocol, lastRecord.ContenthashURI
+		case "DNSRecordChanged", "DNSRecordDeleted", "DNSZonehashChanged":
+			// DNS records are kept in their own tables, the domain record is left unchanged
+			err = tr.createDNSEvent(event, resolverAddr, header)
+			if err != nil {
+				return err
+			}
+			continue
+		case "VersionChanged":
+			// A new record version wipes every record the resolver holds for the node, including its DNS zone
+			err = tr.clearResolverRecords(lastRecord, resolverAddr, header)
+			if err != nil {
+				return err
+			}
+			err = tr.createDNSEvent(event, resolverAddr, header)
+			if err != nil {
+				return err
+			}
+		default:
+			continue
+		}
 		// Persist new record
 		err = tr.ENSRepository.CreateRecord(*lastRecord)
 		if err != nil {
 			return err
 		}
+		// Names set on nodes (e.g. reverse records) give us the preimages of their labels
+		// These are persisted after the record, since learning a label rewrites the full names of existing records
+		if event.Name == "NameChanged" {
+			err = tr.createLabels(event.Values["name"], "NameChanged")
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	// Process resolver MultihashChanged logs
-	for _, multihashChanged := range logs["MultihashChanged"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(multihashChanged.Values["node"], blockNumber)
+	return nil
+}
+
+// Clears the fields of the record that are set through its resolver, and the text records and coin addresses set on the node
+// Keys and coin types whose values are cleared are kept with empty values, the same as if they had been set to empty values
+func (tr *Transformer) clearResolverRecords(record *models.DomainModel, resolverAddr string, header core.Header) error {
+	record.PointsToAddr = ""
+	record.Name = ""
+	record.Content = ""
+	record.ContentType = ""
+	record.PubKeyX = ""
+	record.PubKeyY = ""
+	record.TextKey = ""
+	record.IndexedTextKey = ""
+	record.TextValue = ""
+	record.Multihash = ""
+	record.Contenthash = ""
+	record.ContenthashProtocol = ""
+	record.ContenthashURI = ""
+	record.EffectiveContentProtocol = ""
+	record.EffectiveContentURI = ""
+
+	textRecords, err := tr.ENSRepository.GetTextRecords(record.NameHash, header.BlockNumber)
+	if err != nil {
+		return err
+	}
+	for _, textRecord := range textRecords {
+		if textRecord.Value == "" {
+			continue
+		}
+		err = tr.ENSRepository.CreateTextRecord(models.TextRecordModel{
+			HeaderID:     header.Id,
+			BlockNumber:  header.BlockNumber,
+			NameHash:     record.NameHash,
+			Key:          textRecord.Key,
+			ResolverAddr: resolverAddr,
+		})
 		if err != nil {
 			return err
 		}
-		// Update with changed pubkey variables and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.Multihash = multihashChanged.Values["hash"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
+	}
+
+	coinAddresses, err := tr.ENSRepository.GetCoinAddresses(record.NameHash, header.BlockNumber)
+	if err != nil {
+		return err
+	}
+	for _, coinAddress := range coinAddresses {
+		if coinAddress.Address == "" {
+			continue
+		}
+		err = tr.ENSRepository.CreateCoinAddress(models.CoinAddressModel{
+			BlockNumber:  header.BlockNumber,
+			HeaderID:     header.Id,
+			NameHash:     record.NameHash,
+			CoinType:     coinAddress.CoinType,
+			RawAddress:   "0x",
+			ResolverAddr: resolverAddr,
+		})
 		if err != nil {
 			return err
 		}
 	}
 
-	// Process resolver ContenthashChanged logs
-	for _, contenthashChanged := range logs["ContenthashChanged"] {
-		// Get most recent state
-		lastRecord, err := tr.ENSRepository.GetRecord(contenthashChanged.Values["node"], blockNumber)
+	return nil
+}
+
+// Converts a resolver log with the converter of its event transformer and persists it into the event's table
+func (tr *Transformer) createDNSEvent(event utils.EventLog, resolverAddr string, header core.Header) error {
+	var ethLog gethTypes.Log
+	err := json.Unmarshal(event.Raw, &ethLog)
+	if err != nil {
+		return err
+	}
+	converter := dnsEventConverters[event.Name]
+	entities, err := converter.ToEntities(tr.Resolvers[resolverAddr].Abi, []gethTypes.Log{ethLog})
+	if err != nil {
+		return err
+	}
+	eventModels, err := converter.ToModels(entities)
+	if err != nil {
+		return err
+	}
+
+	return tr.DNSRepository.CreateDNSEvents(header.Id, event.Name, eventModels)
+}
+
+// Persists the address a node resolves to for a coin type, decoded into the coin's native format
+// Addresses that can't be decoded (unknown coin types, non-standard scripts) are kept as hex
+func (tr *Transformer) createCoinAddress(nameHash, resolverAddr, coinType, rawAddress string, header core.Header) error {
+	address := rawAddress
+	decodedType, typeErr := strconv.ParseUint(coinType, 10, 64)
+	rawBytes, addrErr := hexutil.Decode(rawAddress)
+	if typeErr == nil && addrErr == nil {
+		encoded, err := coins.EncodeAddress(decodedType, rawBytes)
 		if err != nil {
-			return err
+			log.Warnf("unable to encode address %s for coin type %s on node %s: %v", rawAddress, coinType, nameHash, err)
+		} else {
+			address = encoded
 		}
-		// Update with changed pubkey variables and block height
-		lastRecord.BlockNumber = blockNumber
-		lastRecord.Contenthash = contenthashChanged.Values["hash"]
-		// Persist new record
-		err = tr.ENSRepository.CreateRecord(*lastRecord)
+	}
+
+	return tr.ENSRepository.CreateCoinAddress(models.CoinAddressModel{
+		BlockNumber:  header.BlockNumber,
+		HeaderID:     header.Id,
+		NameHash:     nameHash,
+		CoinType:     coinType,
+		Address:      address,
+		RawAddress:   rawAddress,
+		ResolverAddr: resolverAddr,
+	})
+}
+
+// Decodes a hex encoded content, multihash or contenthash value into its protocol and URI with the given decoder
+// Values that can't be decoded are left with an empty protocol and URI, the raw value is still kept on the record
+func decodeContent(decode func([]byte) (string, string, error), nameHash, hash string) (string, string) {
+	raw, err := hexutil.Decode(hash)
+	if err != nil {
+		log.Warnf("unable to decode content %s on node %s: %v", hash, nameHash, err)
+		return "", ""
+	}
+	protocol, uri, err := decode(raw)
+	if err != nil {
+		log.Warnf("unable to decode content %s on node %s: %v", hash, nameHash, err)
+		return "", ""
+	}
+
+	return protocol, uri
+}
+
+// Persists every label of a dotted name into the label table
+func (tr *Transformer) createLabels(name, source string) error {
+	for _, label := range utils.SplitName(name) {
+		err := tr.LabelRepository.CreateLabel(models.LabelModel{
+			LabelHash: utils.LabelHash(label),
+			Label:     label,
+			Source:    source,
+		})
 		if err != nil {
 			return err
 		}
@@ -17,11 +17,15 @@
 package domain_records_test
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/light/converter"
+	"github.com/vulcanize/vulcanizedb/libraries/shared/transactions"
 	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/light/fetcher"
 	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/light/repository"
 	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/light/retriever"
@@ -36,9 +40,13 @@ import (
 
 	transformer "github.com/vulcanize/ens_transformers/transformers/domain_records"
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/config"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/converter"
+	tgetter "github.com/vulcanize/ens_transformers/transformers/domain_records/getter"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
 	rep "github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers"
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/test_helpers/mocks"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
 )
 
 var mockLogs = []types.Log{
@@ -87,6 +95,361 @@ var mockLogs = []types.Log{
 	},
 }
 
+// Registry logs emitted in one block, returned out of order: a Transfer of the subdomain that is followed by a NewOwner of it
+var interleavedRegistryLogs = []types.Log{
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash02"),
+		TxIndex:     2,
+		Index:       4,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash("0xce0457fe73731f824cc272376169235128c118b49d344817417c6d108d155e82"),
+			common.HexToHash("0xd1115c02622703bb9236a0e6609cb250a874e903494bd9071c25078f4033dac1"),
+			common.HexToHash("0xadc756803e4eb4ccfb136b73d5f72e3dc0d452d30ae1f4bc82af394c73ce7115"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000fFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash02"),
+		TxIndex:     2,
+		Index:       3,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.TransferSignature),
+			common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000a54aef7fa503e75a03b262a4cd73037c1774735d").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash01"),
+		TxIndex:     1,
+		Index:       1,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash("0xce0457fe73731f824cc272376169235128c118b49d344817417c6d108d155e82"),
+			common.HexToHash("0xd1115c02622703bb9236a0e6609cb250a874e903494bd9071c25078f4033dac1"),
+			common.HexToHash("0xadc756803e4eb4ccfb136b73d5f72e3dc0d452d30ae1f4bc82af394c73ce7115"),
+		},
+		Data: common.HexToHash("0x00000000000000000000000042032c22c510ad0698f16be9b99640efdeb02832").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash01"),
+		TxIndex:     1,
+		Index:       2,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash("0x335721b01866dc23fbee8b6b2c7b1e14d6f05c28cd35a2c934239f94095602a0"),
+			common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000d3ddccdd3b25a8a7423b5bee360a42146eb4baf3").Bytes(),
+	},
+}
+
+// Resolver logs emitted in the same block, returned out of order: two AddrChanged events for the subdomain
+var interleavedResolverLogs = []types.Log{
+	{
+		Address:     common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash03"),
+		TxIndex:     3,
+		Index:       6,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash("0x52d7d861f09ab3d26239d492e8968629f95e9e318cf0b73bfddc441522a15fd2"),
+			common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000fFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash03"),
+		TxIndex:     3,
+		Index:       5,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash("0x52d7d861f09ab3d26239d492e8968629f95e9e318cf0b73bfddc441522a15fd2"),
+			common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000a54aef7fa503e75a03b262a4cd73037c1774735d").Bytes(),
+	},
+}
+
+// Resolver logs setting two text keys on the subdomain in the same block
+var textChangedLogs = []types.Log{
+	{
+		Address:     common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash03"),
+		TxIndex:     3,
+		Index:       1,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.TextChangedSignature),
+			common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+		},
+		Data: test_data.EthTextChangedLog.Data, // key "issuerName"
+	},
+	{
+		Address:     common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash03"),
+		TxIndex:     3,
+		Index:       2,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.TextChangedSignature),
+			common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+		},
+		Data: hexutil.MustDecode("0x000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000006617661746172000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000066176617461720000000000000000000000000000000000000000000000000000"), // key "avatar"
+	},
+}
+
+// Registry and resolver logs that point the parent of the subdomain at a second resolver and set its address on it
+var secondResolverLogs = []types.Log{
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash04"),
+		TxIndex:     4,
+		Index:       9,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NewResolverSignature),
+			common.HexToHash("0xd1115c02622703bb9236a0e6609cb250a874e903494bd9071c25078f4033dac1"),
+		},
+		Data: common.HexToHash("0x0000000000000000000000001da022710dF5002339274AaDEe8D58218e9D6AB5").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress("0x1da022710dF5002339274AaDEe8D58218e9D6AB5"),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash05"),
+		TxIndex:     5,
+		Index:       10,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.AddrChangedSignature),
+			common.HexToHash("0xd1115c02622703bb9236a0e6609cb250a874e903494bd9071c25078f4033dac1"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000fFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5").Bytes(),
+	},
+}
+
+// Resolver log bumping the record version of the subdomain after its address and text records are set in the same block
+var versionChangedLog = types.Log{
+	Address:     common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"),
+	BlockNumber: 6885695,
+	BlockHash:   common.HexToHash("0xMockBlockHash01"),
+	TxHash:      common.HexToHash("0xMockTxHash03"),
+	TxIndex:     3,
+	Index:       7,
+	Removed:     false,
+	Topics: []common.Hash{
+		common.HexToHash(test_data.VersionChangedSignature),
+		common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+	},
+	Data: test_data.EthVersionChangedLog.Data,
+}
+
+// Resolver log that sets the A record of a.example.com. in the DNS zone of vitalik.eth
+var dnsRecordChangedLog = types.Log{
+	Address:     common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"),
+	BlockNumber: 6885695,
+	BlockHash:   common.HexToHash("0xMockBlockHash01"),
+	TxHash:      common.HexToHash("0xMockTxHash03"),
+	TxIndex:     3,
+	Index:       8,
+	Removed:     false,
+	Topics: []common.Hash{
+		common.HexToHash(test_data.DNSRecordChangedSignature),
+		common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+	},
+	Data: test_data.EthDNSRecordChangedLog.Data,
+}
+
+// Registry and resolver logs that create eth and vitalik.eth, point vitalik.eth at the resolver, and set its name on it
+var nameChangedLogs = []types.Log{
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash01"),
+		TxIndex:     1,
+		Index:       1,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NewOwnerSignature),
+			common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"),
+			common.HexToHash("0x4f5b812789fc606be1b3b16908db13fc7a9adf7ca72641f84d75b47069d3d7f0"), // keccak256("eth")
+		},
+		Data: common.HexToHash("0x00000000000000000000000042032c22c510ad0698f16be9b99640efdeb02832").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash02"),
+		TxIndex:     2,
+		Index:       2,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NewOwnerSignature),
+			common.HexToHash("0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"), // namehash("eth")
+			common.HexToHash("0xaf2caa1c2ca1d027f1ac823b529d0a67cd144264b2789fa2ea4d63a67c7103cc"), // keccak256("vitalik")
+		},
+		Data: common.HexToHash("0x000000000000000000000000fFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash02"),
+		TxIndex:     2,
+		Index:       3,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NewResolverSignature),
+			common.HexToHash("0xee6c4522aab0003e8d14cd40a6af439055fd2577951148c14b6cea9a53475835"), // namehash("vitalik.eth")
+		},
+		Data: common.HexToHash("0x000000000000000000000000d3ddccdd3b25a8a7423b5bee360a42146eb4baf3").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash03"),
+		TxIndex:     3,
+		Index:       4,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NameChangedSignature),
+			common.HexToHash("0xee6c4522aab0003e8d14cd40a6af439055fd2577951148c14b6cea9a53475835"),
+		},
+		Data: hexutil.MustDecode("0x0000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000b766974616c696b2e657468000000000000000000000000000000000000000000"), // name "vitalik.eth"
+	},
+}
+
+// Logs from both registries: the subdomain is created and transferred on the old registry, then migrated to the new registry
+// in the next block, after which the old registry's events for it are ignored; its new owner also approves an operator
+// The mock fetcher returns every log for every header, so the fallback registry is started at the second block
+var fallbackRegistryLogs = []types.Log{
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash01"),
+		TxIndex:     1,
+		Index:       1,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NewOwnerSignature),
+			common.HexToHash("0xd1115c02622703bb9236a0e6609cb250a874e903494bd9071c25078f4033dac1"),
+			common.HexToHash("0xadc756803e4eb4ccfb136b73d5f72e3dc0d452d30ae1f4bc82af394c73ce7115"),
+		},
+		Data: common.HexToHash("0x00000000000000000000000042032c22c510ad0698f16be9b99640efdeb02832").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash02"),
+		TxIndex:     2,
+		Index:       2,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.TransferSignature),
+			common.HexToHash("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000a54aef7fa503e75a03b262a4cd73037c1774735d").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress(config.RegistryWithFallbackAddress),
+		BlockNumber: 6885696,
+		BlockHash:   common.HexToHash("0xMockBlockHash02"),
+		TxHash:      common.HexToHash("0xMockTxHash03"),
+		TxIndex:     0,
+		Index:       0,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NewOwnerSignature),
+			common.HexToHash("0xd1115c02622703bb9236a0e6609cb250a874e903494bd9071c25078f4033dac1"),
+			common.HexToHash("0xadc756803e4eb4ccfb136b73d5f72e3dc0d452d30ae1f4bc82af394c73ce7115"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000fFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5").Bytes(),
+	},
+	{
+		Address:     common.HexToAddress(config.RegistryWithFallbackAddress),
+		BlockNumber: 6885696,
+		BlockHash:   common.HexToHash("0xMockBlockHash02"),
+		TxHash:      common.HexToHash("0xMockTxHash04"),
+		TxIndex:     3,
+		Index:       3,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash("0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31"), // ApprovalForAll(address,address,bool)
+			common.HexToHash("0x000000000000000000000000fFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"),
+			common.HexToHash("0x00000000000000000000000042032c22c510ad0698f16be9b99640efdeb02832"),
+		},
+		Data: common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001").Bytes(),
+	},
+}
+
+// A registry log that sets an address as the owner of its own reverse node under addr.reverse
+var reverseNewOwnerLogs = []types.Log{
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash01"),
+		TxIndex:     1,
+		Index:       1,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NewOwnerSignature),
+			common.HexToHash("0x91d1777781884d03a6757a803996e38de2a42967fb37eeaca72729271025a9e2"),
+			common.HexToHash("0x3ea49016cc0ff1f46bdc9804729ade1f16c1825fd0f97e9dee064c316e958082"),
+		},
+		Data: common.HexToHash("0x000000000000000000000000fFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5").Bytes(),
+	},
+}
+
+// A registry log from before 2023, in which the ReverseRegistrar claims the reverse node of the address that called setName and makes itself its owner
+var registrarReverseNewOwnerLogs = []types.Log{
+	{
+		Address:     common.HexToAddress(constants.EnsContractAddress),
+		BlockNumber: 6885695,
+		BlockHash:   common.HexToHash("0xMockBlockHash01"),
+		TxHash:      common.HexToHash("0xMockTxHash01"),
+		TxIndex:     1,
+		Index:       1,
+		Removed:     false,
+		Topics: []common.Hash{
+			common.HexToHash(test_data.NewOwnerSignature),
+			common.HexToHash("0x91d1777781884d03a6757a803996e38de2a42967fb37eeaca72729271025a9e2"),
+			common.HexToHash("0x3ea49016cc0ff1f46bdc9804729ade1f16c1825fd0f97e9dee064c316e958082"),
+		},
+		Data: common.HexToHash("0x0000000000000000000000009062C0A6Dbd6108336BcBe4593a3D1cE05512069").Bytes(), // ReverseRegistrar
+	},
+}
+
 var _ = Describe("Transformer", func() {
 	var db *postgres.DB
 	var blockChain core.BlockChain
@@ -107,15 +470,18 @@ var _ = Describe("Transformer", func() {
 			con := config.MainnetENSConfig
 			con.StartingBlocks[constants.EnsContractAddress] = 6885695
 			t := transformer.Transformer{
-				RegistryConfig:   con,
-				Fetcher:          fetcher.NewFetcher(blockChain),
-				Parser:           parser.NewParser(""),
-				HeaderRepository: repository.NewHeaderRepository(db),
-				Converter:        converter.Converter{},
-				Resolvers:        map[string]*contract.Contract{},
-				ENSRepository:    rep.NewENSRepository(db),
-				InterfaceGetter:  getter.NewInterfaceGetter(blockChain),
-				BlockRetriever:   retriever.NewBlockRetriever(db),
+				RegistryConfig:     con,
+				Fetcher:            fetcher.NewFetcher(blockChain),
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
 			}
 
 			err := t.Init()
@@ -127,6 +493,51 @@ var _ = Describe("Transformer", func() {
 			Expect(registryContract.Abi).To(Equal(constants.ENSAbiString))
 			Expect(registryContract.Name).To(Equal("ENS-Registry"))
 		})
+
+		It("Reloads the resolvers persisted before a restart", func() {
+			resolverRepository := rep.NewResolverRepository(db)
+			err := resolverRepository.CreateResolver(models.ResolverModel{
+				Address:       "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3",
+				StartingBlock: 6885696,
+				Abi:           "[" + constants.AddrChangeInterface + "]",
+				Valid:         true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			err = resolverRepository.CreateResolver(models.ResolverModel{
+				Address:       "0x1da022710dF5002339274AaDEe8D58218e9D6AB5",
+				StartingBlock: 6885696,
+				Valid:         false,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            fetcher.NewFetcher(blockChain),
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: resolverRepository,
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(t.ResolverAddresses).To(HaveKey("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"))
+			Expect(t.ResolverAddresses).To(HaveKey("0x1da022710dF5002339274AaDEe8D58218e9D6AB5"))
+			Expect(t.Resolvers).ToNot(HaveKey("0x1da022710dF5002339274AaDEe8D58218e9D6AB5"))
+			resolverContract, ok := t.Resolvers["0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"]
+			Expect(ok).To(Equal(true))
+			Expect(resolverContract.StartingBlock).To(Equal(int64(6885696)))
+			Expect(resolverContract.Events).To(HaveKey("AddrChanged"))
+		})
 	})
 
 	Describe("Execute", func() {
@@ -149,15 +560,18 @@ var _ = Describe("Transformer", func() {
 			f := mocks.NewMockFetcher(blockChain)
 			f.Logs = mockLogs
 			t := transformer.Transformer{
-				RegistryConfig:   con,
-				Fetcher:          f,
-				Parser:           parser.NewParser(""),
-				HeaderRepository: repository.NewHeaderRepository(db),
-				Converter:        converter.Converter{},
-				Resolvers:        map[string]*contract.Contract{},
-				ENSRepository:    rep.NewENSRepository(db),
-				InterfaceGetter:  getter.NewInterfaceGetter(blockChain),
-				BlockRetriever:   retriever.NewBlockRetriever(db),
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
 			}
 
 			err = t.Init()
@@ -173,6 +587,743 @@ var _ = Describe("Transformer", func() {
 			Expect(record.Owner).To(Equal("0x42032C22C510AD0698f16bE9b99640eFDEB02832"))
 			Expect(record.ResolverAddr).To(Equal("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"))
 			Expect(record.PointsToAddr).To(Equal("0xa54AEF7fA503E75a03b262A4Cd73037C1774735D"))
+
+			resolvers, err := t.ResolverRepository.GetResolvers()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(resolvers)).To(Equal(1))
+			Expect(resolvers[0].Address).To(Equal("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"))
+			Expect(resolvers[0].StartingBlock).To(Equal(int64(6885696)))
+			Expect(resolvers[0].Valid).To(Equal(true))
+		})
+
+		It("With Mock Fetcher: applies interleaved events in the order they were emitted within a block", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append(interleavedRegistryLogs, interleavedResolverLogs...)
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.BlockNumber).To(Equal(int64(6885695)))
+			// The NewOwner that follows the Transfer determines the owner
+			Expect(record.Owner).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+			Expect(record.ResolverAddr).To(Equal("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"))
+			// The AddrChanged with the highest log index determines the address
+			Expect(record.PointsToAddr).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+		})
+
+		It("With Mock Fetcher: keeps a text record, with its fetched value, for every key set on a domain", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append(interleavedRegistryLogs, textChangedLogs...)
+			textBlockChain := mocks.NewMockBlockChain()
+			textBlockChain.StringValues["avatar"] = "https://example.com/avatar.png"
+			textBlockChain.StringValues["issuerName"] = "Vulcanize"
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(textBlockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.TextKey).To(Equal("avatar"))
+			Expect(record.TextValue).To(Equal("https://example.com/avatar.png"))
+			textRecords, err := t.ENSRepository.GetTextRecords("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(textRecords)).To(Equal(2))
+			Expect(textRecords[0].Key).To(Equal("avatar"))
+			Expect(textRecords[0].Value).To(Equal("https://example.com/avatar.png"))
+			Expect(textRecords[0].ResolverAddr).To(Equal("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"))
+			Expect(textRecords[1].Key).To(Equal("issuerName"))
+			Expect(textRecords[1].Value).To(Equal("Vulcanize"))
+			Expect(textRecords[1].BlockNumber).To(Equal(int64(6885695)))
+		})
+
+		It("With Mock Fetcher: leaves the header unchecked if the eth node can't be reached for a text value, and fetches it when retried", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append(append(append([]types.Log{}, interleavedRegistryLogs...), textChangedLogs...), secondResolverLogs...)
+			resolverBlockChain := mocks.NewMockBlockChain()
+			resolverBlockChain.SupportedInterfaces[constants.MetaSig.Bytes()] = true
+			resolverBlockChain.SupportedInterfaces[constants.AddrChangeSig.Bytes()] = true
+			resolverBlockChain.SupportedInterfaces[constants.TextChangeSig.Bytes()] = true
+			textBlockChain := mocks.NewMockBlockChain()
+			textBlockChain.StringValues["avatar"] = "https://example.com/avatar.png"
+			textBlockChain.SetFetchContractDataErr(errors.New("connection refused"))
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    tgetter.NewInterfaceGetter(resolverBlockChain),
+				TextGetter:         tgetter.NewTextGetter(textBlockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).To(HaveOccurred())
+			textRecords, err := t.ENSRepository.GetTextRecords("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(textRecords).To(BeEmpty())
+			// The other resolver is still watched over the same headers
+			record, err := t.ENSRepository.GetRecord("0xd1115c02622703bb9236a0e6609cb250a874e903494bd9071c25078f4033dac1", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.PointsToAddr).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+
+			textBlockChain.SetFetchContractDataErr(nil)
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			textRecords, err = t.ENSRepository.GetTextRecords("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(textRecords)).To(Equal(2))
+			Expect(textRecords[0].Key).To(Equal("avatar"))
+			Expect(textRecords[0].Value).To(Equal("https://example.com/avatar.png"))
+		})
+
+		It("With Mock Fetcher: keeps keys with empty values and checks the header if the resolver reverts on text()", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append(append([]types.Log{}, interleavedRegistryLogs...), textChangedLogs...)
+			resolverBlockChain := mocks.NewMockBlockChain()
+			resolverBlockChain.SupportedInterfaces[constants.MetaSig.Bytes()] = true
+			resolverBlockChain.SupportedInterfaces[constants.TextChangeSig.Bytes()] = true
+			textBlockChain := mocks.NewMockBlockChain()
+			textBlockChain.SetFetchContractDataErr(mocks.RpcError{Code: -32000, Message: "execution reverted"})
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    tgetter.NewInterfaceGetter(resolverBlockChain),
+				TextGetter:         tgetter.NewTextGetter(textBlockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.TextKey).To(Equal("avatar"))
+			Expect(record.TextValue).To(Equal(""))
+			textRecords, err := t.ENSRepository.GetTextRecords("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(textRecords)).To(Equal(2))
+			Expect(textRecords[0].Key).To(Equal("avatar"))
+			Expect(textRecords[0].Value).To(Equal(""))
+			Expect(textRecords[1].Key).To(Equal("issuerName"))
+			Expect(textRecords[1].Value).To(Equal(""))
+
+			// The header was checked, so the values aren't fetched again
+			textBlockChain.SetFetchContractDataErr(nil)
+			textBlockChain.StringValues["avatar"] = "https://example.com/avatar.png"
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			textRecords, err = t.ENSRepository.GetTextRecords("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(textRecords[0].Value).To(Equal(""))
+		})
+
+		It("With Mock Fetcher: clears the records set through the resolver when the record version changes", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append(append(append([]types.Log{}, interleavedRegistryLogs...), interleavedResolverLogs...), textChangedLogs...)
+			f.Logs = append(f.Logs, versionChangedLog)
+			resolverBlockChain := mocks.NewMockBlockChain()
+			resolverBlockChain.StringValues["avatar"] = "https://example.com/avatar.png"
+			resolverBlockChain.StringValues["issuerName"] = "Vulcanize"
+			resolverBlockChain.SupportedInterfaces[constants.MetaSig.Bytes()] = true
+			resolverBlockChain.SupportedInterfaces[constants.AddrChangeSig.Bytes()] = true
+			resolverBlockChain.SupportedInterfaces[constants.TextChangeSig.Bytes()] = true
+			var versionsId [4]byte
+			copy(versionsId[:], hexutil.MustDecode(tgetter.RecordVersionsInterfaceId))
+			resolverBlockChain.SupportedInterfaces[versionsId] = true
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    tgetter.NewInterfaceGetter(resolverBlockChain),
+				TextGetter:         tgetter.NewTextGetter(resolverBlockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			// Registry state survives the version change
+			Expect(record.Owner).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+			Expect(record.ResolverAddr).To(Equal("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"))
+			Expect(record.PointsToAddr).To(Equal(""))
+			Expect(record.TextKey).To(Equal(""))
+			Expect(record.TextValue).To(Equal(""))
+			textRecords, err := t.ENSRepository.GetTextRecords("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(textRecords)).To(Equal(2))
+			Expect(textRecords[0].Value).To(Equal(""))
+			Expect(textRecords[1].Value).To(Equal(""))
+			coinAddresses, err := t.ENSRepository.GetCoinAddresses("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(coinAddresses)).To(Equal(1))
+			Expect(coinAddresses[0].Address).To(Equal(""))
+		})
+
+		It("With Mock Fetcher: persists the DNS records set on the resolvers it discovers into the DNS zones", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append(append([]types.Log{}, interleavedRegistryLogs...), interleavedResolverLogs...)
+			f.Logs = append(f.Logs, dnsRecordChangedLog)
+			resolverBlockChain := mocks.NewMockBlockChain()
+			resolverBlockChain.SupportedInterfaces[constants.MetaSig.Bytes()] = true
+			resolverBlockChain.SupportedInterfaces[constants.AddrChangeSig.Bytes()] = true
+			var dnsRecordId [4]byte
+			copy(dnsRecordId[:], hexutil.MustDecode(tgetter.DNSRecordInterfaceId))
+			resolverBlockChain.SupportedInterfaces[dnsRecordId] = true
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				DNSRepository:      rep.NewDNSRepository(db),
+				InterfaceGetter:    tgetter.NewInterfaceGetter(resolverBlockChain),
+				TextGetter:         tgetter.NewTextGetter(resolverBlockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			zone, err := t.DNSRepository.GetZone("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3", "0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zone).To(Equal([]models.DNSRecordModel{{
+				Resolver:    "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3",
+				Node:        "0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91",
+				Name:        "a.example.com.",
+				Type:        1,
+				Class:       1,
+				TTL:         3600,
+				Rdata:       "0x01020304",
+				BlockNumber: 6885695,
+			}}))
+			// The domain record is left as the other resolver logs set it
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.PointsToAddr).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+		})
+
+		It("With Mock Fetcher: watches resolvers without ERC-165 support whose bytecode is known with the fallback abi", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append(append([]types.Log{}, interleavedRegistryLogs...), interleavedResolverLogs...)
+			resolverCode := []byte{0x60, 0x60, 0x60, 0x40, 0x52}
+			rpcClient := mocks.NewMockRpcClient()
+			rpcClient.Code[common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3")] = resolverCode
+			detection := config.MainnetResolverDetection
+			detection.LegacyCodeHashes = map[string]bool{crypto.Keccak256Hash(resolverCode).Hex(): true}
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				ResolverDetection:  detection,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    tgetter.NewInterfaceGetter(mocks.NewMockBlockChain()),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				CodeGetter:         tgetter.NewCodeGetter(rpcClient),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.PointsToAddr).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+			detections, err := t.ResolverRepository.GetDetections("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(detections)).To(Equal(1))
+			Expect(detections[0].Method).To(Equal(models.DetectionBytecode))
+			Expect(detections[0].CodeHash).To(Equal(crypto.Keccak256Hash(resolverCode).Hex()))
+			Expect(detections[0].Abi).To(Equal(tgetter.LegacyResolverABI))
+			// The bytecode is read at the block the detection is logged for
+			Expect(detections[0].BlockNumber).To(Equal(int64(6885695)))
+			Expect(rpcClient.PassedBlockNumber).To(Equal(hexutil.EncodeUint64(6885695)))
+		})
+
+		It("With Mock Fetcher: detects the abi of upgradeable resolvers again once the interval has passed", func() {
+			header1, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			header2, err := blockChain.GetHeaderByNumber(6885697)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header1)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append([]types.Log{}, interleavedRegistryLogs...)
+			resolverBlockChain := mocks.NewMockBlockChain()
+			rpcClient := mocks.NewMockRpcClient()
+			// The resolver is an EIP-1967 proxy whose implementation doesn't support any resolver interfaces yet
+			rpcClient.Storage[common.HexToAddress("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3")] = map[common.Hash]common.Hash{
+				tgetter.ImplementationSlot: common.HexToHash("0x4976fb03C32e5B8cfe2b6cCB31c09Ba78EBaBa41"),
+			}
+			detection := config.MainnetResolverDetection
+			detection.RedetectInterval = 2
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				ResolverDetection:  detection,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    tgetter.NewInterfaceGetter(resolverBlockChain),
+				TextGetter:         tgetter.NewTextGetter(resolverBlockChain),
+				CodeGetter:         tgetter.NewCodeGetter(rpcClient),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(t.Resolvers).ToNot(HaveKey("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"))
+
+			// The proxy is upgraded to an implementation that supports the addr interface
+			resolverBlockChain.SupportedInterfaces[constants.MetaSig.Bytes()] = true
+			resolverBlockChain.SupportedInterfaces[constants.AddrChangeSig.Bytes()] = true
+			f.Logs = append(f.Logs, interleavedResolverLogs...)
+			_, err = headerRepository.CreateOrUpdateHeader(header2)
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+
+			resolverContract, ok := t.Resolvers["0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"]
+			Expect(ok).To(Equal(true))
+			Expect(resolverContract.StartingBlock).To(Equal(int64(6885697)))
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885697)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.PointsToAddr).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+			detections, err := t.ResolverRepository.GetDetections("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(detections)).To(Equal(2))
+			Expect(detections[0].Method).To(Equal(models.DetectionNone))
+			Expect(detections[0].Upgradeable).To(Equal(true))
+			Expect(detections[1].Method).To(Equal(models.DetectionInterface))
+			Expect(detections[1].BlockNumber).To(Equal(int64(6885697)))
+			resolvers, err := t.ResolverRepository.GetResolvers()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolvers).To(ContainElement(models.ResolverModel{
+				Address:       "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3",
+				StartingBlock: 6885697,
+				Abi:           resolverContract.Abi,
+				Valid:         true,
+				Upgradeable:   true,
+				DetectedAt:    6885697,
+			}))
+		})
+
+		It("With Mock Fetcher: learns labels from NameChanged events and fills in full names", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = nameChangedLogs
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			label, err := t.LabelRepository.GetLabel("0xaf2caa1c2ca1d027f1ac823b529d0a67cd144264b2789fa2ea4d63a67c7103cc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(label).To(Equal("vitalik"))
+			record, err := t.ENSRepository.GetRecord("0xee6c4522aab0003e8d14cd40a6af439055fd2577951148c14b6cea9a53475835", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.Name).To(Equal("vitalik.eth"))
+			Expect(record.FullName).To(Equal("vitalik.eth"))
+			parent, err := t.ENSRepository.GetRecord("0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parent.FullName).To(Equal("eth"))
+		})
+
+		It("With Mock Fetcher: records the reverse nodes addresses claim on the registry", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			headerID, err := headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = reverseNewOwnerLogs
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				ReverseRepository:  rep.NewReverseRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			records, err := t.ReverseRepository.GetReverseRecords("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]models.ReverseRecordModel{{
+				BlockNumber: 6885695,
+				HeaderID:    headerID,
+				Address:     "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5",
+				Node:        "0xc064784cc17d497072842ddd7f42163fb0869db593d84febdf21412021cf025c",
+				Source:      models.ReverseSourceNewOwner,
+			}}))
+			label, err := t.LabelRepository.GetLabel("0x3ea49016cc0ff1f46bdc9804729ade1f16c1825fd0f97e9dee064c316e958082")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(label).To(Equal("ffd1ac3e8818adcbe5c597ea076e8d3210b45df5"))
+		})
+
+		It("With Mock Fetcher: takes the address claiming a reverse node owned by the ReverseRegistrar from the sender of the transaction", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			headerID, err := headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = registrarReverseNewOwnerLogs
+			transactionBlockChain := mocks.NewMockBlockChain()
+			transactionBlockChain.Transactions = []core.TransactionModel{{
+				Hash: common.HexToHash("0xMockTxHash01").Hex(),
+				From: "0xffd1ac3e8818adcbe5c597ea076e8d3210b45df5",
+				To:   "0x9062c0a6dbd6108336bcbe4593a3d1ce05512069",
+			}}
+			t := transformer.Transformer{
+				RegistryConfig:      con,
+				Fetcher:             f,
+				Parser:              parser.NewParser(""),
+				HeaderRepository:    repository.NewHeaderRepository(db),
+				Converter:           converter.Converter{},
+				Resolvers:           map[string]*contract.Contract{},
+				ENSRepository:       rep.NewENSRepository(db),
+				ResolverRepository:  rep.NewResolverRepository(db),
+				LabelRepository:     rep.NewLabelRepository(db),
+				ReverseRepository:   rep.NewReverseRepository(db),
+				InterfaceGetter:     getter.NewInterfaceGetter(blockChain),
+				TextGetter:          tgetter.NewTextGetter(blockChain),
+				ITransactionsSyncer: transactions.NewTransactionsSyncer(db, transactionBlockChain),
+				BlockRetriever:      retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			record, err := t.ENSRepository.GetRecord("0xc064784cc17d497072842ddd7f42163fb0869db593d84febdf21412021cf025c", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.Owner).To(Equal("0x9062C0A6Dbd6108336BcBe4593a3D1cE05512069"))
+			records, err := t.ReverseRepository.GetReverseRecords("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]models.ReverseRecordModel{{
+				BlockNumber: 6885695,
+				HeaderID:    headerID,
+				Address:     "0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5",
+				Node:        "0xc064784cc17d497072842ddd7f42163fb0869db593d84febdf21412021cf025c",
+				Source:      models.ReverseSourceNewOwner,
+			}}))
+			Expect(transactionBlockChain.GetTransactionsPassedHashes).To(Equal([]common.Hash{common.HexToHash("0xMockTxHash01")}))
+		})
+
+		It("With Mock Fetcher: skips reverse claims whose label isn't the address of the transaction's sender", func() {
+			header, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = registrarReverseNewOwnerLogs
+			transactionBlockChain := mocks.NewMockBlockChain()
+			transactionBlockChain.Transactions = []core.TransactionModel{{
+				Hash: common.HexToHash("0xMockTxHash01").Hex(),
+				From: "0x42032c22c510ad0698f16be9b99640efdeb02832",
+				To:   "0x9062c0a6dbd6108336bcbe4593a3d1ce05512069",
+			}}
+			t := transformer.Transformer{
+				RegistryConfig:      con,
+				Fetcher:             f,
+				Parser:              parser.NewParser(""),
+				HeaderRepository:    repository.NewHeaderRepository(db),
+				Converter:           converter.Converter{},
+				Resolvers:           map[string]*contract.Contract{},
+				ENSRepository:       rep.NewENSRepository(db),
+				ResolverRepository:  rep.NewResolverRepository(db),
+				LabelRepository:     rep.NewLabelRepository(db),
+				ReverseRepository:   rep.NewReverseRepository(db),
+				InterfaceGetter:     getter.NewInterfaceGetter(blockChain),
+				TextGetter:          tgetter.NewTextGetter(blockChain),
+				ITransactionsSyncer: transactions.NewTransactionsSyncer(db, transactionBlockChain),
+				BlockRetriever:      retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			records, err := t.ReverseRepository.GetReverseRecords("0x42032C22C510AD0698f16bE9b99640eFDEB02832")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(BeEmpty())
+			records, err = t.ReverseRepository.GetReverseRecords("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+
+		It("With Mock Fetcher: re-derives domain records when a header is replaced at the same height", func() {
+			header1, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			header2, err := blockChain.GetHeaderByNumber(6885696)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header1)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header2)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks[constants.EnsContractAddress] = 6885695
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = append(interleavedRegistryLogs, interleavedResolverLogs...)
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885696)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.Owner).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+			Expect(record.ResolverAddr).To(Equal("0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3"))
+
+			// Replace the first header with a reorged one at the same height, in which the subdomain is only created
+			reorgedHeader := header1
+			reorgedHeader.Hash = "0xReorgedBlockHash"
+			_, err = headerRepository.CreateOrUpdateHeader(reorgedHeader)
+			Expect(err).ToNot(HaveOccurred())
+			f.Logs = mockLogs[:1]
+
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+			for _, blockNumber := range []int64{6885695, 6885696} {
+				record, err = t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", blockNumber)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(record.Owner).To(Equal("0x42032C22C510AD0698f16bE9b99640eFDEB02832"))
+				Expect(record.ResolverAddr).To(Equal(""))
+				Expect(record.PointsToAddr).To(Equal(""))
+			}
+		})
+
+		It("With Mock Fetcher: takes the state of migrated nodes from the registry with fallback", func() {
+			header1, err := blockChain.GetHeaderByNumber(6885695)
+			Expect(err).ToNot(HaveOccurred())
+			header2, err := blockChain.GetHeaderByNumber(6885696)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header1)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = headerRepository.CreateOrUpdateHeader(header2)
+			Expect(err).ToNot(HaveOccurred())
+
+			con := config.MainnetENSConfig
+			con.StartingBlocks = map[string]int64{
+				constants.EnsContractAddress:       6885695,
+				config.RegistryWithFallbackAddress: 6885696,
+			}
+			f := mocks.NewMockFetcher(blockChain)
+			f.Logs = fallbackRegistryLogs
+			t := transformer.Transformer{
+				RegistryConfig:     con,
+				Fetcher:            f,
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
+			}
+
+			err = t.Init()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(t.FallbackRegistry.Address).To(Equal(config.RegistryWithFallbackAddress))
+			Expect(t.FallbackRegistry.Events).To(HaveKey("ApprovalForAll"))
+			err = t.Execute()
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885695)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.Owner).To(Equal("0xa54AEF7fA503E75a03b262A4Cd73037C1774735D"))
+			Expect(record.Migrated).To(Equal(false))
+			record, err = t.ENSRepository.GetRecord("0x5954c882606735d75f2775ff380873d6d6b546f63cdf79424f12209b9e15bb91", 6885696)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record.Owner).To(Equal("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5"))
+			Expect(record.Migrated).To(Equal(true))
+
+			approvals, err := t.ENSRepository.GetApprovals("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5", 6885696)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(approvals)).To(Equal(1))
+			Expect(approvals[0].RegistryAddr).To(Equal(config.RegistryWithFallbackAddress))
+			Expect(approvals[0].Operator).To(Equal("0x42032C22C510AD0698f16bE9b99640eFDEB02832"))
+			Expect(approvals[0].Approved).To(Equal(true))
 		})
 
 		It("With real fetcher: Transforms registry event data into domain records", func() {
@@ -186,15 +1337,18 @@ var _ = Describe("Transformer", func() {
 			con := config.MainnetENSConfig
 			con.StartingBlocks[constants.EnsContractAddress] = 7483567
 			t := transformer.Transformer{
-				RegistryConfig:   con,
-				Fetcher:          fetcher.NewFetcher(blockChain),
-				Parser:           parser.NewParser(""),
-				HeaderRepository: repository.NewHeaderRepository(db),
-				Converter:        converter.Converter{},
-				Resolvers:        map[string]*contract.Contract{},
-				ENSRepository:    rep.NewENSRepository(db),
-				InterfaceGetter:  getter.NewInterfaceGetter(blockChain),
-				BlockRetriever:   retriever.NewBlockRetriever(db),
+				RegistryConfig:     con,
+				Fetcher:            fetcher.NewFetcher(blockChain),
+				Parser:             parser.NewParser(""),
+				HeaderRepository:   repository.NewHeaderRepository(db),
+				Converter:          converter.Converter{},
+				Resolvers:          map[string]*contract.Contract{},
+				ENSRepository:      rep.NewENSRepository(db),
+				ResolverRepository: rep.NewResolverRepository(db),
+				LabelRepository:    rep.NewLabelRepository(db),
+				InterfaceGetter:    getter.NewInterfaceGetter(blockChain),
+				TextGetter:         tgetter.NewTextGetter(blockChain),
+				BlockRetriever:     retriever.NewBlockRetriever(db),
 			}
 			err := t.Init()
 			Expect(err).ToNot(HaveOccurred())
@@ -17,12 +17,110 @@
 package utils
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/types"
 )
 
+// Namehash of the root node, the parent of top level domains such as "eth"
+const RootNode = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+// Namehash of "addr.reverse", the parent of the reverse node of every address
+const ReverseNode = "0x91d1777781884d03a6757a803996e38de2a42967fb37eeaca72729271025a9e2"
+
+// Pairs a converted log with the name of the event it was converted as and the address of the contract that emitted it
+type EventLog struct {
+	Name    string
+	Address string
+	types.Log
+}
+
 func CreateSubnode(node, label string) string {
 	nodeBytes := common.HexToHash(node)
 	labelBytes := common.HexToHash(label)
 	return crypto.Keccak256Hash(append(nodeBytes.Bytes(), labelBytes.Bytes()...)).Hex()
 }
+
+// Returns the label of an address's reverse node, its hex address in lowercase without the 0x prefix
+func ReverseLabel(address string) string {
+	return strings.ToLower(strings.TrimPrefix(common.HexToAddress(address).Hex(), "0x"))
+}
+
+// Returns the reverse node of an address, i.e. the namehash of "<address>.addr.reverse"
+func ReverseNodeOf(address string) string {
+	return CreateSubnode(ReverseNode, LabelHash(ReverseLabel(address)))
+}
+
+// Returns the label hash (keccak256) of a plaintext label
+func LabelHash(label string) string {
+	return crypto.Keccak256Hash([]byte(label)).Hex()
+}
+
+// Returns the namehash of a dotted name, hashing its labels from the top level domain down; the empty name is the root node
+// The name is expected to already be normalized
+func NameHash(name string) string {
+	node := RootNode
+	labels := SplitName(name)
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = CreateSubnode(node, LabelHash(labels[i]))
+	}
+
+	return node
+}
+
+// Normalizes a name the way names are looked up by: lowercased, without a trailing dot
+func NormalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+// Splits a dotted name into its labels, e.g. "sub.vitalik.eth" into "sub", "vitalik" and "eth"
+// Empty labels, e.g. from a trailing dot, are dropped
+func SplitName(name string) []string {
+	labels := make([]string, 0)
+	for _, label := range strings.Split(name, ".") {
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels
+}
+
+// Represents a label whose preimage we don't know by its hash in square brackets, e.g. "[5cee33...]"
+func UnknownLabel(labelHash string) string {
+	return "[" + strings.TrimPrefix(labelHash, "0x") + "]"
+}
+
+// Merges the converted logs of every event into a single slice, ordered by their position in the block (transaction index, then log index)
+func OrderLogs(logs map[string][]types.Log) []EventLog {
+	return OrderContractLogs(map[string]map[string][]types.Log{"": logs})
+}
+
+// Merges the converted logs of several contracts (contract address => event name => logs) into a single slice,
+// ordered by their position in the block, so that logs emitted by different contracts can be applied in the order they were emitted
+func OrderContractLogs(logs map[string]map[string][]types.Log) []EventLog {
+	ordered := make([]EventLog, 0)
+	for addr, contractLogs := range logs {
+		for name, eventLogs := range contractLogs {
+			for _, log := range eventLogs {
+				ordered = append(ordered, EventLog{
+					Name:    name,
+					Address: addr,
+					Log:     log,
+				})
+			}
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].TransactionIndex != ordered[j].TransactionIndex {
+			return ordered[i].TransactionIndex < ordered[j].TransactionIndex
+		}
+		return ordered[i].LogIndex < ordered[j].LogIndex
+	})
+
+	return ordered
+}
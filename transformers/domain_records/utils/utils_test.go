@@ -19,6 +19,7 @@ package utils_test
 import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/types"
 
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
 )
@@ -32,4 +33,100 @@ var _ = Describe("Utils", func() {
 			Expect(subnode).To(Equal("0xb4664b154f4dd9abf5bb27d6e3ff12181d6e37b0606b4ff61ff8796e6e29a2e4"))
 		})
 	})
+
+	Describe("LabelHash", func() {
+		It("Hashes a plaintext label", func() {
+			Expect(utils.LabelHash("eth")).To(Equal("0x4f5b812789fc606be1b3b16908db13fc7a9adf7ca72641f84d75b47069d3d7f0"))
+		})
+	})
+
+	Describe("ReverseNodeOf", func() {
+		It("Hashes the lowercase hex address under addr.reverse", func() {
+			Expect(utils.ReverseLabel("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5")).To(Equal("ffd1ac3e8818adcbe5c597ea076e8d3210b45df5"))
+			Expect(utils.CreateSubnode(utils.CreateSubnode(utils.RootNode, utils.LabelHash("reverse")), utils.LabelHash("addr"))).To(Equal(utils.ReverseNode))
+			Expect(utils.ReverseNodeOf("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5")).To(Equal("0xc064784cc17d497072842ddd7f42163fb0869db593d84febdf21412021cf025c"))
+		})
+	})
+
+	Describe("NameHash", func() {
+		It("Hashes the labels of a dotted name from the top level domain down", func() {
+			Expect(utils.NameHash("eth")).To(Equal("0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"))
+			Expect(utils.NameHash("vitalik.eth")).To(Equal("0xee6c4522aab0003e8d14cd40a6af439055fd2577951148c14b6cea9a53475835"))
+		})
+
+		It("Returns the root node for the empty name", func() {
+			Expect(utils.NameHash("")).To(Equal(utils.RootNode))
+		})
+	})
+
+	Describe("NormalizeName", func() {
+		It("Lowercases the name and drops its trailing dot", func() {
+			Expect(utils.NormalizeName("Vitalik.ETH.")).To(Equal("vitalik.eth"))
+		})
+	})
+
+	Describe("SplitName", func() {
+		It("Splits a dotted name into its labels", func() {
+			Expect(utils.SplitName("sub.vitalik.eth")).To(Equal([]string{"sub", "vitalik", "eth"}))
+		})
+
+		It("Drops empty labels", func() {
+			Expect(utils.SplitName("vitalik.eth.")).To(Equal([]string{"vitalik", "eth"}))
+			Expect(utils.SplitName("")).To(Equal([]string{}))
+		})
+	})
+
+	Describe("UnknownLabel", func() {
+		It("Wraps the label hash in square brackets", func() {
+			unknown := utils.UnknownLabel("0x4f5b812789fc606be1b3b16908db13fc7a9adf7ca72641f84d75b47069d3d7f0")
+			Expect(unknown).To(Equal("[4f5b812789fc606be1b3b16908db13fc7a9adf7ca72641f84d75b47069d3d7f0]"))
+		})
+	})
+
+	Describe("OrderLogs", func() {
+		It("Merges the logs of every event in the order they were emitted", func() {
+			transfer := types.Log{TransactionIndex: 1, LogIndex: 3, Values: map[string]string{"owner": "0x2"}}
+			firstNewOwner := types.Log{TransactionIndex: 0, LogIndex: 7, Values: map[string]string{"owner": "0x1"}}
+			secondNewOwner := types.Log{TransactionIndex: 1, LogIndex: 4, Values: map[string]string{"owner": "0x3"}}
+			newTTL := types.Log{TransactionIndex: 1, LogIndex: 2, Values: map[string]string{"ttl": "100"}}
+			logs := map[string][]types.Log{
+				"NewOwner":    {secondNewOwner, firstNewOwner},
+				"Transfer":    {transfer},
+				"NewTTL":      {newTTL},
+				"NewResolver": {},
+			}
+
+			ordered := utils.OrderLogs(logs)
+			Expect(ordered).To(Equal([]utils.EventLog{
+				{Name: "NewOwner", Log: firstNewOwner},
+				{Name: "NewTTL", Log: newTTL},
+				{Name: "Transfer", Log: transfer},
+				{Name: "NewOwner", Log: secondNewOwner},
+			}))
+		})
+
+		It("Returns an empty slice if there are no logs", func() {
+			ordered := utils.OrderLogs(map[string][]types.Log{"AddrChanged": {}})
+			Expect(len(ordered)).To(Equal(0))
+		})
+	})
+
+	Describe("OrderContractLogs", func() {
+		It("Merges the logs of several contracts in the order they were emitted", func() {
+			oldTransfer := types.Log{TransactionIndex: 0, LogIndex: 1, Values: map[string]string{"owner": "0x1"}}
+			newOwner := types.Log{TransactionIndex: 0, LogIndex: 0, Values: map[string]string{"owner": "0x2"}}
+			newTransfer := types.Log{TransactionIndex: 1, LogIndex: 0, Values: map[string]string{"owner": "0x3"}}
+			logs := map[string]map[string][]types.Log{
+				"0xOld": {"Transfer": {oldTransfer}},
+				"0xNew": {"Transfer": {newTransfer}, "NewOwner": {newOwner}},
+			}
+
+			ordered := utils.OrderContractLogs(logs)
+			Expect(ordered).To(Equal([]utils.EventLog{
+				{Name: "NewOwner", Address: "0xNew", Log: newOwner},
+				{Name: "Transfer", Address: "0xOld", Log: oldTransfer},
+				{Name: "Transfer", Address: "0xNew", Log: newTransfer},
+			}))
+		})
+	})
 })
@@ -84,6 +84,7 @@ func (converter HashInvalidatedConverter) ToModels(entities []interface{}) ([]in
 			RegistrationDate: hashEntity.RegistrationDate.String(),
 			LogIndex:         logIdx,
 			TransactionIndex: txIdx,
+			TransactionHash:  hashEntity.Raw.TxHash.Hex(),
 			Raw:              rawLog,
 		}
 		models = append(models, model)
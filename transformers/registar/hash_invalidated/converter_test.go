@@ -77,6 +77,7 @@ var _ = Describe("HashInvalidated Converter", func() {
 				Value:            temp.String(),
 				RegistrationDate: temp.String(),
 				TransactionIndex: 0,
+				TransactionHash:  "0x0000000000000000000000000000000000000000000000000000000000000000",
 				Raw:              emptyLog,
 			}
 			models, err := converter.ToModels([]interface{}{emptyEntity})
@@ -0,0 +1,88 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hash_invalidated
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	ensRepo "github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+const invalidateNameAbi = `[{"constant":false,"inputs":[{"name":"unhashedName","type":"string"}],"name":"invalidateName","outputs":[],"payable":false,"type":"function"}]`
+
+// The name is an indexed string, so the event only emits its hash
+// Recovers the plaintext name from the input of the invalidateName transaction that emitted the event, which the watcher syncs before transforming the logs
+// Returns an empty string if the transaction hasn't been synced or didn't call invalidateName directly
+func recoverName(tx *sqlx.Tx, headerID int64, model HashInvalidatedModel) (string, error) {
+	var input []byte
+	err := tx.Get(&input,
+		`SELECT input_data FROM public.light_sync_transactions WHERE header_id = $1 AND hash = $2`,
+		headerID, model.TransactionHash,
+	)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return decodeName(input, model.Name)
+}
+
+// Decodes the name argument from invalidateName call data, returning it only if it hashes to the name emitted in the event
+func decodeName(input []byte, nameHash string) (string, error) {
+	parsed, err := abi.JSON(strings.NewReader(invalidateNameAbi))
+	if err != nil {
+		return "", err
+	}
+	method := parsed.Methods["invalidateName"]
+	if len(input) < 4 || !bytes.Equal(input[:4], method.Id()) {
+		return "", nil
+	}
+	values, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil || len(values) != 1 {
+		return "", nil
+	}
+	name, ok := values[0].(string)
+	if !ok || crypto.Keccak256Hash([]byte(name)).Hex() != nameHash {
+		return "", nil
+	}
+
+	return name, nil
+}
+
+func createLabel(tx *sqlx.Tx, headerID int64, model HashInvalidatedModel) error {
+	name, err := recoverName(tx, headerID, model)
+	if err != nil || name == "" {
+		return err
+	}
+	_, err = ensRepo.CreateLabelInTransaction(tx, models.LabelModel{
+		LabelHash: utils.LabelHash(name),
+		Label:     name,
+		Source:    "HashInvalidated",
+	})
+
+	return err
+}
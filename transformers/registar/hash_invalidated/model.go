@@ -23,5 +23,6 @@ type HashInvalidatedModel struct {
 	RegistrationDate string `db:"registration_date"`
 	LogIndex         uint   `db:"log_idx"`
 	TransactionIndex uint   `db:"tx_idx"`
+	TransactionHash  string `db:"-"`
 	Raw              []byte `db:"raw_log"`
 }
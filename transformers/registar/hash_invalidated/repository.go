@@ -64,6 +64,16 @@ func (repository HashInvalidatedRepository) Create(headerID int64, models []inte
 			}
 			return execErr
 		}
+
+		// Seed the label table with the plaintext of the invalidated name, if it can be recovered
+		labelErr := createLabel(tx, headerID, hashModel)
+		if labelErr != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				log.Error("failed to rollback ", rollbackErr)
+			}
+			return labelErr
+		}
 	}
 
 	checkHeaderErr := repo.MarkHeaderCheckedInTransaction(headerID, tx, constants.HashInvalidatedChecked)
@@ -17,6 +17,7 @@
 package hash_invalidated_test
 
 import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -76,6 +77,39 @@ var _ = Describe("HashInvalidated repository", func() {
 			Expect(dbHashInvalidated.TransactionIndex).To(Equal(test_data.HashInvalidatedModel.TransactionIndex))
 			Expect(dbHashInvalidated.Raw).To(MatchJSON(test_data.HashInvalidatedModel.Raw))
 		})
+
+		It("seeds the label table with the name recovered from the invalidateName transaction", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+			// invalidateName("testName")
+			input := hexutil.MustDecode("0x15f7333100000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000008746573744e616d65000000000000000000000000000000000000000000000000")
+			_, err = db.Exec(`INSERT INTO public.light_sync_transactions (header_id, hash, input_data) VALUES ($1, $2, $3)`,
+				headerID, test_data.HashInvalidatedModel.TransactionHash, input)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = hashInvalidatedRepository.Create(headerID, []interface{}{test_data.HashInvalidatedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var label string
+			err = db.Get(&label, `SELECT label FROM ens.labels WHERE label_hash = $1`, test_data.HashInvalidatedModel.Name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(label).To(Equal("testName"))
+		})
+
+		It("doesn't seed the label table if the transaction hasn't been synced", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = hashInvalidatedRepository.Create(headerID, []interface{}{test_data.HashInvalidatedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var count int
+			err = db.Get(&count, `SELECT COUNT(*) FROM ens.labels`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
 	})
 
 	Describe("MarkHeaderChecked", func() {
@@ -25,7 +25,7 @@ import (
 func GetNewOwnerConfig() shared_t.EventTransformerConfig {
 	return shared_t.EventTransformerConfig{
 		TransformerName:     constants.NewOwnerLabel,
-		ContractAddresses:   []string{constants.RegistryContractAddress()},
+		ContractAddresses:   []string{constants.RegistryContractAddress(), constants.RegistryWithFallbackContractAddress()},
 		ContractAbi:         constants.RegistryABI(),
 		Topic:               constants.GetNewOwnerSignature(),
 		StartingBlockNumber: constants.RegistryDeploymentBlock(),
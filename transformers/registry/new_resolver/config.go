@@ -25,7 +25,7 @@ import (
 func GetNewResolverConfig() shared_t.EventTransformerConfig {
 	return shared_t.EventTransformerConfig{
 		TransformerName:     constants.NewResolverLabel,
-		ContractAddresses:   []string{constants.RegistryContractAddress()},
+		ContractAddresses:   []string{constants.RegistryContractAddress(), constants.RegistryWithFallbackContractAddress()},
 		ContractAbi:         constants.RegistryABI(),
 		Topic:               constants.GetNewResolverSignature(),
 		StartingBlockNumber: constants.RegistryDeploymentBlock(),
@@ -25,7 +25,7 @@ import (
 func GetNewTtlConfig() shared_t.EventTransformerConfig {
 	return shared_t.EventTransformerConfig{
 		TransformerName:     constants.NewTtlLabel,
-		ContractAddresses:   []string{constants.RegistryContractAddress()},
+		ContractAddresses:   []string{constants.RegistryContractAddress(), constants.RegistryWithFallbackContractAddress()},
 		ContractAbi:         constants.RegistryABI(),
 		Topic:               constants.GetNewTtlSignature(),
 		StartingBlockNumber: constants.RegistryDeploymentBlock(),
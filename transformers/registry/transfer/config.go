@@ -25,7 +25,7 @@ import (
 func GetTransferConfig() shared_t.EventTransformerConfig {
 	return shared_t.EventTransformerConfig{
 		TransformerName:     constants.TransferLabel,
-		ContractAddresses:   []string{constants.RegistryContractAddress()},
+		ContractAddresses:   []string{constants.RegistryContractAddress(), constants.RegistryWithFallbackContractAddress()},
 		ContractAbi:         constants.RegistryABI(),
 		Topic:               constants.GetTransferSignature(),
 		StartingBlockNumber: constants.RegistryDeploymentBlock(),
@@ -0,0 +1,89 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package address_changed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/address_changed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("AddressChanged repository", func() {
+	var (
+		addressChangedRepository address_changed.AddressChangedRepository
+		db                       *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		addressChangedRepository = address_changed.AddressChangedRepository{}
+		addressChangedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.AddressChangedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.AddressChangedChecked,
+			LogEventTableName:        "ens.address_changed",
+			TestModel:                test_data.AddressChangedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &addressChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists an address_changed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = addressChangedRepository.Create(headerID, []interface{}{test_data.AddressChangedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbAddressChanged address_changed.AddressChangedModel
+			err = db.Get(&dbAddressChanged, `SELECT resolver, node, coin_type, new_address, log_idx, tx_idx, raw_log FROM ens.address_changed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbAddressChanged.Resolver).To(Equal(test_data.AddressChangedModel.Resolver))
+			Expect(dbAddressChanged.Node).To(Equal(test_data.AddressChangedModel.Node))
+			Expect(dbAddressChanged.CoinType).To(Equal(test_data.AddressChangedModel.CoinType))
+			Expect(dbAddressChanged.NewAddress).To(Equal(test_data.AddressChangedModel.NewAddress))
+			Expect(dbAddressChanged.LogIndex).To(Equal(test_data.AddressChangedModel.LogIndex))
+			Expect(dbAddressChanged.TransactionIndex).To(Equal(test_data.AddressChangedModel.TransactionIndex))
+			Expect(dbAddressChanged.Raw).To(MatchJSON(test_data.AddressChangedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.AddressChangedChecked,
+			Repository:              &addressChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
@@ -0,0 +1,90 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package authorisation_changed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/authorisation_changed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("AuthorisationChanged repository", func() {
+	var (
+		authorisationChangedRepository authorisation_changed.AuthorisationChangedRepository
+		db                             *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		authorisationChangedRepository = authorisation_changed.AuthorisationChangedRepository{}
+		authorisationChangedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.AuthorisationChangedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.AuthorisationChangedChecked,
+			LogEventTableName:        "ens.authorisation_changed",
+			TestModel:                test_data.AuthorisationChangedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &authorisationChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists an authorisation_changed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = authorisationChangedRepository.Create(headerID, []interface{}{test_data.AuthorisationChangedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbAuthorisationChanged authorisation_changed.AuthorisationChangedModel
+			err = db.Get(&dbAuthorisationChanged, `SELECT resolver, node, owner, target, is_authorised, log_idx, tx_idx, raw_log FROM ens.authorisation_changed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbAuthorisationChanged.Resolver).To(Equal(test_data.AuthorisationChangedModel.Resolver))
+			Expect(dbAuthorisationChanged.Node).To(Equal(test_data.AuthorisationChangedModel.Node))
+			Expect(dbAuthorisationChanged.Owner).To(Equal(test_data.AuthorisationChangedModel.Owner))
+			Expect(dbAuthorisationChanged.Target).To(Equal(test_data.AuthorisationChangedModel.Target))
+			Expect(dbAuthorisationChanged.IsAuthorised).To(Equal(test_data.AuthorisationChangedModel.IsAuthorised))
+			Expect(dbAuthorisationChanged.LogIndex).To(Equal(test_data.AuthorisationChangedModel.LogIndex))
+			Expect(dbAuthorisationChanged.TransactionIndex).To(Equal(test_data.AuthorisationChangedModel.TransactionIndex))
+			Expect(dbAuthorisationChanged.Raw).To(MatchJSON(test_data.AuthorisationChangedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.AuthorisationChangedChecked,
+			Repository:              &authorisationChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
@@ -22,8 +22,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/contenthash"
 )
 
 type ContentChangedConverter struct{}
@@ -70,10 +73,17 @@ func (converter ContentChangedConverter) ToModels(entities []interface{}) ([]int
 			return nil, err
 		}
 
+		// A content hash we can't decode is still persisted with its raw value
+		protocol, uri, err := contenthash.DecodeSwarmHash(contentEntity.Hash.Bytes())
+		if err != nil {
+			log.Warnf("unable to decode content hash %x on node %s: %v", contentEntity.Hash.Bytes(), contentEntity.Node.Hex(), err)
+		}
 		model := ContentChangedModel{
 			Resolver:         contentEntity.Resolver.Hex(),
 			Node:             contentEntity.Node.Hex(),
 			Hash:             contentEntity.Hash.Hex(),
+			Protocol:         protocol,
+			URI:              uri,
 			LogIndex:         logIdx,
 			TransactionIndex: txIdx,
 			Raw:              rawLog,
@@ -20,6 +20,8 @@ type ContentChangedModel struct {
 	Resolver         string
 	Node             string
 	Hash             string
+	Protocol         string `db:"content_protocol"`
+	URI              string `db:"content_uri"`
 	LogIndex         uint   `db:"log_idx"`
 	TransactionIndex uint   `db:"tx_idx"`
 	Raw              []byte `db:"raw_log"`
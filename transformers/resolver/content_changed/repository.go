@@ -52,10 +52,10 @@ func (repository ContentChangedRepository) Create(headerID int64, models []inter
 		}
 
 		_, execErr := tx.Exec(
-			`INSERT into ens.content_changed (header_id, resolver, node, hash, log_idx, tx_idx, raw_log)
-        			VALUES($1, $2, $3, $4, $5, $6, $7)
-					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET resolver = $2, node = $3, hash = $4, raw_log = $7;`,
-			headerID, contentModel.Resolver, contentModel.Node, contentModel.Hash, contentModel.LogIndex, contentModel.TransactionIndex, contentModel.Raw,
+			`INSERT into ens.content_changed (header_id, resolver, node, hash, content_protocol, content_uri, log_idx, tx_idx, raw_log)
+        			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET resolver = $2, node = $3, hash = $4, content_protocol = $5, content_uri = $6, raw_log = $9;`,
+			headerID, contentModel.Resolver, contentModel.Node, contentModel.Hash, contentModel.Protocol, contentModel.URI, contentModel.LogIndex, contentModel.TransactionIndex, contentModel.Raw,
 		)
 		if execErr != nil {
 			rollbackErr := tx.Rollback()
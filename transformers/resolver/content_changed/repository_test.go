@@ -66,11 +66,13 @@ var _ = Describe("ContentChanged repository", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			var dbContentChanged content_changed.ContentChangedModel
-			err = db.Get(&dbContentChanged, `SELECT resolver, node, hash, log_idx, tx_idx, raw_log FROM ens.content_changed WHERE header_id = $1`, headerID)
+			err = db.Get(&dbContentChanged, `SELECT resolver, node, hash, content_protocol, content_uri, log_idx, tx_idx, raw_log FROM ens.content_changed WHERE header_id = $1`, headerID)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(dbContentChanged.Resolver).To(Equal(test_data.ContentChangedModel.Resolver))
 			Expect(dbContentChanged.Node).To(Equal(test_data.ContentChangedModel.Node))
 			Expect(dbContentChanged.Hash).To(Equal(test_data.ContentChangedModel.Hash))
+			Expect(dbContentChanged.Protocol).To(Equal(test_data.ContentChangedModel.Protocol))
+			Expect(dbContentChanged.URI).To(Equal(test_data.ContentChangedModel.URI))
 			Expect(dbContentChanged.LogIndex).To(Equal(test_data.ContentChangedModel.LogIndex))
 			Expect(dbContentChanged.TransactionIndex).To(Equal(test_data.ContentChangedModel.TransactionIndex))
 			Expect(dbContentChanged.Raw).To(MatchJSON(test_data.ContentChangedModel.Raw))
@@ -21,8 +21,11 @@ import (
 	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/contenthash"
 )
 
 type ContenthashChangedConverter struct{}
@@ -68,10 +71,17 @@ func (converter ContenthashChangedConverter) ToModels(entities []interface{}) ([
 		if err != nil {
 			return nil, err
 		}
+		// A contenthash we can't decode is still persisted with its raw value
+		protocol, uri, err := contenthash.Decode(contentEntity.Hash)
+		if err != nil {
+			log.Warnf("unable to decode contenthash %x on node %s: %v", contentEntity.Hash, contentEntity.Node.Hex(), err)
+		}
 		model := ContenthashChangedModel{
 			Resolver:         contentEntity.Resolver.Hex(),
 			Node:             contentEntity.Node.Hex(),
 			Hash:             contentEntity.Hash,
+			Protocol:         protocol,
+			URI:              uri,
 			LogIndex:         logIdx,
 			TransactionIndex: txIdx,
 			Raw:              rawLog,
@@ -20,6 +20,8 @@ type ContenthashChangedModel struct {
 	Resolver         string
 	Node             string
 	Hash             []byte
+	Protocol         string `db:"content_protocol"`
+	URI              string `db:"content_uri"`
 	LogIndex         uint   `db:"log_idx"`
 	TransactionIndex uint   `db:"tx_idx"`
 	Raw              []byte `db:"raw_log"`
@@ -66,11 +66,13 @@ var _ = Describe("ContenthashChanged repository", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			var dbContenthashChanged contenthash_changed.ContenthashChangedModel
-			err = db.Get(&dbContenthashChanged, `SELECT resolver, node, hash, log_idx, tx_idx, raw_log FROM ens.contenthash_changed WHERE header_id = $1`, headerID)
+			err = db.Get(&dbContenthashChanged, `SELECT resolver, node, hash, content_protocol, content_uri, log_idx, tx_idx, raw_log FROM ens.contenthash_changed WHERE header_id = $1`, headerID)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(dbContenthashChanged.Resolver).To(Equal(test_data.ContenthashChangedModel.Resolver))
 			Expect(dbContenthashChanged.Node).To(Equal(test_data.ContenthashChangedModel.Node))
 			Expect(dbContenthashChanged.Hash).To(Equal(test_data.ContenthashChangedModel.Hash))
+			Expect(dbContenthashChanged.Protocol).To(Equal(test_data.ContenthashChangedModel.Protocol))
+			Expect(dbContenthashChanged.URI).To(Equal(test_data.ContenthashChangedModel.URI))
 			Expect(dbContenthashChanged.LogIndex).To(Equal(test_data.ContenthashChangedModel.LogIndex))
 			Expect(dbContenthashChanged.TransactionIndex).To(Equal(test_data.ContenthashChangedModel.TransactionIndex))
 			Expect(dbContenthashChanged.Raw).To(MatchJSON(test_data.ContenthashChangedModel.Raw))
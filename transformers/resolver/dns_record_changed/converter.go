@@ -0,0 +1,113 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_record_changed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/dns"
+)
+
+type DNSRecordChangedConverter struct{}
+
+func (DNSRecordChangedConverter) ToEntities(contractAbi string, ethLogs []types.Log) ([]interface{}, error) {
+	var entities []interface{}
+	for _, ethLog := range ethLogs {
+		entity := &DNSRecordChangedEntity{}
+		entity.Resolver = ethLog.Address
+		abi, err := geth.ParseAbi(contractAbi)
+		if err != nil {
+			return nil, err
+		}
+
+		contract := bind.NewBoundContract(entity.Resolver, abi, nil, nil, nil)
+
+		err = contract.UnpackLog(entity, "DNSRecordChanged", ethLog)
+		if err != nil {
+			return nil, err
+		}
+
+		entity.Raw = ethLog
+		entity.LogIndex = ethLog.Index
+		entity.TransactionIndex = ethLog.TxIndex
+
+		entities = append(entities, *entity)
+	}
+
+	return entities, nil
+}
+
+func (converter DNSRecordChangedConverter) ToModels(entities []interface{}) ([]interface{}, error) {
+	var models []interface{}
+	for _, entity := range entities {
+		recordEntity, ok := entity.(DNSRecordChangedEntity)
+		if !ok {
+			return nil, fmt.Errorf("entity of type %T, not %T", entity, DNSRecordChangedEntity{})
+		}
+
+		logIdx := recordEntity.LogIndex
+		txIdx := recordEntity.TransactionIndex
+		rawLog, err := json.Marshal(recordEntity.Raw)
+		if err != nil {
+			return nil, err
+		}
+
+		// Names that aren't valid wire format are kept with an empty name, the raw name is still in the raw log
+		name, err := dns.ParseName(recordEntity.Name)
+		if err != nil {
+			log.Warnf("unable to parse DNS name %s on node %s: %v", hexutil.Encode(recordEntity.Name), recordEntity.Node.Hex(), err)
+		}
+		// An RRset that can't be parsed is kept with no resource records, the raw record is still kept
+		records, err := dns.ParseRRSet(recordEntity.Record)
+		if err != nil {
+			log.Warnf("unable to parse DNS record %s on node %s: %v", hexutil.Encode(recordEntity.Record), recordEntity.Node.Hex(), err)
+		}
+		var resourceRecords []ResourceRecordModel
+		for _, record := range records {
+			resourceRecords = append(resourceRecords, ResourceRecordModel{
+				Name:  record.Name,
+				Type:  record.Type,
+				Class: record.Class,
+				TTL:   record.TTL,
+				Rdata: hexutil.Encode(record.Rdata),
+			})
+		}
+
+		model := DNSRecordChangedModel{
+			Resolver:         recordEntity.Resolver.Hex(),
+			Node:             recordEntity.Node.Hex(),
+			Name:             name,
+			Resource:         strconv.FormatUint(uint64(recordEntity.Resource), 10),
+			Record:           hexutil.Encode(recordEntity.Record),
+			LogIndex:         logIdx,
+			TransactionIndex: txIdx,
+			Raw:              rawLog,
+			ResourceRecords:  resourceRecords,
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
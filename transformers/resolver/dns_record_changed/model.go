@@ -0,0 +1,38 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_record_changed
+
+type DNSRecordChangedModel struct {
+	Resolver         string
+	Node             string
+	Name             string `db:"name"`
+	Resource         string `db:"resource"`
+	Record           string `db:"record"`
+	LogIndex         uint   `db:"log_idx"`
+	TransactionIndex uint   `db:"tx_idx"`
+	Raw              []byte `db:"raw_log"`
+	// The resource records of the RRset, persisted into their own table
+	ResourceRecords []ResourceRecordModel `db:"-"`
+}
+
+type ResourceRecordModel struct {
+	Name  string `db:"name"`
+	Type  uint16 `db:"type"`
+	Class uint16 `db:"class"`
+	TTL   uint32 `db:"ttl"`
+	Rdata string `db:"rdata"`
+}
@@ -0,0 +1,124 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_record_changed
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+
+	repo "github.com/vulcanize/vulcanizedb/libraries/shared/repository"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+)
+
+type DNSRecordChangedRepository struct {
+	db *postgres.DB
+}
+
+func (repository *DNSRecordChangedRepository) SetDB(db *postgres.DB) {
+	repository.db = db
+}
+
+func (repository DNSRecordChangedRepository) Create(headerID int64, models []interface{}) error {
+	tx, dBaseErr := repository.db.Beginx()
+	if dBaseErr != nil {
+		return dBaseErr
+	}
+	execErr := CreateInTransaction(tx, headerID, models)
+	if execErr != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return execErr
+	}
+
+	checkHeaderErr := repo.MarkHeaderCheckedInTransaction(headerID, tx, constants.DNSRecordChangedChecked)
+	if checkHeaderErr != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return checkHeaderErr
+	}
+
+	return tx.Commit()
+}
+
+// Replaces the resource records of the RRset set by a DNSRecordChanged event
+func createResourceRecords(tx *sqlx.Tx, recordID int64, records []ResourceRecordModel) error {
+	_, err := tx.Exec(`DELETE FROM ens.dns_resource_records WHERE dns_record_changed_id = $1`, recordID)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		_, err = tx.Exec(
+			`INSERT INTO ens.dns_resource_records (dns_record_changed_id, name, type, class, ttl, rdata)
+					VALUES ($1, $2, $3, $4, $5, $6)`,
+			recordID, record.Name, record.Type, record.Class, record.TTL, record.Rdata,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Persists the models and the resource records of their RRsets within the given transaction, without marking the header checked
+func CreateInTransaction(tx *sqlx.Tx, headerID int64, models []interface{}) error {
+	for _, model := range models {
+		recordModel, ok := model.(DNSRecordChangedModel)
+		if !ok {
+			return fmt.Errorf("model of type %T, not %T", model, DNSRecordChangedModel{})
+		}
+
+		var recordID int64
+		execErr := tx.QueryRow(
+			`INSERT into ens.dns_record_changed (header_id, resolver, node, name, resource, record, log_idx, tx_idx, raw_log)
+        			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET resolver = $2, node = $3, name = $4, resource = $5, record = $6, raw_log = $9
+					RETURNING id`,
+			headerID, recordModel.Resolver, recordModel.Node, recordModel.Name, recordModel.Resource, recordModel.Record, recordModel.LogIndex, recordModel.TransactionIndex, recordModel.Raw,
+		).Scan(&recordID)
+		if execErr != nil {
+			return execErr
+		}
+		execErr = createResourceRecords(tx, recordID, recordModel.ResourceRecords)
+		if execErr != nil {
+			return execErr
+		}
+	}
+
+	return nil
+}
+
+func (repository DNSRecordChangedRepository) MarkHeaderChecked(headerID int64) error {
+	return repo.MarkHeaderChecked(headerID, repository.db, constants.DNSRecordChangedChecked)
+}
+
+func (repository DNSRecordChangedRepository) MissingHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.MissingHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.DNSRecordChangedChecked)
+}
+
+func (repository DNSRecordChangedRepository) RecheckHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.RecheckHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.DNSRecordChangedChecked)
+}
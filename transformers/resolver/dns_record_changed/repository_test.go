@@ -0,0 +1,151 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_record_changed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_record_changed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("DNSRecordChanged repository", func() {
+	var (
+		dnsRecordChangedRepository dns_record_changed.DNSRecordChangedRepository
+		db                         *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		dnsRecordChangedRepository = dns_record_changed.DNSRecordChangedRepository{}
+		dnsRecordChangedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.DNSRecordChangedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.DNSRecordChangedChecked,
+			LogEventTableName:        "ens.dns_record_changed",
+			TestModel:                test_data.DNSRecordChangedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &dnsRecordChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a dns_record_changed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = dnsRecordChangedRepository.Create(headerID, []interface{}{test_data.DNSRecordChangedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbDNSRecordChanged dns_record_changed.DNSRecordChangedModel
+			err = db.Get(&dbDNSRecordChanged, `SELECT resolver, node, name, resource, record, log_idx, tx_idx, raw_log FROM ens.dns_record_changed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbDNSRecordChanged.Resolver).To(Equal(test_data.DNSRecordChangedModel.Resolver))
+			Expect(dbDNSRecordChanged.Node).To(Equal(test_data.DNSRecordChangedModel.Node))
+			Expect(dbDNSRecordChanged.Name).To(Equal(test_data.DNSRecordChangedModel.Name))
+			Expect(dbDNSRecordChanged.Resource).To(Equal(test_data.DNSRecordChangedModel.Resource))
+			Expect(dbDNSRecordChanged.Record).To(Equal(test_data.DNSRecordChangedModel.Record))
+			Expect(dbDNSRecordChanged.LogIndex).To(Equal(test_data.DNSRecordChangedModel.LogIndex))
+			Expect(dbDNSRecordChanged.TransactionIndex).To(Equal(test_data.DNSRecordChangedModel.TransactionIndex))
+			Expect(dbDNSRecordChanged.Raw).To(MatchJSON(test_data.DNSRecordChangedModel.Raw))
+		})
+
+		It("persists the resource records of the RRset, replacing them when the log is rechecked", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = dnsRecordChangedRepository.Create(headerID, []interface{}{test_data.DNSRecordChangedModel})
+			Expect(err).NotTo(HaveOccurred())
+			err = dnsRecordChangedRepository.Create(headerID, []interface{}{test_data.DNSRecordChangedModel})
+			Expect(err).NotTo(HaveOccurred())
+
+			var records []dns_record_changed.ResourceRecordModel
+			err = db.Select(&records, `SELECT name, type, class, ttl, rdata FROM ens.dns_resource_records`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal(test_data.DNSRecordChangedModel.ResourceRecords))
+		})
+	})
+
+	Describe("DNS zones", func() {
+		var headerID int64
+
+		BeforeEach(func() {
+			var err error
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err = headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+			err = dnsRecordChangedRepository.Create(headerID, []interface{}{test_data.DNSRecordChangedModel})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("includes the resource records of the latest RRset of each name and type", func() {
+			var zone []dns_record_changed.ResourceRecordModel
+			err := db.Select(&zone, `SELECT name, type, class, ttl, rdata FROM ens.dns_zones WHERE node = $1`, test_data.DNSRecordChangedModel.Node)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zone).To(Equal(test_data.DNSRecordChangedModel.ResourceRecords))
+		})
+
+		It("leaves out RRsets that were deleted", func() {
+			deleted := test_data.DNSRecordDeletedModel
+			deleted.LogIndex = test_data.DNSRecordChangedModel.LogIndex + 1
+			_, err := db.Exec(`INSERT INTO ens.dns_record_deleted (header_id, resolver, node, name, resource, log_idx, tx_idx) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				headerID, deleted.Resolver, deleted.Node, deleted.Name, deleted.Resource, deleted.LogIndex, deleted.TransactionIndex)
+			Expect(err).NotTo(HaveOccurred())
+
+			var count int
+			err = db.Get(&count, `SELECT COUNT(*) FROM ens.dns_zones`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+
+		It("leaves out RRsets that were set before the node's latest version change", func() {
+			_, err := db.Exec(`INSERT INTO ens.version_changed (header_id, resolver, node, new_version, log_idx, tx_idx) VALUES ($1, $2, $3, 1, $4, $5)`,
+				headerID, test_data.DNSRecordChangedModel.Resolver, test_data.DNSRecordChangedModel.Node,
+				test_data.DNSRecordChangedModel.LogIndex+1, test_data.DNSRecordChangedModel.TransactionIndex)
+			Expect(err).NotTo(HaveOccurred())
+
+			var count int
+			err = db.Get(&count, `SELECT COUNT(*) FROM ens.dns_zones`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.DNSRecordChangedChecked,
+			Repository:              &dnsRecordChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
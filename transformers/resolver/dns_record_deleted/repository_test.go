@@ -0,0 +1,89 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_record_deleted_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_record_deleted"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("DNSRecordDeleted repository", func() {
+	var (
+		dnsRecordDeletedRepository dns_record_deleted.DNSRecordDeletedRepository
+		db                         *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		dnsRecordDeletedRepository = dns_record_deleted.DNSRecordDeletedRepository{}
+		dnsRecordDeletedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.DNSRecordDeletedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.DNSRecordDeletedChecked,
+			LogEventTableName:        "ens.dns_record_deleted",
+			TestModel:                test_data.DNSRecordDeletedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &dnsRecordDeletedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a dns_record_deleted record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = dnsRecordDeletedRepository.Create(headerID, []interface{}{test_data.DNSRecordDeletedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbDNSRecordDeleted dns_record_deleted.DNSRecordDeletedModel
+			err = db.Get(&dbDNSRecordDeleted, `SELECT resolver, node, name, resource, log_idx, tx_idx, raw_log FROM ens.dns_record_deleted WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbDNSRecordDeleted.Resolver).To(Equal(test_data.DNSRecordDeletedModel.Resolver))
+			Expect(dbDNSRecordDeleted.Node).To(Equal(test_data.DNSRecordDeletedModel.Node))
+			Expect(dbDNSRecordDeleted.Name).To(Equal(test_data.DNSRecordDeletedModel.Name))
+			Expect(dbDNSRecordDeleted.Resource).To(Equal(test_data.DNSRecordDeletedModel.Resource))
+			Expect(dbDNSRecordDeleted.LogIndex).To(Equal(test_data.DNSRecordDeletedModel.LogIndex))
+			Expect(dbDNSRecordDeleted.TransactionIndex).To(Equal(test_data.DNSRecordDeletedModel.TransactionIndex))
+			Expect(dbDNSRecordDeleted.Raw).To(MatchJSON(test_data.DNSRecordDeletedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.DNSRecordDeletedChecked,
+			Repository:              &dnsRecordDeletedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
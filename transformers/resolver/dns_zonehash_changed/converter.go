@@ -0,0 +1,86 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_zonehash_changed
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+)
+
+type DNSZonehashChangedConverter struct{}
+
+func (DNSZonehashChangedConverter) ToEntities(contractAbi string, ethLogs []types.Log) ([]interface{}, error) {
+	var entities []interface{}
+	for _, ethLog := range ethLogs {
+		entity := &DNSZonehashChangedEntity{}
+		entity.Resolver = ethLog.Address
+		abi, err := geth.ParseAbi(contractAbi)
+		if err != nil {
+			return nil, err
+		}
+
+		contract := bind.NewBoundContract(entity.Resolver, abi, nil, nil, nil)
+
+		err = contract.UnpackLog(entity, "DNSZonehashChanged", ethLog)
+		if err != nil {
+			return nil, err
+		}
+
+		entity.Raw = ethLog
+		entity.LogIndex = ethLog.Index
+		entity.TransactionIndex = ethLog.TxIndex
+
+		entities = append(entities, *entity)
+	}
+
+	return entities, nil
+}
+
+func (converter DNSZonehashChangedConverter) ToModels(entities []interface{}) ([]interface{}, error) {
+	var models []interface{}
+	for _, entity := range entities {
+		zonehashEntity, ok := entity.(DNSZonehashChangedEntity)
+		if !ok {
+			return nil, fmt.Errorf("entity of type %T, not %T", entity, DNSZonehashChangedEntity{})
+		}
+
+		logIdx := zonehashEntity.LogIndex
+		txIdx := zonehashEntity.TransactionIndex
+		rawLog, err := json.Marshal(zonehashEntity.Raw)
+		if err != nil {
+			return nil, err
+		}
+
+		model := DNSZonehashChangedModel{
+			Resolver:         zonehashEntity.Resolver.Hex(),
+			Node:             zonehashEntity.Node.Hex(),
+			LastZonehash:     hexutil.Encode(zonehashEntity.LastZonehash),
+			Zonehash:         hexutil.Encode(zonehashEntity.Zonehash),
+			LogIndex:         logIdx,
+			TransactionIndex: txIdx,
+			Raw:              rawLog,
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
@@ -0,0 +1,89 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_zonehash_changed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_zonehash_changed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("DNSZonehashChanged repository", func() {
+	var (
+		dnsZonehashChangedRepository dns_zonehash_changed.DNSZonehashChangedRepository
+		db                           *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		dnsZonehashChangedRepository = dns_zonehash_changed.DNSZonehashChangedRepository{}
+		dnsZonehashChangedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.DNSZonehashChangedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.DNSZonehashChangedChecked,
+			LogEventTableName:        "ens.dns_zonehash_changed",
+			TestModel:                test_data.DNSZonehashChangedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &dnsZonehashChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a dns_zonehash_changed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = dnsZonehashChangedRepository.Create(headerID, []interface{}{test_data.DNSZonehashChangedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbDNSZonehashChanged dns_zonehash_changed.DNSZonehashChangedModel
+			err = db.Get(&dbDNSZonehashChanged, `SELECT resolver, node, last_zonehash, zonehash, log_idx, tx_idx, raw_log FROM ens.dns_zonehash_changed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbDNSZonehashChanged.Resolver).To(Equal(test_data.DNSZonehashChangedModel.Resolver))
+			Expect(dbDNSZonehashChanged.Node).To(Equal(test_data.DNSZonehashChangedModel.Node))
+			Expect(dbDNSZonehashChanged.LastZonehash).To(Equal(test_data.DNSZonehashChangedModel.LastZonehash))
+			Expect(dbDNSZonehashChanged.Zonehash).To(Equal(test_data.DNSZonehashChangedModel.Zonehash))
+			Expect(dbDNSZonehashChanged.LogIndex).To(Equal(test_data.DNSZonehashChangedModel.LogIndex))
+			Expect(dbDNSZonehashChanged.TransactionIndex).To(Equal(test_data.DNSZonehashChangedModel.TransactionIndex))
+			Expect(dbDNSZonehashChanged.Raw).To(MatchJSON(test_data.DNSZonehashChangedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.DNSZonehashChangedChecked,
+			Repository:              &dnsZonehashChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
@@ -0,0 +1,89 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package interface_changed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/interface_changed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("InterfaceChanged repository", func() {
+	var (
+		interfaceChangedRepository interface_changed.InterfaceChangedRepository
+		db                         *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		interfaceChangedRepository = interface_changed.InterfaceChangedRepository{}
+		interfaceChangedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.InterfaceChangedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.InterfaceChangedChecked,
+			LogEventTableName:        "ens.interface_changed",
+			TestModel:                test_data.InterfaceChangedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &interfaceChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists an interface_changed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = interfaceChangedRepository.Create(headerID, []interface{}{test_data.InterfaceChangedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbInterfaceChanged interface_changed.InterfaceChangedModel
+			err = db.Get(&dbInterfaceChanged, `SELECT resolver, node, interface_id, implementer, log_idx, tx_idx, raw_log FROM ens.interface_changed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbInterfaceChanged.Resolver).To(Equal(test_data.InterfaceChangedModel.Resolver))
+			Expect(dbInterfaceChanged.Node).To(Equal(test_data.InterfaceChangedModel.Node))
+			Expect(dbInterfaceChanged.InterfaceID).To(Equal(test_data.InterfaceChangedModel.InterfaceID))
+			Expect(dbInterfaceChanged.Implementer).To(Equal(test_data.InterfaceChangedModel.Implementer))
+			Expect(dbInterfaceChanged.LogIndex).To(Equal(test_data.InterfaceChangedModel.LogIndex))
+			Expect(dbInterfaceChanged.TransactionIndex).To(Equal(test_data.InterfaceChangedModel.TransactionIndex))
+			Expect(dbInterfaceChanged.Raw).To(MatchJSON(test_data.InterfaceChangedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.InterfaceChangedChecked,
+			Repository:              &interfaceChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
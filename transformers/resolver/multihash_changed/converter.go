@@ -22,8 +22,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/contenthash"
 )
 
 type MultihashChangedConverter struct{}
@@ -70,10 +73,17 @@ func (converter MultihashChangedConverter) ToModels(entities []interface{}) ([]i
 			return nil, err
 		}
 
+		// A multihash we can't decode is still persisted with its raw value
+		protocol, uri, err := contenthash.DecodeMultihash(multiEntity.Hash)
+		if err != nil {
+			log.Warnf("unable to decode multihash %x on node %s: %v", multiEntity.Hash, multiEntity.Node.Hex(), err)
+		}
 		model := MultihashChangedModel{
 			Resolver:         multiEntity.Resolver.Hex(),
 			Node:             multiEntity.Node.Hex(),
 			Hash:             multiEntity.Hash,
+			Protocol:         protocol,
+			URI:              uri,
 			LogIndex:         logIdx,
 			TransactionIndex: txIdx,
 			Raw:              rawLog,
@@ -52,10 +52,10 @@ func (repository MultihashChangedRepository) Create(headerID int64, models []int
 		}
 
 		_, execErr := tx.Exec(
-			`INSERT into ens.multihash_changed (header_id, resolver, node, hash, log_idx, tx_idx, raw_log)
-        			VALUES($1, $2, $3, $4, $5, $6, $7)
-					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET resolver = $2, node = $3, hash = $4, raw_log = $7;`,
-			headerID, multiModel.Resolver, multiModel.Node, multiModel.Hash, multiModel.LogIndex, multiModel.TransactionIndex, multiModel.Raw,
+			`INSERT into ens.multihash_changed (header_id, resolver, node, hash, content_protocol, content_uri, log_idx, tx_idx, raw_log)
+        			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET resolver = $2, node = $3, hash = $4, content_protocol = $5, content_uri = $6, raw_log = $9;`,
+			headerID, multiModel.Resolver, multiModel.Node, multiModel.Hash, multiModel.Protocol, multiModel.URI, multiModel.LogIndex, multiModel.TransactionIndex, multiModel.Raw,
 		)
 		if execErr != nil {
 			rollbackErr := tx.Rollback()
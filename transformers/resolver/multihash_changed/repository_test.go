@@ -66,11 +66,13 @@ var _ = Describe("MultihashChanged repository", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			var dbMultihashChanged multihash_changed.MultihashChangedModel
-			err = db.Get(&dbMultihashChanged, `SELECT resolver, node, hash, log_idx, tx_idx, raw_log FROM ens.multihash_changed WHERE header_id = $1`, headerID)
+			err = db.Get(&dbMultihashChanged, `SELECT resolver, node, hash, content_protocol, content_uri, log_idx, tx_idx, raw_log FROM ens.multihash_changed WHERE header_id = $1`, headerID)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(dbMultihashChanged.Resolver).To(Equal(test_data.MultihashChangedModel.Resolver))
 			Expect(dbMultihashChanged.Node).To(Equal(test_data.MultihashChangedModel.Node))
 			Expect(dbMultihashChanged.Hash).To(Equal(test_data.MultihashChangedModel.Hash))
+			Expect(dbMultihashChanged.Protocol).To(Equal(test_data.MultihashChangedModel.Protocol))
+			Expect(dbMultihashChanged.URI).To(Equal(test_data.MultihashChangedModel.URI))
 			Expect(dbMultihashChanged.LogIndex).To(Equal(test_data.MultihashChangedModel.LogIndex))
 			Expect(dbMultihashChanged.TransactionIndex).To(Equal(test_data.MultihashChangedModel.TransactionIndex))
 			Expect(dbMultihashChanged.Raw).To(MatchJSON(test_data.MultihashChangedModel.Raw))
@@ -0,0 +1,98 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package version_changed
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+
+	repo "github.com/vulcanize/vulcanizedb/libraries/shared/repository"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+)
+
+type VersionChangedRepository struct {
+	db *postgres.DB
+}
+
+func (repository *VersionChangedRepository) SetDB(db *postgres.DB) {
+	repository.db = db
+}
+
+func (repository VersionChangedRepository) Create(headerID int64, models []interface{}) error {
+	tx, dBaseErr := repository.db.Beginx()
+	if dBaseErr != nil {
+		return dBaseErr
+	}
+	execErr := CreateInTransaction(tx, headerID, models)
+	if execErr != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return execErr
+	}
+
+	checkHeaderErr := repo.MarkHeaderCheckedInTransaction(headerID, tx, constants.VersionChangedChecked)
+	if checkHeaderErr != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			log.Error("failed to rollback ", rollbackErr)
+		}
+		return checkHeaderErr
+	}
+
+	return tx.Commit()
+}
+
+// Persists the models within the given transaction, without marking the header checked
+func CreateInTransaction(tx *sqlx.Tx, headerID int64, models []interface{}) error {
+	for _, model := range models {
+		versionModel, ok := model.(VersionChangedModel)
+		if !ok {
+			return fmt.Errorf("model of type %T, not %T", model, VersionChangedModel{})
+		}
+
+		_, execErr := tx.Exec(
+			`INSERT into ens.version_changed (header_id, resolver, node, new_version, log_idx, tx_idx, raw_log)
+        			VALUES($1, $2, $3, $4, $5, $6, $7)
+					ON CONFLICT (header_id, tx_idx, log_idx) DO UPDATE SET resolver = $2, node = $3, new_version = $4, raw_log = $7;`,
+			headerID, versionModel.Resolver, versionModel.Node, versionModel.NewVersion, versionModel.LogIndex, versionModel.TransactionIndex, versionModel.Raw,
+		)
+		if execErr != nil {
+			return execErr
+		}
+	}
+
+	return nil
+}
+
+func (repository VersionChangedRepository) MarkHeaderChecked(headerID int64) error {
+	return repo.MarkHeaderChecked(headerID, repository.db, constants.VersionChangedChecked)
+}
+
+func (repository VersionChangedRepository) MissingHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.MissingHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.VersionChangedChecked)
+}
+
+func (repository VersionChangedRepository) RecheckHeaders(startingBlockNumber int64, endingBlockNumber int64) ([]core.Header, error) {
+	return repo.RecheckHeaders(startingBlockNumber, endingBlockNumber, repository.db, constants.VersionChangedChecked)
+}
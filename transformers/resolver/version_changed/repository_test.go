@@ -0,0 +1,88 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package version_changed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/resolver/version_changed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("VersionChanged repository", func() {
+	var (
+		versionChangedRepository version_changed.VersionChangedRepository
+		db                       *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		versionChangedRepository = version_changed.VersionChangedRepository{}
+		versionChangedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.VersionChangedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.VersionChangedChecked,
+			LogEventTableName:        "ens.version_changed",
+			TestModel:                test_data.VersionChangedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &versionChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a version_changed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = versionChangedRepository.Create(headerID, []interface{}{test_data.VersionChangedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbVersionChanged version_changed.VersionChangedModel
+			err = db.Get(&dbVersionChanged, `SELECT resolver, node, new_version, log_idx, tx_idx, raw_log FROM ens.version_changed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbVersionChanged.Resolver).To(Equal(test_data.VersionChangedModel.Resolver))
+			Expect(dbVersionChanged.Node).To(Equal(test_data.VersionChangedModel.Node))
+			Expect(dbVersionChanged.NewVersion).To(Equal(test_data.VersionChangedModel.NewVersion))
+			Expect(dbVersionChanged.LogIndex).To(Equal(test_data.VersionChangedModel.LogIndex))
+			Expect(dbVersionChanged.TransactionIndex).To(Equal(test_data.VersionChangedModel.TransactionIndex))
+			Expect(dbVersionChanged.Raw).To(MatchJSON(test_data.VersionChangedModel.Raw))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.VersionChangedChecked,
+			Repository:              &versionChangedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
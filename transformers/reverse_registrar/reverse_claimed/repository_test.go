@@ -0,0 +1,112 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package reverse_claimed_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/test_config"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	ensRepo "github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/ens_transformers/transformers/reverse_registrar/reverse_claimed"
+	"github.com/vulcanize/ens_transformers/transformers/shared/constants"
+	"github.com/vulcanize/ens_transformers/transformers/test_data"
+	"github.com/vulcanize/ens_transformers/transformers/test_data/shared_behaviors"
+)
+
+var _ = Describe("ReverseClaimed repository", func() {
+	var (
+		reverseClaimedRepository reverse_claimed.ReverseClaimedRepository
+		db                       *postgres.DB
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		reverseClaimedRepository = reverse_claimed.ReverseClaimedRepository{}
+		reverseClaimedRepository.SetDB(db)
+	})
+
+	Describe("Create", func() {
+		modelWithDifferentLogIdx := test_data.ReverseClaimedModel
+		modelWithDifferentLogIdx.LogIndex++
+		inputs := shared_behaviors.CreateBehaviorInputs{
+			CheckedHeaderColumnName:  constants.ReverseClaimedChecked,
+			LogEventTableName:        "ens.reverse_claimed",
+			TestModel:                test_data.ReverseClaimedModel,
+			ModelWithDifferentLogIdx: modelWithDifferentLogIdx,
+			Repository:               &reverseClaimedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryCreateBehaviors(&inputs)
+
+		It("persists a reverse_claimed record", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = reverseClaimedRepository.Create(headerID, []interface{}{test_data.ReverseClaimedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			var dbReverseClaimed reverse_claimed.ReverseClaimedModel
+			err = db.Get(&dbReverseClaimed, `SELECT addr, node, log_idx, tx_idx, raw_log FROM ens.reverse_claimed WHERE header_id = $1`, headerID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbReverseClaimed.Addr).To(Equal(test_data.ReverseClaimedModel.Addr))
+			Expect(dbReverseClaimed.Node).To(Equal(test_data.ReverseClaimedModel.Node))
+			Expect(dbReverseClaimed.LogIndex).To(Equal(test_data.ReverseClaimedModel.LogIndex))
+			Expect(dbReverseClaimed.TransactionIndex).To(Equal(test_data.ReverseClaimedModel.TransactionIndex))
+			Expect(dbReverseClaimed.Raw).To(MatchJSON(test_data.ReverseClaimedModel.Raw))
+		})
+
+		It("records the claim as a reverse record and the address as the label of its reverse node", func() {
+			headerRepository := repositories.NewHeaderRepository(db)
+			headerID, err := headerRepository.CreateOrUpdateHeader(fakes.FakeHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = reverseClaimedRepository.Create(headerID, []interface{}{test_data.ReverseClaimedModel})
+
+			Expect(err).NotTo(HaveOccurred())
+			records, err := ensRepo.NewReverseRepository(db).GetReverseRecords(test_data.ReverseClaimedModel.Addr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([]models.ReverseRecordModel{{
+				BlockNumber: fakes.FakeHeader.BlockNumber,
+				HeaderID:    headerID,
+				Address:     test_data.ReverseClaimedModel.Addr,
+				Node:        test_data.ReverseClaimedModel.Node,
+				Source:      models.ReverseSourceRegistrar,
+			}}))
+			label, err := ensRepo.NewLabelRepository(db).GetLabel(utils.LabelHash(utils.ReverseLabel(test_data.ReverseClaimedModel.Addr)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(label).To(Equal("ffd1ac3e8818adcbe5c597ea076e8d3210b45df5"))
+		})
+	})
+
+	Describe("MarkHeaderChecked", func() {
+		inputs := shared_behaviors.MarkedHeaderCheckedBehaviorInputs{
+			CheckedHeaderColumnName: constants.ReverseClaimedChecked,
+			Repository:              &reverseClaimedRepository,
+		}
+
+		shared_behaviors.SharedRepositoryMarkHeaderCheckedBehaviors(&inputs)
+	})
+})
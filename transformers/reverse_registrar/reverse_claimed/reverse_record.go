@@ -0,0 +1,46 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package reverse_claimed
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/models"
+	ensRepo "github.com/vulcanize/ens_transformers/transformers/domain_records/repository"
+	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+)
+
+// The registrar emits both the claiming address and the node it claimed, so the address's hex label is a reliable preimage
+// Nodes that aren't the address's reverse node are still recorded as claimed, but don't seed a label
+func createReverseRecord(tx *sqlx.Tx, headerID int64, model ReverseClaimedModel) error {
+	err := ensRepo.CreateReverseRecordInTransaction(tx, models.ReverseRecordModel{
+		HeaderID: headerID,
+		Address:  model.Addr,
+		Node:     model.Node,
+		Source:   models.ReverseSourceRegistrar,
+	})
+	if err != nil || utils.ReverseNodeOf(model.Addr) != model.Node {
+		return err
+	}
+	_, err = ensRepo.CreateLabelInTransaction(tx, models.LabelModel{
+		LabelHash: utils.LabelHash(utils.ReverseLabel(model.Addr)),
+		Label:     utils.ReverseLabel(model.Addr),
+		Source:    models.ReverseSourceRegistrar,
+	})
+
+	return err
+}
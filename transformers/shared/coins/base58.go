@@ -0,0 +1,56 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package coins
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Encodes the version byte and payload with a double-sha256 checksum, as in bitcoin's legacy addresses
+func base58CheckEncode(version byte, payload []byte) string {
+	data := append([]byte{version}, payload...)
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+
+	return base58Encode(append(data, second[:4]...))
+}
+
+func base58Encode(data []byte) string {
+	var encoded []byte
+	value := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	for value.Sign() > 0 {
+		value.DivMod(value, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	// Leading zero bytes are encoded as leading 1s
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	return string(encoded)
+}
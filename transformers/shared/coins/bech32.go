@@ -0,0 +1,110 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package coins
+
+import (
+	"fmt"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Checksum constants of bech32 (BIP-173), used for version 0 witness programs, and bech32m (BIP-350), used for every later version
+const (
+	bech32Constant  = 1
+	bech32mConstant = 0x2bc830a3
+)
+
+// Encodes a segwit witness program into an address with the given human-readable prefix
+func segwitEncode(prefix string, version byte, program []byte) (string, error) {
+	if len(program) < 2 || len(program) > 40 || (version == 0 && len(program) != 20 && len(program) != 32) {
+		return "", fmt.Errorf("invalid witness program of length %d for version %d", len(program), version)
+	}
+	data := append([]byte{version}, convertBits(program, 8, 5)...)
+	checksumConstant := uint32(bech32mConstant)
+	if version == 0 {
+		checksumConstant = bech32Constant
+	}
+
+	return bech32Encode(prefix, data, checksumConstant), nil
+}
+
+func bech32Encode(prefix string, data []byte, checksumConstant uint32) string {
+	values := append(prefixExpand(prefix), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ checksumConstant
+	var encoded strings.Builder
+	encoded.WriteString(prefix)
+	encoded.WriteByte('1')
+	for _, d := range data {
+		encoded.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		encoded.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+
+	return encoded.String()
+}
+
+func prefixExpand(prefix string) []byte {
+	expanded := make([]byte, 0, len(prefix)*2+1)
+	for i := 0; i < len(prefix); i++ {
+		expanded = append(expanded, prefix[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(prefix); i++ {
+		expanded = append(expanded, prefix[i]&31)
+	}
+
+	return expanded
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+// Regroups bits from groups of fromBits into groups of toBits, padding the final group with zeros
+func convertBits(data []byte, fromBits, toBits uint) []byte {
+	var acc uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+	converted := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, b := range data {
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			converted = append(converted, byte(acc>>bits&maxValue))
+		}
+	}
+	if bits > 0 {
+		converted = append(converted, byte(acc<<(toBits-bits)&maxValue))
+	}
+
+	return converted
+}
@@ -0,0 +1,101 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package coins
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SLIP-44 coin types with native address encodings
+const (
+	Bitcoin      uint64 = 0
+	Litecoin     uint64 = 2
+	Dogecoin     uint64 = 3
+	Ether        uint64 = 60
+	EtherClassic uint64 = 61
+)
+
+// Address formats of the bitcoin-like coins, whose addresses are stored by EIP-2304 resolvers as their scriptPubkey
+type bitcoinFormat struct {
+	p2pkhVersion byte
+	p2shVersion  byte
+	segwitPrefix string // Empty if the coin has no segwit addresses
+}
+
+var bitcoinFormats = map[uint64]bitcoinFormat{
+	Bitcoin:  {p2pkhVersion: 0x00, p2shVersion: 0x05, segwitPrefix: "bc"},
+	Litecoin: {p2pkhVersion: 0x30, p2shVersion: 0x32, segwitPrefix: "ltc"},
+	Dogecoin: {p2pkhVersion: 0x1e, p2shVersion: 0x16},
+}
+
+var ErrUnsupportedScript = errors.New("unsupported scriptPubkey")
+
+// Encodes the binary address an EIP-2304 resolver stores for a coin into the coin's native text format
+// Bitcoin-like addresses are encoded with base58check or bech32 depending on their script, and ether-like addresses are EIP-55 checksummed
+// Addresses of coin types without a known encoding are returned as hex
+func EncodeAddress(coinType uint64, address []byte) (string, error) {
+	if len(address) == 0 {
+		return "", nil
+	}
+	if format, ok := bitcoinFormats[coinType]; ok {
+		return format.encode(address)
+	}
+	switch coinType {
+	case Ether, EtherClassic:
+		if len(address) != common.AddressLength {
+			return "", fmt.Errorf("address of coin type %d is %d bytes, not %d", coinType, len(address), common.AddressLength)
+		}
+		return common.BytesToAddress(address).Hex(), nil
+	}
+
+	return hexutil.Encode(address), nil
+}
+
+func (f bitcoinFormat) encode(script []byte) (string, error) {
+	switch {
+	// OP_DUP OP_HASH160 <20 byte pubkey hash> OP_EQUALVERIFY OP_CHECKSIG
+	case len(script) == 25 && script[0] == 0x76 && script[1] == 0xa9 && script[2] == 0x14 && script[23] == 0x88 && script[24] == 0xac:
+		return base58CheckEncode(f.p2pkhVersion, script[3:23]), nil
+	// OP_HASH160 <20 byte script hash> OP_EQUAL
+	case len(script) == 23 && script[0] == 0xa9 && script[1] == 0x14 && script[22] == 0x87:
+		return base58CheckEncode(f.p2shVersion, script[2:22]), nil
+	// <witness version> <2 to 40 byte witness program>
+	case f.segwitPrefix != "" && len(script) >= 4 && len(script) <= 42 && int(script[1]) == len(script)-2:
+		version, ok := witnessVersion(script[0])
+		if !ok {
+			return "", ErrUnsupportedScript
+		}
+		return segwitEncode(f.segwitPrefix, version, script[2:])
+	}
+
+	return "", ErrUnsupportedScript
+}
+
+// Witness versions are pushed with OP_0 or OP_1 through OP_16
+func witnessVersion(opcode byte) (byte, bool) {
+	if opcode == 0x00 {
+		return 0, true
+	}
+	if opcode >= 0x51 && opcode <= 0x60 {
+		return opcode - 0x50, true
+	}
+	return 0, false
+}
@@ -0,0 +1,62 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package coins_test
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/coins"
+)
+
+var _ = Describe("EncodeAddress", func() {
+	DescribeTable("encodes binary addresses into their native format",
+		func(coinType uint64, address, expected string) {
+			encoded, err := coins.EncodeAddress(coinType, hexutil.MustDecode(address))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encoded).To(Equal(expected))
+		},
+		Entry("bitcoin P2PKH", coins.Bitcoin, "0x76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"),
+		Entry("bitcoin P2SH", coins.Bitcoin, "0xa91462e907b15cbf27d5425399ebf6f0fb50ebb88f1887", "3Ai1JZ8pdJb2ksieUV8FsxSNVJCpoPi8W6"),
+		Entry("bitcoin segwit v0", coins.Bitcoin, "0x0014751e76e8199196d454941c45d1b3a323f1433bd6", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"),
+		Entry("bitcoin segwit v1", coins.Bitcoin, "0x5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6",
+			"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y"),
+		Entry("litecoin P2PKH", coins.Litecoin, "0x76a914a5f4d12ce3685781b227c1f39548ddef429e978388ac", "LaMT348PWRnrqeeWArpwQPbuanpXDZGEUz"),
+		Entry("litecoin segwit", coins.Litecoin, "0x0014751e76e8199196d454941c45d1b3a323f1433bd6", "ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n9"),
+		Entry("dogecoin P2PKH", coins.Dogecoin, "0x76a9144620b70031f0e9437e374a2100934fba4911046088ac", "DBXu2kgc3xtvCUWFcxFE3r9hEYgmuaaCyD"),
+		Entry("ether", coins.Ether, "0x314159265dd8dbb310642f98f50c066173c1259b", "0x314159265dD8dbb310642f98f50C066173C1259b"),
+		Entry("unknown coin types as hex", uint64(144), "0x0102", "0x0102"),
+		Entry("cleared addresses as empty", coins.Bitcoin, "0x", ""),
+	)
+
+	It("returns an error for scripts without an address format", func() {
+		_, err := coins.EncodeAddress(coins.Bitcoin, hexutil.MustDecode("0x6a0401020304"))
+		Expect(err).To(Equal(coins.ErrUnsupportedScript))
+	})
+
+	It("returns an error for segwit scripts of coins without segwit addresses", func() {
+		_, err := coins.EncodeAddress(coins.Dogecoin, hexutil.MustDecode("0x0014751e76e8199196d454941c45d1b3a323f1433bd6"))
+		Expect(err).To(Equal(coins.ErrUnsupportedScript))
+	})
+
+	It("returns an error for ether addresses of the wrong length", func() {
+		_, err := coins.EncodeAddress(coins.Ether, hexutil.MustDecode("0x3141"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -25,6 +25,23 @@ const (
 	HashReleasedChecked    = "hash_released_checked"
 	NewBidChecked          = "new_bid_checked"
 
+	// BaseRegistrar
+	NameMigratedChecked      = "name_migrated_checked"
+	NameRegisteredChecked    = "name_registered_checked"
+	NameRenewedChecked       = "name_renewed_checked"
+	ControllerAddedChecked   = "controller_added_checked"
+	ControllerRemovedChecked = "controller_removed_checked"
+	RegistrarTransferChecked = "registrar_transfer_checked"
+	RegistrarApprovalChecked = "registrar_approval_checked"
+
+	// ETHRegistrarController
+	ControllerNameRegisteredChecked = "controller_name_registered_checked"
+	ControllerNameRenewedChecked    = "controller_name_renewed_checked"
+	NewPriceOracleChecked           = "new_price_oracle_checked"
+
+	// ReverseRegistrar
+	ReverseClaimedChecked = "reverse_claimed_checked"
+
 	// Registry
 	NewOwnerChecked    = "new_owner_checked"
 	NewResolverChecked = "new_resolver_checked"
@@ -32,12 +49,19 @@ const (
 	TransferChecked    = "ens_transfer_checked"
 
 	// Resolver
-	AbiChangedChecked         = "abi_changed_checked"
-	AddrChangedChecked        = "addr_changed_checked"
-	ContentChangedChecked     = "content_changed_checked"
-	ContenthashChangedChecked = "contenthash_changed_checked"
-	MultihashChangedChecked   = "multihash_changed_checked"
-	NameChangedChecked        = "name_changed_checked"
-	PubkeyChangedChecked      = "pubkey_changed_checked"
-	TextChangedChecked        = "text_changed_checked"
+	AbiChangedChecked           = "abi_changed_checked"
+	AddrChangedChecked          = "addr_changed_checked"
+	AddressChangedChecked       = "address_changed_checked"
+	AuthorisationChangedChecked = "authorisation_changed_checked"
+	ContentChangedChecked       = "content_changed_checked"
+	ContenthashChangedChecked   = "contenthash_changed_checked"
+	DNSRecordChangedChecked     = "dns_record_changed_checked"
+	DNSRecordDeletedChecked     = "dns_record_deleted_checked"
+	DNSZonehashChangedChecked   = "dns_zonehash_changed_checked"
+	InterfaceChangedChecked     = "interface_changed_checked"
+	MultihashChangedChecked     = "multihash_changed_checked"
+	NameChangedChecked          = "name_changed_checked"
+	PubkeyChangedChecked        = "pubkey_changed_checked"
+	TextChangedChecked          = "text_changed_checked"
+	VersionChangedChecked       = "version_changed_checked"
 )
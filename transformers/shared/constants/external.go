@@ -57,12 +57,27 @@ func getEnvironmentInt64(key string) int64 {
 
 // Getters for contract addresses from environment files
 func RegistryContractAddress() string { return getEnvironmentString("contract.address.registry") }
+func RegistryWithFallbackContractAddress() string {
+	return getEnvironmentString("contract.address.registry_with_fallback")
+}
 func RegistarContractAddress() string { return getEnvironmentString("contract.address.registar") }
 func ResolverContractAddress() string { return getEnvironmentString("contract.address.resolver") }
+func BaseRegistrarContractAddress() string {
+	return getEnvironmentString("contract.address.base_registrar")
+}
+func ControllerContractAddress() string { return getEnvironmentString("contract.address.controller") }
+func ReverseRegistrarContractAddress() string {
+	return getEnvironmentString("contract.address.reverse_registrar")
+}
 
-func RegistryABI() string { return getEnvironmentString("contract.abi.registry") }
-func RegistarABI() string { return getEnvironmentString("contract.abi.registar") }
-func ResolverABI() string { return getEnvironmentString("contract.abi.resolver") }
+func RegistryABI() string      { return getEnvironmentString("contract.abi.registry") }
+func RegistarABI() string      { return getEnvironmentString("contract.abi.registar") }
+func ResolverABI() string      { return getEnvironmentString("contract.abi.resolver") }
+func BaseRegistrarABI() string { return getEnvironmentString("contract.abi.base_registrar") }
+func ControllerABI() string    { return getEnvironmentString("contract.abi.controller") }
+func ReverseRegistrarABI() string {
+	return getEnvironmentString("contract.abi.reverse_registrar")
+}
 
 func RegistryDeploymentBlock() int64 {
 	return getEnvironmentInt64("contract.deployment-block.registry")
@@ -73,3 +88,12 @@ func RegistarDeploymentBlock() int64 {
 func ResolverDeploymentBlock() int64 {
 	return getEnvironmentInt64("contract.deployment-block.resolver")
 }
+func BaseRegistrarDeploymentBlock() int64 {
+	return getEnvironmentInt64("contract.deployment-block.base_registrar")
+}
+func ControllerDeploymentBlock() int64 {
+	return getEnvironmentInt64("contract.deployment-block.controller")
+}
+func ReverseRegistrarDeploymentBlock() int64 {
+	return getEnvironmentInt64("contract.deployment-block.reverse_registrar")
+}
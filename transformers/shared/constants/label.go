@@ -25,6 +25,23 @@ const (
 	HashReleasedLabel    = "hashReleased"
 	NewBidLabel          = "newBid"
 
+	// BaseRegistrar
+	NameMigratedLabel      = "nameMigrated"
+	NameRegisteredLabel    = "nameRegistered"
+	NameRenewedLabel       = "nameRenewed"
+	ControllerAddedLabel   = "controllerAdded"
+	ControllerRemovedLabel = "controllerRemoved"
+	RegistrarTransferLabel = "registrarTransfer"
+	RegistrarApprovalLabel = "registrarApproval"
+
+	// ETHRegistrarController
+	ControllerNameRegisteredLabel = "controllerNameRegistered"
+	ControllerNameRenewedLabel    = "controllerNameRenewed"
+	NewPriceOracleLabel           = "newPriceOracle"
+
+	// ReverseRegistrar
+	ReverseClaimedLabel = "reverseClaimed"
+
 	// Registry
 	NewOwnerLabel    = "newOwner"
 	NewResolverLabel = "newResolver"
@@ -32,12 +49,19 @@ const (
 	TransferLabel    = "transfer"
 
 	// Resolver
-	AbiChangedLabel         = "abiChanged"
-	AddrChangedLabel        = "addrChanged"
-	ContentChangedLabel     = "contentChanged"
-	ContenthashChangedLabel = "contenthashChanged"
-	MultihashChangedLabel   = "multihashChanged"
-	NameChangedLabel        = "nameChanged"
-	PubkeyChangedLabel      = "pubkeyChanged"
-	TextChangedLabel        = "textChanged"
+	AbiChangedLabel           = "abiChanged"
+	AddrChangedLabel          = "addrChanged"
+	AddressChangedLabel       = "addressChanged"
+	AuthorisationChangedLabel = "authorisationChanged"
+	ContentChangedLabel       = "contentChanged"
+	ContenthashChangedLabel   = "contenthashChanged"
+	DNSRecordChangedLabel     = "dnsRecordChanged"
+	DNSRecordDeletedLabel     = "dnsRecordDeleted"
+	DNSZonehashChangedLabel   = "dnsZonehashChanged"
+	InterfaceChangedLabel     = "interfaceChanged"
+	MultihashChangedLabel     = "multihashChanged"
+	NameChangedLabel          = "nameChanged"
+	PubkeyChangedLabel        = "pubkeyChanged"
+	TextChangedLabel          = "textChanged"
+	VersionChangedLabel       = "versionChanged"
 )
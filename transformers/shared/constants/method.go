@@ -26,6 +26,41 @@ func hashRegisteredMethod() string { return GetSolidityMethodSignature(RegistarA
 func hashReleasedMethod() string   { return GetSolidityMethodSignature(RegistarABI(), "HashReleased") }
 func newBidMethod() string         { return GetSolidityMethodSignature(RegistarABI(), "NewBid") }
 
+// BaseRegistrar
+func nameMigratedMethod() string {
+	return GetSolidityMethodSignature(BaseRegistrarABI(), "NameMigrated")
+}
+func nameRegisteredMethod() string {
+	return GetSolidityMethodSignature(BaseRegistrarABI(), "NameRegistered")
+}
+func nameRenewedMethod() string { return GetSolidityMethodSignature(BaseRegistrarABI(), "NameRenewed") }
+func controllerAddedMethod() string {
+	return GetSolidityMethodSignature(BaseRegistrarABI(), "ControllerAdded")
+}
+func controllerRemovedMethod() string {
+	return GetSolidityMethodSignature(BaseRegistrarABI(), "ControllerRemoved")
+}
+func registrarTransferMethod() string {
+	return GetSolidityMethodSignature(BaseRegistrarABI(), "Transfer")
+}
+func registrarApprovalMethod() string {
+	return GetSolidityMethodSignature(BaseRegistrarABI(), "Approval")
+}
+
+// ETHRegistrarController
+func controllerNameRegisteredMethod() string {
+	return GetSolidityMethodSignature(ControllerABI(), "NameRegistered")
+}
+func controllerNameRenewedMethod() string {
+	return GetSolidityMethodSignature(ControllerABI(), "NameRenewed")
+}
+func newPriceOracleMethod() string { return GetSolidityMethodSignature(ControllerABI(), "NewPriceOracle") }
+
+// ReverseRegistrar
+func reverseClaimedMethod() string {
+	return GetSolidityMethodSignature(ReverseRegistrarABI(), "ReverseClaimed")
+}
+
 // Registry
 func newOwnerMethod() string    { return GetSolidityMethodSignature(RegistryABI(), "NewOwner") }
 func newResolverMethod() string { return GetSolidityMethodSignature(RegistryABI(), "NewResolver") }
@@ -35,13 +70,34 @@ func transferMethod() string    { return GetSolidityMethodSignature(RegistryABI(
 // Resolver
 func abiChangedMethod() string     { return GetSolidityMethodSignature(ResolverABI(), "ABIChanged") }
 func addrChangedMethod() string    { return GetSolidityMethodSignature(ResolverABI(), "AddrChanged") }
+func addressChangedMethod() string {
+	return GetSolidityMethodSignature(ResolverABI(), "AddressChanged")
+}
+func authorisationChangedMethod() string {
+	return GetSolidityMethodSignature(ResolverABI(), "AuthorisationChanged")
+}
 func contentChangedMethod() string { return GetSolidityMethodSignature(ResolverABI(), "ContentChanged") }
 func contenthashChangedMethod() string {
 	return GetSolidityMethodSignature(ResolverABI(), "ContenthashChanged")
 }
+func dnsRecordChangedMethod() string {
+	return GetSolidityMethodSignature(ResolverABI(), "DNSRecordChanged")
+}
+func dnsRecordDeletedMethod() string {
+	return GetSolidityMethodSignature(ResolverABI(), "DNSRecordDeleted")
+}
+func dnsZonehashChangedMethod() string {
+	return GetSolidityMethodSignature(ResolverABI(), "DNSZonehashChanged")
+}
+func interfaceChangedMethod() string {
+	return GetSolidityMethodSignature(ResolverABI(), "InterfaceChanged")
+}
 func multihashChangedMethod() string {
 	return GetSolidityMethodSignature(ResolverABI(), "MultihashChanged")
 }
 func nameChangedMethod() string   { return GetSolidityMethodSignature(ResolverABI(), "NameChanged") }
 func pubkeyChangedMethod() string { return GetSolidityMethodSignature(ResolverABI(), "PubkeyChanged") }
 func textChangedMethod() string   { return GetSolidityMethodSignature(ResolverABI(), "TextChanged") }
+func versionChangedMethod() string {
+	return GetSolidityMethodSignature(ResolverABI(), "VersionChanged")
+}
@@ -24,6 +24,27 @@ func GetHashRegisteredSignature() string  { return GetEventSignature(hashRegiste
 func GetHashReleasedSignature() string    { return GetEventSignature(hashReleasedMethod()) }
 func GetNewBidSignature() string          { return GetEventSignature(newBidMethod()) }
 
+// BaseRegistrar
+func GetNameMigratedSignature() string      { return GetEventSignature(nameMigratedMethod()) }
+func GetNameRegisteredSignature() string    { return GetEventSignature(nameRegisteredMethod()) }
+func GetNameRenewedSignature() string       { return GetEventSignature(nameRenewedMethod()) }
+func GetControllerAddedSignature() string   { return GetEventSignature(controllerAddedMethod()) }
+func GetControllerRemovedSignature() string { return GetEventSignature(controllerRemovedMethod()) }
+func GetRegistrarTransferSignature() string { return GetEventSignature(registrarTransferMethod()) }
+func GetRegistrarApprovalSignature() string { return GetEventSignature(registrarApprovalMethod()) }
+
+// ETHRegistrarController
+func GetControllerNameRegisteredSignature() string {
+	return GetEventSignature(controllerNameRegisteredMethod())
+}
+func GetControllerNameRenewedSignature() string {
+	return GetEventSignature(controllerNameRenewedMethod())
+}
+func GetNewPriceOracleSignature() string { return GetEventSignature(newPriceOracleMethod()) }
+
+// ReverseRegistrar
+func GetReverseClaimedSignature() string { return GetEventSignature(reverseClaimedMethod()) }
+
 // Registry
 func GetNewOwnerSignature() string    { return GetEventSignature(newOwnerMethod()) }
 func GetNewResolverSignature() string { return GetEventSignature(newResolverMethod()) }
@@ -31,11 +52,20 @@ func GetNewTtlSignature() string      { return GetEventSignature(newTtlMethod())
 func GetTransferSignature() string    { return GetEventSignature(transferMethod()) }
 
 // Resolver
-func GetAbiChangedSignature() string         { return GetEventSignature(abiChangedMethod()) }
-func GetAddrChangedSignature() string        { return GetEventSignature(addrChangedMethod()) }
+func GetAbiChangedSignature() string     { return GetEventSignature(abiChangedMethod()) }
+func GetAddrChangedSignature() string    { return GetEventSignature(addrChangedMethod()) }
+func GetAddressChangedSignature() string { return GetEventSignature(addressChangedMethod()) }
+func GetAuthorisationChangedSignature() string {
+	return GetEventSignature(authorisationChangedMethod())
+}
 func GetContentChangedSignature() string     { return GetEventSignature(contentChangedMethod()) }
 func GetContenthashChangedSignature() string { return GetEventSignature(contenthashChangedMethod()) }
+func GetDNSRecordChangedSignature() string   { return GetEventSignature(dnsRecordChangedMethod()) }
+func GetDNSRecordDeletedSignature() string   { return GetEventSignature(dnsRecordDeletedMethod()) }
+func GetDNSZonehashChangedSignature() string { return GetEventSignature(dnsZonehashChangedMethod()) }
+func GetInterfaceChangedSignature() string   { return GetEventSignature(interfaceChangedMethod()) }
 func GetMultihashChangedSignature() string   { return GetEventSignature(multihashChangedMethod()) }
 func GetNameChangedSignature() string        { return GetEventSignature(nameChangedMethod()) }
 func GetPubkeyChangedSignature() string      { return GetEventSignature(pubkeyChangedMethod()) }
 func GetTextChangedSignature() string        { return GetEventSignature(textChangedMethod()) }
+func GetVersionChangedSignature() string     { return GetEventSignature(versionChangedMethod()) }
@@ -0,0 +1,55 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package contenthash
+
+import "math/big"
+
+const (
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base36Alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+)
+
+func base58Encode(data []byte) string {
+	return encodeBase(data, base58Alphabet)
+}
+
+func base36Encode(data []byte) string {
+	return encodeBase(data, base36Alphabet)
+}
+
+// Encodes the data as a big-endian number in the radix of the alphabet, with leading zero bytes encoded as the alphabet's zero
+func encodeBase(data []byte, alphabet string) string {
+	var encoded []byte
+	value := new(big.Int).SetBytes(data)
+	radix := big.NewInt(int64(len(alphabet)))
+	mod := new(big.Int)
+	for value.Sign() > 0 {
+		value.DivMod(value, radix, mod)
+		encoded = append(encoded, alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,98 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package contenthash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A content identifier, as stored in ipfs, ipns and swarm contenthashes
+type cid struct {
+	version      uint64
+	codec        uint64
+	hashFunction uint64
+	multihash    []byte // Hash function, digest length and digest
+	digest       []byte
+	raw          []byte
+}
+
+// Decodes a binary CID; a bare sha2-256 multihash is a CIDv0
+func decodeCid(data []byte) (cid, error) {
+	if len(data) == 2+cidV0HashLength && uint64(data[0]) == sha256Hash && data[1] == cidV0HashLength {
+		return cid{version: 0, codec: dagPbCodec, hashFunction: sha256Hash, multihash: data, digest: data[2:], raw: data}, nil
+	}
+	version, rest, err := readVarint(data)
+	if err != nil {
+		return cid{}, err
+	}
+	if version != 1 {
+		return cid{}, fmt.Errorf("%v: CID version %d", ErrMalformed, version)
+	}
+	codec, multihash, err := readVarint(rest)
+	if err != nil {
+		return cid{}, err
+	}
+	hashFunction, rest, err := readVarint(multihash)
+	if err != nil {
+		return cid{}, err
+	}
+	length, digest, err := readVarint(rest)
+	if err != nil {
+		return cid{}, err
+	}
+	if uint64(len(digest)) != length {
+		return cid{}, fmt.Errorf("%v: digest of %d bytes, not %d", ErrMalformed, len(digest), length)
+	}
+
+	return cid{version: 1, codec: codec, hashFunction: hashFunction, multihash: multihash, digest: digest, raw: data}, nil
+}
+
+// IPFS content is given by its CIDv0 when it can be, as gateways and most tooling expect, and otherwise by its base32 CIDv1
+func (c cid) ipfsString() string {
+	if c.codec == dagPbCodec && c.hashFunction == sha256Hash && len(c.digest) == cidV0HashLength {
+		return base58Encode(c.multihash)
+	}
+
+	return c.base32String()
+}
+
+// IPNS names are either the public key of a libp2p peer, given in base36 as the IPFS tooling does,
+// or a DNSLink domain embedded in an identity hash
+func (c cid) ipnsString() string {
+	if c.codec == libp2pKeyCodec {
+		return "k" + base36Encode(c.raw)
+	}
+	if c.hashFunction == identityHash {
+		return string(c.digest)
+	}
+	if c.version == 0 {
+		return base58Encode(c.multihash)
+	}
+
+	return c.base32String()
+}
+
+func (c cid) base32String() string {
+	raw := c.raw
+	if c.version == 0 {
+		// Upgrade a CIDv0 to its CIDv1 form
+		raw = append([]byte{1, byte(dagPbCodec)}, c.multihash...)
+	}
+
+	return "b" + strings.ToLower(base32Encoding.EncodeToString(raw))
+}
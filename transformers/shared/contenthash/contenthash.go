@@ -0,0 +1,159 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package contenthash
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Protocols of the content an EIP-1577 contenthash can point to
+const (
+	IPFS   = "ipfs"
+	IPNS   = "ipns"
+	Swarm  = "swarm"
+	Onion  = "onion"
+	Onion3 = "onion3"
+)
+
+// Multicodec codes of the contenthash namespaces, and of the content types and hash functions found in their CIDs
+const (
+	ipfsNamespace   uint64 = 0xe3
+	ipnsNamespace   uint64 = 0xe5
+	swarmNamespace  uint64 = 0xe4
+	onionNamespace  uint64 = 0x01bc
+	onion3Namespace uint64 = 0x01bd
+
+	dagPbCodec      uint64 = 0x70
+	libp2pKeyCodec  uint64 = 0x72
+	swarmManifest   uint64 = 0xfa
+	identityHash    uint64 = 0x00
+	sha256Hash      uint64 = 0x12
+	keccak256Hash   uint64 = 0x1b
+	cidV0HashLength        = 32
+)
+
+var (
+	ErrUnknownNamespace = errors.New("unknown contenthash namespace")
+	ErrMalformed        = errors.New("malformed contenthash")
+
+	base32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// Decodes an EIP-1577 contenthash into the protocol of the content it points to and a URI for that content, e.g.
+// 0xe3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f => ipfs, ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4
+// IPFS content is addressed by its CIDv0 when it has one, otherwise by its base32 CIDv1; IPNS keys are given as base36 CIDv1s
+// An empty contenthash (one that has been cleared) decodes to an empty protocol and URI
+func Decode(hash []byte) (protocol, uri string, err error) {
+	if len(hash) == 0 {
+		return "", "", nil
+	}
+	namespace, rest, err := readVarint(hash)
+	if err != nil {
+		return "", "", err
+	}
+	switch namespace {
+	case ipfsNamespace:
+		cid, err := decodeCid(rest)
+		if err != nil {
+			return "", "", err
+		}
+		return IPFS, "ipfs://" + cid.ipfsString(), nil
+	case ipnsNamespace:
+		cid, err := decodeCid(rest)
+		if err != nil {
+			return "", "", err
+		}
+		return IPNS, "ipns://" + cid.ipnsString(), nil
+	case swarmNamespace:
+		cid, err := decodeCid(rest)
+		if err != nil {
+			return "", "", err
+		}
+		if cid.hashFunction != keccak256Hash {
+			return "", "", fmt.Errorf("%v: swarm hash function 0x%x", ErrMalformed, cid.hashFunction)
+		}
+		return Swarm, "bzz://" + strings.TrimPrefix(hexutil.Encode(cid.digest), "0x"), nil
+	case onionNamespace:
+		address, err := onionAddress(rest, 16)
+		if err != nil {
+			return "", "", err
+		}
+		return Onion, "onion://" + address, nil
+	case onion3Namespace:
+		address, err := onionAddress(rest, 56)
+		if err != nil {
+			return "", "", err
+		}
+		return Onion3, "onion3://" + address, nil
+	}
+
+	return "", "", fmt.Errorf("%v: 0x%x", ErrUnknownNamespace, namespace)
+}
+
+// Decodes the IPFS multihash set by a legacy resolver's setMultihash into the same protocol and URI as an equivalent contenthash
+// A multihash is the dag-pb content of a CIDv0 (or CIDv1 for hash functions other than sha2-256)
+func DecodeMultihash(hash []byte) (protocol, uri string, err error) {
+	if len(hash) == 0 {
+		return "", "", nil
+	}
+	cid, err := decodeCid(append([]byte{1, byte(dagPbCodec)}, hash...))
+	if err != nil {
+		return "", "", err
+	}
+
+	return IPFS, "ipfs://" + cid.ipfsString(), nil
+}
+
+// Decodes the bytes32 content hash set by a legacy resolver's setContent, which is the hash of a Swarm manifest
+// The zero hash is a cleared content hash, and decodes to an empty protocol and URI
+func DecodeSwarmHash(hash []byte) (protocol, uri string, err error) {
+	if len(hash) != 32 {
+		return "", "", fmt.Errorf("%v: swarm hash of %d bytes, not 32", ErrMalformed, len(hash))
+	}
+	if new(big.Int).SetBytes(hash).Sign() == 0 {
+		return "", "", nil
+	}
+
+	return Swarm, "bzz://" + strings.TrimPrefix(hexutil.Encode(hash), "0x"), nil
+}
+
+// Tor hidden service addresses are stored as their plaintext
+func onionAddress(data []byte, length int) (string, error) {
+	if len(data) != length || !utf8.Valid(data) {
+		return "", fmt.Errorf("%v: onion address of %d bytes, not %d", ErrMalformed, len(data), length)
+	}
+
+	return string(data), nil
+}
+
+// Reads an unsigned varint from the front of data, returning the value and the remaining bytes
+func readVarint(data []byte) (uint64, []byte, error) {
+	value, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, ErrMalformed
+	}
+
+	return value, data[n:], nil
+}
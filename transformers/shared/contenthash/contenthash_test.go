@@ -0,0 +1,111 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package contenthash_test
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/contenthash"
+)
+
+var _ = Describe("Decode", func() {
+	DescribeTable("decodes contenthashes into their protocol and URI",
+		func(hash, expectedProtocol, expectedURI string) {
+			protocol, uri, err := contenthash.Decode(hexutil.MustDecode(hash))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(protocol).To(Equal(expectedProtocol))
+			Expect(uri).To(Equal(expectedURI))
+		},
+		Entry("ipfs dag-pb CIDv1 as CIDv0", "0xe3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f",
+			contenthash.IPFS, "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"),
+		Entry("ipfs bare multihash as CIDv0", "0xe301122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f",
+			contenthash.IPFS, "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"),
+		Entry("ipfs raw CIDv1 as base32", "0xe3010155122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f",
+			contenthash.IPFS, "ipfs://bafkreibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4"),
+		Entry("ipns libp2p-key as base36", "0xe50101720024080112205cbd1cc86ac20d6640795809c2a185bb2504538a2de8076da5a6971b8acb4715",
+			contenthash.IPNS, "ipns://k51qzi5uqu5dihst24f3rp2ej4co9berxohfkxaenbq1wjty7nrd5e9xp4afx1"),
+		Entry("ipns DNSLink domain", "0xe5010170000f6170702e756e69737761702e6f7267",
+			contenthash.IPNS, "ipns://app.uniswap.org"),
+		Entry("swarm manifest", "0xe40101fa011b20d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162",
+			contenthash.Swarm, "bzz://d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162"),
+		Entry("swarm with the dag-pb codec of early contenthashes", "0xe40101701b206c41b0e4e24593f5155277e2a1ec45545db24d424974eb71173421523db0e2a6",
+			contenthash.Swarm, "bzz://6c41b0e4e24593f5155277e2a1ec45545db24d424974eb71173421523db0e2a6"),
+		Entry("onion", "0xbc037a716b746c776934666563766f367269",
+			contenthash.Onion, "onion://zqktlwi4fecvo6ri"),
+		Entry("onion3", "0xbd037035336c663537716f7679757677736336786e72707079706c79337674716d376c3670636f626b6d797173696f6679657a6e667535757164",
+			contenthash.Onion3, "onion3://p53lf57qovyuvwsc6xnrppyply3vtqm7l6pcobkmyqsiofyeznfu5uqd"),
+		Entry("cleared contenthash", "0x", "", ""),
+	)
+
+	DescribeTable("returns an error for contenthashes that can't be decoded",
+		func(hash string) {
+			_, _, err := contenthash.Decode(hexutil.MustDecode(hash))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown namespace", "0xaa01122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f"),
+		Entry("truncated digest", "0xe3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a89"),
+		Entry("unsupported CID version", "0xe3010270122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f"),
+		Entry("swarm with a sha2-256 digest", "0xe40101fa011220d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162"),
+		Entry("onion address of the wrong length", "0xbc037a716b746c7769"),
+		Entry("truncated namespace", "0xe3"),
+	)
+})
+
+var _ = Describe("DecodeMultihash", func() {
+	DescribeTable("decodes legacy multihashes into IPFS URIs",
+		func(hash, expectedProtocol, expectedURI string) {
+			protocol, uri, err := contenthash.DecodeMultihash(hexutil.MustDecode(hash))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(protocol).To(Equal(expectedProtocol))
+			Expect(uri).To(Equal(expectedURI))
+		},
+		Entry("sha2-256 as CIDv0", "0x122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f",
+			contenthash.IPFS, "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"),
+		Entry("other hash functions as base32 CIDv1", "0x1b2029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f",
+			contenthash.IPFS, "ipfs://bafybwibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4"),
+		Entry("cleared multihash", "0x", "", ""),
+	)
+
+	It("returns an error for a truncated multihash", func() {
+		_, _, err := contenthash.DecodeMultihash(hexutil.MustDecode("0x122029f2d17be6139079dc48696d"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("DecodeSwarmHash", func() {
+	It("decodes a legacy content hash into a Swarm URI", func() {
+		protocol, uri, err := contenthash.DecodeSwarmHash(hexutil.MustDecode("0xd1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(protocol).To(Equal(contenthash.Swarm))
+		Expect(uri).To(Equal("bzz://d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162"))
+	})
+
+	It("decodes the zero hash to an empty protocol and URI", func() {
+		protocol, uri, err := contenthash.DecodeSwarmHash(make([]byte, 32))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(protocol).To(Equal(""))
+		Expect(uri).To(Equal(""))
+	})
+
+	It("returns an error for hashes that aren't 32 bytes", func() {
+		_, _, err := contenthash.DecodeSwarmHash(hexutil.MustDecode("0xd1de9994"))
+		Expect(err).To(HaveOccurred())
+	})
+})
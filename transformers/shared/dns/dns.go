@@ -0,0 +1,162 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Classes and types of the resource records resolvers are commonly given
+const (
+	ClassINET uint16 = 1
+
+	TypeA     uint16 = 1
+	TypeNS    uint16 = 2
+	TypeCNAME uint16 = 5
+	TypeSOA   uint16 = 6
+	TypePTR   uint16 = 12
+	TypeMX    uint16 = 15
+	TypeTXT   uint16 = 16
+	TypeAAAA  uint16 = 28
+	TypeSRV   uint16 = 33
+	TypeDS    uint16 = 43
+)
+
+const (
+	maxNameLength  = 255
+	maxLabelLength = 63
+	// The fixed length fields between a record's owner name and its rdata: type, class, TTL and rdata length
+	headerLength = 10
+)
+
+var ErrMalformed = errors.New("malformed DNS wire format")
+
+// A resource record decoded from DNS wire format (RFC 1035 section 4.1.3)
+type ResourceRecord struct {
+	Name  string
+	Type  uint16
+	Class uint16
+	TTL   uint32
+	Rdata []byte
+}
+
+// Parses the concatenated wire format resource records of an RRset, as set on a resolver through setDNSRecords
+// Resolvers don't support name compression, so compression pointers are rejected as malformed
+func ParseRRSet(data []byte) ([]ResourceRecord, error) {
+	var records []ResourceRecord
+	for offset := 0; offset < len(data); {
+		record, next, err := readRecord(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+		offset = next
+	}
+
+	return records, nil
+}
+
+// Parses a wire format domain name into its presentation format, e.g. 0x03777777076578616d706c6503636f6d00 => www.example.com.
+func ParseName(data []byte) (string, error) {
+	name, next, err := readName(data, 0)
+	if err != nil {
+		return "", err
+	}
+	if next != len(data) {
+		return "", fmt.Errorf("%v: %d trailing bytes after name", ErrMalformed, len(data)-next)
+	}
+
+	return name, nil
+}
+
+func readRecord(data []byte, offset int) (ResourceRecord, int, error) {
+	name, offset, err := readName(data, offset)
+	if err != nil {
+		return ResourceRecord{}, 0, err
+	}
+	if len(data)-offset < headerLength {
+		return ResourceRecord{}, 0, fmt.Errorf("%v: truncated record header", ErrMalformed)
+	}
+	record := ResourceRecord{
+		Name:  name,
+		Type:  binary.BigEndian.Uint16(data[offset:]),
+		Class: binary.BigEndian.Uint16(data[offset+2:]),
+		TTL:   binary.BigEndian.Uint32(data[offset+4:]),
+	}
+	rdataLength := int(binary.BigEndian.Uint16(data[offset+8:]))
+	offset += headerLength
+	if len(data)-offset < rdataLength {
+		return ResourceRecord{}, 0, fmt.Errorf("%v: truncated rdata", ErrMalformed)
+	}
+	record.Rdata = append([]byte{}, data[offset:offset+rdataLength]...)
+
+	return record, offset + rdataLength, nil
+}
+
+// Reads the length prefixed labels of a name up to its terminating root label
+func readName(data []byte, offset int) (string, int, error) {
+	var labels []string
+	wireLength := 0
+	for {
+		if offset >= len(data) {
+			return "", 0, fmt.Errorf("%v: truncated name", ErrMalformed)
+		}
+		length := int(data[offset])
+		offset++
+		wireLength += length + 1
+		if length == 0 {
+			break
+		}
+		if length > maxLabelLength {
+			return "", 0, fmt.Errorf("%v: label of %d bytes", ErrMalformed, length)
+		}
+		if wireLength > maxNameLength {
+			return "", 0, fmt.Errorf("%v: name longer than %d bytes", ErrMalformed, maxNameLength)
+		}
+		if len(data)-offset < length {
+			return "", 0, fmt.Errorf("%v: truncated label", ErrMalformed)
+		}
+		labels = append(labels, EscapeLabel(data[offset:offset+length]))
+		offset += length
+	}
+	if len(labels) == 0 {
+		return ".", offset, nil
+	}
+
+	return strings.Join(labels, ".") + ".", offset, nil
+}
+
+// Escapes the bytes of a label that can't appear in a name's presentation format as is (RFC 4343 section 2.1)
+func EscapeLabel(label []byte) string {
+	var builder strings.Builder
+	for _, b := range label {
+		switch {
+		case b == '.' || b == '\\' || b == '"' || b == '(' || b == ')' || b == ';' || b == '@' || b == '$':
+			builder.WriteByte('\\')
+			builder.WriteByte(b)
+		case b <= ' ' || b >= 0x7f:
+			fmt.Fprintf(&builder, "\\%03d", b)
+		default:
+			builder.WriteByte(b)
+		}
+	}
+
+	return builder.String()
+}
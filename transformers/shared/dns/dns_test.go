@@ -0,0 +1,86 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_test
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/dns"
+)
+
+var _ = Describe("ParseRRSet", func() {
+	It("parses every record of an RRset", func() {
+		// a.example.com. 3600 IN A 1.2.3.4 and a.example.com. 3600 IN A 2.3.4.5
+		records, err := dns.ParseRRSet(hexutil.MustDecode("0x0161076578616d706c6503636f6d000001000100000e100004010203040161076578616d706c6503636f6d000001000100000e10000402030405"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(Equal([]dns.ResourceRecord{
+			{Name: "a.example.com.", Type: dns.TypeA, Class: dns.ClassINET, TTL: 3600, Rdata: []byte{1, 2, 3, 4}},
+			{Name: "a.example.com.", Type: dns.TypeA, Class: dns.ClassINET, TTL: 3600, Rdata: []byte{2, 3, 4, 5}},
+		}))
+	})
+
+	It("keeps the rdata of a record as is", func() {
+		// example.com. 300 IN TXT "hello world"
+		records, err := dns.ParseRRSet(hexutil.MustDecode("0x076578616d706c6503636f6d00001000010000012c000c0b68656c6c6f20776f726c64"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(records)).To(Equal(1))
+		Expect(records[0].Type).To(Equal(dns.TypeTXT))
+		Expect(records[0].TTL).To(Equal(uint32(300)))
+		Expect(records[0].Rdata).To(Equal(append([]byte{11}, []byte("hello world")...)))
+	})
+
+	It("parses an empty RRset into no records", func() {
+		records, err := dns.ParseRRSet([]byte{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(BeEmpty())
+	})
+
+	DescribeTable("returns an error for malformed records",
+		func(record string) {
+			_, err := dns.ParseRRSet(hexutil.MustDecode(record))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("truncated name", "0x0161076578616d706c65"),
+		Entry("truncated header", "0x0161076578616d706c6503636f6d0000010001"),
+		Entry("truncated rdata", "0x0161076578616d706c6503636f6d000001000100000e100004010203"),
+		Entry("compressed name", "0xc00c000100010000012c000401020304"),
+	)
+})
+
+var _ = Describe("ParseName", func() {
+	DescribeTable("parses wire format names into their presentation format",
+		func(name, expected string) {
+			parsed, err := dns.ParseName(hexutil.MustDecode(name))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(expected))
+		},
+		Entry("subdomain", "0x0161076578616d706c6503636f6d00", "a.example.com."),
+		Entry("root", "0x00", "."),
+		Entry("labels with a dot and a space", "0x03612e62037820790365746800", "a\\.b.x\\032y.eth."),
+	)
+
+	It("returns an error for trailing bytes after the name", func() {
+		_, err := dns.ParseName(hexutil.MustDecode("0x0161000000"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,244 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// Opcodes and response codes of DNS messages (RFC 1035 section 4.1.1)
+const (
+	OpcodeQuery uint8 = 0
+
+	RcodeSuccess        uint8 = 0
+	RcodeFormatError    uint8 = 1
+	RcodeServerFailure  uint8 = 2
+	RcodeNameError      uint8 = 3
+	RcodeNotImplemented uint8 = 4
+	RcodeRefused        uint8 = 5
+)
+
+const (
+	// The largest message sent over UDP to a client that doesn't advertise a larger size with EDNS (RFC 1035 section 2.3.4)
+	MaxUDPMessageLength = 512
+	messageHeaderLength = 12
+	maxCharacterString  = 255
+)
+
+type Question struct {
+	Name  string
+	Type  uint16
+	Class uint16
+}
+
+// A DNS message with its question and answer sections; authority and additional records are not read or written
+type Message struct {
+	ID                 uint16
+	Response           bool
+	Opcode             uint8
+	Authoritative      bool
+	Truncated          bool
+	RecursionDesired   bool
+	RecursionAvailable bool
+	Rcode              uint8
+	Questions          []Question
+	Answers            []ResourceRecord
+}
+
+// Parses the header, questions and answers of a wire format message, ignoring its authority and additional sections
+// Like resource records, messages with compressed names are rejected as malformed
+// If the header could be read, it is returned along with the error so that the message can still be answered with a format error
+func ParseMessage(data []byte) (Message, error) {
+	if len(data) < messageHeaderLength {
+		return Message{}, fmt.Errorf("%v: truncated message header", ErrMalformed)
+	}
+	flags := binary.BigEndian.Uint16(data[2:])
+	message := Message{
+		ID:                 binary.BigEndian.Uint16(data),
+		Response:           flags&(1<<15) != 0,
+		Opcode:             uint8(flags>>11) & 0xf,
+		Authoritative:      flags&(1<<10) != 0,
+		Truncated:          flags&(1<<9) != 0,
+		RecursionDesired:   flags&(1<<8) != 0,
+		RecursionAvailable: flags&(1<<7) != 0,
+		Rcode:              uint8(flags) & 0xf,
+	}
+	header := message
+	questions := int(binary.BigEndian.Uint16(data[4:]))
+	answers := int(binary.BigEndian.Uint16(data[6:]))
+
+	offset := messageHeaderLength
+	for i := 0; i < questions; i++ {
+		name, next, err := readName(data, offset)
+		if err != nil {
+			return header, err
+		}
+		if len(data)-next < 4 {
+			return header, fmt.Errorf("%v: truncated question", ErrMalformed)
+		}
+		message.Questions = append(message.Questions, Question{
+			Name:  name,
+			Type:  binary.BigEndian.Uint16(data[next:]),
+			Class: binary.BigEndian.Uint16(data[next+2:]),
+		})
+		offset = next + 4
+	}
+	for i := 0; i < answers; i++ {
+		record, next, err := readRecord(data, offset)
+		if err != nil {
+			return header, err
+		}
+		message.Answers = append(message.Answers, record)
+		offset = next
+	}
+
+	return message, nil
+}
+
+// Encodes the message in wire format, without compressing its names
+func (m Message) Pack() ([]byte, error) {
+	if len(m.Questions) > 0xffff || len(m.Answers) > 0xffff {
+		return nil, fmt.Errorf("too many questions or answers")
+	}
+	flags := uint16(m.Opcode&0xf)<<11 | uint16(m.Rcode&0xf)
+	flags |= flag(m.Response, 15) | flag(m.Authoritative, 10) | flag(m.Truncated, 9) | flag(m.RecursionDesired, 8) | flag(m.RecursionAvailable, 7)
+
+	data := make([]byte, messageHeaderLength, MaxUDPMessageLength)
+	binary.BigEndian.PutUint16(data, m.ID)
+	binary.BigEndian.PutUint16(data[2:], flags)
+	binary.BigEndian.PutUint16(data[4:], uint16(len(m.Questions)))
+	binary.BigEndian.PutUint16(data[6:], uint16(len(m.Answers)))
+	for _, question := range m.Questions {
+		name, err := EncodeName(question.Name)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, name...)
+		data = appendUint16(data, question.Type)
+		data = appendUint16(data, question.Class)
+	}
+	for _, record := range m.Answers {
+		name, err := EncodeName(record.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(record.Rdata) > 0xffff {
+			return nil, fmt.Errorf("rdata of %d bytes is too long", len(record.Rdata))
+		}
+		data = append(data, name...)
+		data = appendUint16(data, record.Type)
+		data = appendUint16(data, record.Class)
+		data = append(data, byte(record.TTL>>24), byte(record.TTL>>16), byte(record.TTL>>8), byte(record.TTL))
+		data = appendUint16(data, uint16(len(record.Rdata)))
+		data = append(data, record.Rdata...)
+	}
+
+	return data, nil
+}
+
+func flag(set bool, bit uint) uint16 {
+	if set {
+		return 1 << bit
+	}
+	return 0
+}
+
+func appendUint16(data []byte, value uint16) []byte {
+	return append(data, byte(value>>8), byte(value))
+}
+
+// Encodes a name in presentation format, e.g. www.example.com., into wire format, unescaping the escapes ParseName produces
+// Names are taken to be fully qualified whether or not they end with a dot
+func EncodeName(name string) ([]byte, error) {
+	var data []byte
+	var label []byte
+	endLabel := func() error {
+		if len(label) == 0 {
+			return fmt.Errorf("invalid name %q: empty label", name)
+		}
+		if len(label) > maxLabelLength {
+			return fmt.Errorf("invalid name %q: label of %d bytes", name, len(label))
+		}
+		data = append(append(data, byte(len(label))), label...)
+		label = nil
+		return nil
+	}
+
+	if name != "." {
+		terminated := false
+		for i := 0; i < len(name); i++ {
+			c := name[i]
+			terminated = c == '.'
+			switch {
+			case c == '.':
+				err := endLabel()
+				if err != nil {
+					return nil, err
+				}
+			case c == '\\' && i+3 < len(name) && isDigit(name[i+1]) && isDigit(name[i+2]) && isDigit(name[i+3]):
+				value, err := strconv.Atoi(name[i+1 : i+4])
+				if err != nil || value > 0xff {
+					return nil, fmt.Errorf("invalid name %q: invalid escape", name)
+				}
+				label = append(label, byte(value))
+				i += 3
+			case c == '\\':
+				if i+1 >= len(name) {
+					return nil, fmt.Errorf("invalid name %q: invalid escape", name)
+				}
+				label = append(label, name[i+1])
+				i++
+			default:
+				label = append(label, c)
+			}
+		}
+		if !terminated {
+			err := endLabel()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	data = append(data, 0)
+	if len(data) > maxNameLength {
+		return nil, fmt.Errorf("invalid name %q: longer than %d bytes", name, maxNameLength)
+	}
+
+	return data, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Encodes text as the rdata of a TXT record, split into character strings of at most 255 bytes
+func TXTRdata(text string) []byte {
+	var rdata []byte
+	for {
+		chunk := text
+		if len(chunk) > maxCharacterString {
+			chunk = chunk[:maxCharacterString]
+		}
+		rdata = append(append(rdata, byte(len(chunk))), chunk...)
+		text = text[len(chunk):]
+		if text == "" {
+			return rdata
+		}
+	}
+}
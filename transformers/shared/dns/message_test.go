@@ -0,0 +1,124 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_test
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/dns"
+)
+
+var _ = Describe("ParseMessage", func() {
+	It("parses the header and question of a query", func() {
+		// id 0x1234, recursion desired, example.com. IN A, with an EDNS OPT record in the additional section
+		message, err := dns.ParseMessage(hexutil.MustDecode("0x123401000001000000000001076578616d706c6503636f6d0000010001" +
+			"0000291000000000000000"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(message).To(Equal(dns.Message{
+			ID:               0x1234,
+			RecursionDesired: true,
+			Questions:        []dns.Question{{Name: "example.com.", Type: dns.TypeA, Class: dns.ClassINET}},
+		}))
+	})
+
+	DescribeTable("returns an error for malformed messages",
+		func(message string) {
+			_, err := dns.ParseMessage(hexutil.MustDecode(message))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("truncated header", "0x123401000001"),
+		Entry("truncated question", "0x123401000001000000000000076578616d706c6503636f6d000001"),
+		Entry("missing answer", "0x123481800001000100000000076578616d706c6503636f6d0000010001"),
+	)
+
+	It("returns the header of messages with malformed questions, so that they can be answered", func() {
+		message, err := dns.ParseMessage(hexutil.MustDecode("0x123401000001000000000000076578616d706c6503636f6d000001"))
+
+		Expect(err).To(HaveOccurred())
+		Expect(message.ID).To(Equal(uint16(0x1234)))
+		Expect(message.RecursionDesired).To(BeTrue())
+		Expect(message.Questions).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Message", func() {
+	It("packs messages that parse back into the same message", func() {
+		message := dns.Message{
+			ID:                 0xbeef,
+			Response:           true,
+			Authoritative:      true,
+			RecursionDesired:   true,
+			RecursionAvailable: true,
+			Rcode:              dns.RcodeNameError,
+			Questions:          []dns.Question{{Name: "vitalik.eth.", Type: dns.TypeTXT, Class: dns.ClassINET}},
+			Answers: []dns.ResourceRecord{
+				{Name: "vitalik.eth.", Type: dns.TypeTXT, Class: dns.ClassINET, TTL: 300, Rdata: dns.TXTRdata("dnslink=/ipfs/Qm")},
+				{Name: "a\\.b.eth.", Type: dns.TypeA, Class: dns.ClassINET, TTL: 1 << 31, Rdata: []byte{1, 2, 3, 4}},
+			},
+		}
+
+		packed, err := message.Pack()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hexutil.Encode(packed[:12])).To(Equal("0xbeef85830001000200000000"))
+		parsed, err := dns.ParseMessage(packed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed).To(Equal(message))
+	})
+})
+
+var _ = Describe("EncodeName", func() {
+	DescribeTable("encodes names into wire format",
+		func(name, expected string) {
+			encoded, err := dns.EncodeName(name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hexutil.Encode(encoded)).To(Equal(expected))
+		},
+		Entry("fully qualified name", "a.example.com.", "0x0161076578616d706c6503636f6d00"),
+		Entry("name without a trailing dot", "a.example.com", "0x0161076578616d706c6503636f6d00"),
+		Entry("root", ".", "0x00"),
+		Entry("escaped dot and space", "a\\.b.x\\032y.eth.", "0x03612e62037820790365746800"),
+	)
+
+	DescribeTable("returns an error for invalid names",
+		func(name string) {
+			_, err := dns.EncodeName(name)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty label", "a..eth."),
+		Entry("empty name", ""),
+		Entry("long label", strings.Repeat("a", 64)+".eth."),
+		Entry("long name", strings.Repeat(strings.Repeat("a", 63)+".", 4)+"eth."),
+	)
+})
+
+var _ = Describe("TXTRdata", func() {
+	It("splits long text into character strings of at most 255 bytes", func() {
+		text := strings.Repeat("a", 300)
+		rdata := dns.TXTRdata(text)
+
+		Expect(len(rdata)).To(Equal(302))
+		Expect(rdata[0]).To(Equal(byte(255)))
+		Expect(rdata[256]).To(Equal(byte(45)))
+		Expect(dns.TXTRdata("")).To(Equal([]byte{0}))
+	})
+})
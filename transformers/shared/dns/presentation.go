@@ -0,0 +1,159 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var typeNames = map[uint16]string{
+	TypeA:     "A",
+	TypeNS:    "NS",
+	TypeCNAME: "CNAME",
+	TypeSOA:   "SOA",
+	TypePTR:   "PTR",
+	TypeMX:    "MX",
+	TypeTXT:   "TXT",
+	TypeAAAA:  "AAAA",
+	TypeSRV:   "SRV",
+	TypeDS:    "DS",
+}
+
+// Returns the mnemonic of a record type, or its number in the generic TYPE<n> form for types without one (RFC 3597 section 5)
+func TypeString(recordType uint16) string {
+	if name, ok := typeNames[recordType]; ok {
+		return name
+	}
+	return "TYPE" + strconv.Itoa(int(recordType))
+}
+
+// Formats wire format rdata in the master file format of its type (RFC 1035 section 5.1), e.g. 0x01020304 => 1.2.3.4 for an A record
+// Rdata of other types, or that is malformed for its type, is written in the generic \# form (RFC 3597 section 5)
+func FormatRdata(recordType uint16, rdata []byte) string {
+	var fields []string
+	var err error
+	switch recordType {
+	case TypeA:
+		if len(rdata) == net.IPv4len {
+			return net.IP(rdata).String()
+		}
+	case TypeAAAA:
+		if len(rdata) == net.IPv6len {
+			return net.IP(rdata).String()
+		}
+	case TypeNS, TypeCNAME, TypePTR:
+		fields, err = readFields(rdata, "name")
+	case TypeMX:
+		fields, err = readFields(rdata, "uint16", "name")
+	case TypeSRV:
+		fields, err = readFields(rdata, "uint16", "uint16", "uint16", "name")
+	case TypeSOA:
+		fields, err = readFields(rdata, "name", "name", "uint32", "uint32", "uint32", "uint32", "uint32")
+	case TypeTXT:
+		fields, err = readCharacterStrings(rdata)
+	}
+	if fields != nil && err == nil {
+		return strings.Join(fields, " ")
+	}
+
+	return genericRdata(rdata)
+}
+
+// Reads the fields of rdata with the given layout, requiring the fields to cover it exactly
+func readFields(rdata []byte, layout ...string) ([]string, error) {
+	var fields []string
+	offset := 0
+	for _, field := range layout {
+		switch field {
+		case "name":
+			name, next, err := readName(rdata, offset)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, name)
+			offset = next
+		case "uint16":
+			if len(rdata)-offset < 2 {
+				return nil, fmt.Errorf("%v: truncated rdata", ErrMalformed)
+			}
+			fields = append(fields, strconv.Itoa(int(binary.BigEndian.Uint16(rdata[offset:]))))
+			offset += 2
+		case "uint32":
+			if len(rdata)-offset < 4 {
+				return nil, fmt.Errorf("%v: truncated rdata", ErrMalformed)
+			}
+			fields = append(fields, strconv.FormatUint(uint64(binary.BigEndian.Uint32(rdata[offset:])), 10))
+			offset += 4
+		}
+	}
+	if offset != len(rdata) {
+		return nil, fmt.Errorf("%v: %d trailing bytes in rdata", ErrMalformed, len(rdata)-offset)
+	}
+
+	return fields, nil
+}
+
+// Reads the length prefixed character strings of TXT rdata as quoted strings
+func readCharacterStrings(rdata []byte) ([]string, error) {
+	if len(rdata) == 0 {
+		return nil, fmt.Errorf("%v: empty TXT rdata", ErrMalformed)
+	}
+	var fields []string
+	for offset := 0; offset < len(rdata); {
+		length := int(rdata[offset])
+		offset++
+		if len(rdata)-offset < length {
+			return nil, fmt.Errorf("%v: truncated character string", ErrMalformed)
+		}
+		fields = append(fields, quote(rdata[offset:offset+length]))
+		offset += length
+	}
+
+	return fields, nil
+}
+
+// Quotes a character string, escaping quotes, backslashes and bytes that aren't printable ASCII
+func quote(text []byte) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, b := range text {
+		switch {
+		case b == '"' || b == '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(b)
+		case b < ' ' || b >= 0x7f:
+			fmt.Fprintf(&builder, "\\%03d", b)
+		default:
+			builder.WriteByte(b)
+		}
+	}
+	builder.WriteByte('"')
+
+	return builder.String()
+}
+
+func genericRdata(rdata []byte) string {
+	if len(rdata) == 0 {
+		return "\\# 0"
+	}
+	return fmt.Sprintf("\\# %d %s", len(rdata), hex.EncodeToString(rdata))
+}
@@ -0,0 +1,61 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dns_test
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/ens_transformers/transformers/shared/dns"
+)
+
+var _ = Describe("FormatRdata", func() {
+	DescribeTable("formats rdata in the master file format of its type",
+		func(recordType uint16, rdata, expected string) {
+			Expect(dns.FormatRdata(recordType, hexutil.MustDecode(rdata))).To(Equal(expected))
+		},
+		Entry("A", dns.TypeA, "0x01020304", "1.2.3.4"),
+		Entry("AAAA", dns.TypeAAAA, "0x20010db8000000000000000000000001", "2001:db8::1"),
+		Entry("CNAME", dns.TypeCNAME, "0x0377777707766974616c696b0365746800", "www.vitalik.eth."),
+		Entry("MX", dns.TypeMX, "0x000a046d61696c076578616d706c6503636f6d00", "10 mail.example.com."),
+		Entry("SRV", dns.TypeSRV, "0x000a000501bb076578616d706c6503636f6d00", "10 5 443 example.com."),
+		Entry("SOA", dns.TypeSOA, "0x036e7331076578616d706c6503636f6d000a686f73746d6173746572076578616d706c6503636f6d000000000100000002000000030000000400000005",
+			"ns1.example.com. hostmaster.example.com. 1 2 3 4 5"),
+		Entry("TXT with several strings", dns.TypeTXT, "0x0568656c6c6f03612262", `"hello" "a\"b"`),
+		Entry("TXT with unprintable bytes", dns.TypeTXT, "0x02015c", `"\001\\"`),
+	)
+
+	DescribeTable("formats rdata of other types, or that is malformed for its type, in the generic form",
+		func(recordType uint16, rdata, expected string) {
+			Expect(dns.FormatRdata(recordType, hexutil.MustDecode(rdata))).To(Equal(expected))
+		},
+		Entry("unknown type", uint16(99), "0xabcd", `\# 2 abcd`),
+		Entry("empty rdata", uint16(99), "0x", `\# 0`),
+		Entry("A of the wrong length", dns.TypeA, "0x010203", `\# 3 010203`),
+		Entry("MX with trailing bytes", dns.TypeMX, "0x000a0000", `\# 4 000a0000`),
+		Entry("truncated TXT", dns.TypeTXT, "0x0568656c", `\# 4 0568656c`),
+	)
+})
+
+var _ = Describe("TypeString", func() {
+	It("returns the mnemonic of known types and the generic form of others", func() {
+		Expect(dns.TypeString(dns.TypeTXT)).To(Equal("TXT"))
+		Expect(dns.TypeString(99)).To(Equal("TYPE99"))
+	})
+})
@@ -51,6 +51,14 @@ func BigIntToString(value *big.Int) string {
 	}
 }
 
+// Converts a uint256 into the bytes32 it was cast from, e.g. the ERC-721 token id of a .eth name back into its label hash
+func BigIntToHash(value *big.Int) common.Hash {
+	if value == nil {
+		return common.Hash{}
+	}
+	return common.BigToHash(value)
+}
+
 func GetDataBytesAtIndex(n int, logData []byte) []byte {
 	switch {
 	case n == -1:
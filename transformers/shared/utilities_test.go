@@ -17,10 +17,14 @@
 package shared_test
 
 import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/vulcanize/ens_transformers/transformers/domain_records/utils"
+	"github.com/vulcanize/ens_transformers/transformers/shared"
 )
 
 var _ = Describe("Shared Utilities", func() {
@@ -32,4 +36,15 @@ var _ = Describe("Shared Utilities", func() {
 			Expect(subnode).To(Equal("0xb4664b154f4dd9abf5bb27d6e3ff12181d6e37b0606b4ff61ff8796e6e29a2e4"))
 		})
 	})
+	Describe("BigIntToHash", func() {
+		It("Converts a token id back into the label hash it was cast from", func() {
+			labelHash := common.HexToHash("0xaf2caa1c2ca1d027f1ac823b529d0a67cd144264b2789fa2ea4d63a67c7103cc")
+			Expect(shared.BigIntToHash(labelHash.Big())).To(Equal(labelHash))
+		})
+
+		It("Returns the zero hash for a nil value", func() {
+			var value *big.Int
+			Expect(shared.BigIntToHash(value)).To(Equal(common.Hash{}))
+		})
+	})
 })
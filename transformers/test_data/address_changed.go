@@ -0,0 +1,77 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/resolver/address_changed"
+)
+
+const (
+	TemporaryAddressChangedBlockNumber = int64(26)
+	TemporaryAddressChangedData        = "0x00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000001976a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac00000000000000"
+	TemporaryAddressChangedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	addressChangedRawJson, _ = json.Marshal(EthAddressChangedLog)
+	// The P2PKH scriptPubkey of litecoin address LUEweDxDA4WhvWiNXXSxjM9CYzHPJv4QQF
+	litecoinAddress = hexutil.MustDecode("0x76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac")
+)
+
+var EthAddressChangedLog = types.Log{
+	Address: common.HexToAddress(ResolverAddress),
+	Topics: []common.Hash{
+		common.HexToHash(AddressChangedSignature),
+		common.HexToHash("0x4554480000000000000000000000000000000000000000000000000000000000"),
+	},
+	Data:        hexutil.MustDecode(TemporaryAddressChangedData),
+	BlockNumber: uint64(TemporaryAddressChangedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryAddressChangedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var AddressChangedEntity = address_changed.AddressChangedEntity{
+	Resolver:         common.HexToAddress(ResolverAddress),
+	Node:             node,
+	CoinType:         big.NewInt(2),
+	NewAddress:       litecoinAddress,
+	LogIndex:         EthAddressChangedLog.Index,
+	TransactionIndex: EthAddressChangedLog.TxIndex,
+	Raw:              EthAddressChangedLog,
+}
+
+var AddressChangedModel = address_changed.AddressChangedModel{
+	Resolver:         ResolverAddress,
+	Node:             node.Hex(),
+	CoinType:         "2",
+	NewAddress:       hexutil.Encode(litecoinAddress),
+	LogIndex:         EthAddressChangedLog.Index,
+	TransactionIndex: EthAddressChangedLog.TxIndex,
+	Raw:              addressChangedRawJson,
+}
@@ -17,14 +17,17 @@
 package test_data
 
 const (
-	RegistryAddress        = "0x314159265dD8dbb310642f98f50C066173C1259b"
-	RopstenRegistryAddress = "0x112234455C3a32FD11230C42E7Bccd4A84e02010" //starts at block 25409
-	ResolverAddress        = "0x1da022710dF5002339274AaDEe8D58218e9D6AB5"
-	ResolverAddress2       = "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3" //starts at 6705947
-	ResolverAddress3       = "0x5FfC014343cd971B7eb70732021E26C35B744cc4" //starts at 3733668
-	RopstenResolverAddress = "0xcAcbE14d88380F8eb37ec0d7788ec226EE7b3434" //starts at block 4115489
-	RegistarAddress        = "0x6090A6e47849629b7245Dfa1Ca21D94cd15878Ef"
-	RopstenRegistarAddress = "0xC68De5B43C3d980B0C110A77a5F78d3c4c4d63B4" // starts at block 25461
+	RegistryAddress         = "0x314159265dD8dbb310642f98f50C066173C1259b"
+	RopstenRegistryAddress  = "0x112234455C3a32FD11230C42E7Bccd4A84e02010" //starts at block 25409
+	ResolverAddress         = "0x1da022710dF5002339274AaDEe8D58218e9D6AB5"
+	ResolverAddress2        = "0xD3ddcCDD3b25A8a7423B5bEe360a42146eb4Baf3" //starts at 6705947
+	ResolverAddress3        = "0x5FfC014343cd971B7eb70732021E26C35B744cc4" //starts at 3733668
+	RopstenResolverAddress  = "0xcAcbE14d88380F8eb37ec0d7788ec226EE7b3434" //starts at block 4115489
+	RegistarAddress         = "0x6090A6e47849629b7245Dfa1Ca21D94cd15878Ef"
+	RopstenRegistarAddress  = "0xC68De5B43C3d980B0C110A77a5F78d3c4c4d63B4" // starts at block 25461
+	BaseRegistrarAddress    = "0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85" // starts at block 9380410
+	ControllerAddress       = "0x283Af0B28c62C092C9727F1Ee09c02CA627EB7F5" // starts at block 9380471
+	ReverseRegistrarAddress = "0xa58E81fe9b61B5c3fE2AFD33CF304c454AbFc7Cb" // starts at block 16925606
 )
 
 /*
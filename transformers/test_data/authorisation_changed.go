@@ -0,0 +1,80 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/resolver/authorisation_changed"
+)
+
+const (
+	TemporaryAuthorisationChangedBlockNumber = int64(26)
+	TemporaryAuthorisationChangedData        = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	TemporaryAuthorisationChangedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	authorisationChangedRawJson, _ = json.Marshal(EthAuthorisationChangedLog)
+	authorisationOwner             = common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	authorisationTarget            = common.HexToAddress("0x283Af0B28c62C092C9727F1Ee09c02CA627EB7F5")
+)
+
+var EthAuthorisationChangedLog = types.Log{
+	Address: common.HexToAddress(ResolverAddress),
+	Topics: []common.Hash{
+		common.HexToHash(AuthorisationChangedSignature),
+		common.HexToHash("0x4554480000000000000000000000000000000000000000000000000000000000"),
+		common.BytesToHash(authorisationOwner.Bytes()),
+		common.BytesToHash(authorisationTarget.Bytes()),
+	},
+	Data:        hexutil.MustDecode(TemporaryAuthorisationChangedData),
+	BlockNumber: uint64(TemporaryAuthorisationChangedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryAuthorisationChangedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var AuthorisationChangedEntity = authorisation_changed.AuthorisationChangedEntity{
+	Resolver:         common.HexToAddress(ResolverAddress),
+	Node:             node,
+	Owner:            authorisationOwner,
+	Target:           authorisationTarget,
+	IsAuthorised:     true,
+	LogIndex:         EthAuthorisationChangedLog.Index,
+	TransactionIndex: EthAuthorisationChangedLog.TxIndex,
+	Raw:              EthAuthorisationChangedLog,
+}
+
+var AuthorisationChangedModel = authorisation_changed.AuthorisationChangedModel{
+	Resolver:         ResolverAddress,
+	Node:             node.Hex(),
+	Owner:            authorisationOwner.Hex(),
+	Target:           authorisationTarget.Hex(),
+	IsAuthorised:     true,
+	LogIndex:         EthAuthorisationChangedLog.Index,
+	TransactionIndex: EthAuthorisationChangedLog.TxIndex,
+	Raw:              authorisationChangedRawJson,
+}
@@ -67,6 +67,8 @@ var ContentChangedModel = content_changed.ContentChangedModel{
 	Resolver:         ResolverAddress,
 	Node:             node.Hex(),
 	Hash:             hash.Hex(),
+	Protocol:         "swarm",
+	URI:              "bzz://0000000000000000000000000000d8b4147eda80fec7122ae16da2479cbd7ffb",
 	LogIndex:         EthContentChangedLog.Index,
 	TransactionIndex: EthContentChangedLog.TxIndex,
 	Raw:              contentChangedRawJson,
@@ -67,6 +67,8 @@ var ContenthashChangedModel = contenthash_changed.ContenthashChangedModel{
 	Resolver:         ResolverAddress,
 	Node:             node.Hex(),
 	Hash:             targetHash,
+	Protocol:         "swarm",
+	URI:              "bzz://6c41b0e4e24593f5155277e2a1ec45545db24d424974eb71173421523db0e2a6",
 	LogIndex:         EthContenthashChangedLog.Index,
 	TransactionIndex: EthContenthashChangedLog.TxIndex,
 	Raw:              contenthashChangedRawJson,
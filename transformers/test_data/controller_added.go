@@ -0,0 +1,66 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/controller_added"
+)
+
+const (
+	TemporaryControllerAddedBlockNumber = int64(26)
+	TemporaryControllerAddedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	controllerAddedRawJson, _ = json.Marshal(EthControllerAddedLog)
+)
+
+var EthControllerAddedLog = types.Log{
+	Address: common.HexToAddress(BaseRegistrarAddress),
+	Topics: []common.Hash{
+		common.HexToHash(ControllerAddedSignature),
+		common.HexToHash("0x000000000000000000000000283af0b28c62c092c9727f1ee09c02ca627eb7f5"),
+	},
+	Data:        []byte{},
+	BlockNumber: uint64(TemporaryControllerAddedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryControllerAddedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var ControllerAddedEntity = controller_added.ControllerAddedEntity{
+	Controller:       registrarController,
+	LogIndex:         EthControllerAddedLog.Index,
+	TransactionIndex: EthControllerAddedLog.TxIndex,
+	Raw:              EthControllerAddedLog,
+}
+
+var ControllerAddedModel = controller_added.ControllerAddedModel{
+	Controller:       registrarController.Hex(),
+	LogIndex:         EthControllerAddedLog.Index,
+	TransactionIndex: EthControllerAddedLog.TxIndex,
+	Raw:              controllerAddedRawJson,
+}
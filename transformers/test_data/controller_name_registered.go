@@ -0,0 +1,82 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	controller_name_registered "github.com/vulcanize/ens_transformers/transformers/controller/name_registered"
+)
+
+const (
+	// ABI encoding of the non-indexed name ("vitalik"), cost and expires arguments
+	TemporaryControllerNameData                  = "0x0000000000000000000000000000000000000000000000000000000000000060000000000000000000000000000000000000000000000000002386f26fc10000000000000000000000000000000000000000000000000000000000005f5e10000000000000000000000000000000000000000000000000000000000000000007766974616c696b00000000000000000000000000000000000000000000000000"
+	TemporaryControllerNameRegisteredBlockNumber = int64(26)
+	TemporaryControllerNameRegisteredTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	controllerNameRegisteredRawJson, _ = json.Marshal(EthControllerNameRegisteredLog)
+	controllerName                     = "vitalik"
+	controllerCost                     = big.NewInt(10000000000000000)
+	priceOracle                        = common.HexToAddress("0xb9d374d0fe3d8341155663fae31b7beae0ae233a")
+)
+
+var EthControllerNameRegisteredLog = types.Log{
+	Address: common.HexToAddress(ControllerAddress),
+	Topics: []common.Hash{
+		common.HexToHash(ControllerNameRegisteredSignature),
+		registrarLabelHash,
+		common.HexToHash("0x0000000000000000000000000000d8b4147eda80fec7122ae16da2479cbd7ffb"),
+	},
+	Data:        hexutil.MustDecode(TemporaryControllerNameData),
+	BlockNumber: uint64(TemporaryControllerNameRegisteredBlockNumber),
+	TxHash:      common.HexToHash(TemporaryControllerNameRegisteredTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var ControllerNameRegisteredEntity = controller_name_registered.NameRegisteredEntity{
+	Name:             controllerName,
+	Label:            registrarLabelHash,
+	Owner:            owner,
+	Cost:             controllerCost,
+	Expires:          expires,
+	LogIndex:         EthControllerNameRegisteredLog.Index,
+	TransactionIndex: EthControllerNameRegisteredLog.TxIndex,
+	Raw:              EthControllerNameRegisteredLog,
+}
+
+var ControllerNameRegisteredModel = controller_name_registered.NameRegisteredModel{
+	Name:             controllerName,
+	LabelHash:        registrarLabelHash.Hex(),
+	Owner:            owner.Hex(),
+	Cost:             controllerCost.String(),
+	Expires:          expires.String(),
+	LogIndex:         EthControllerNameRegisteredLog.Index,
+	TransactionIndex: EthControllerNameRegisteredLog.TxIndex,
+	Raw:              controllerNameRegisteredRawJson,
+}
@@ -0,0 +1,73 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	controller_name_renewed "github.com/vulcanize/ens_transformers/transformers/controller/name_renewed"
+)
+
+const (
+	TemporaryControllerNameRenewedBlockNumber = int64(26)
+	TemporaryControllerNameRenewedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	controllerNameRenewedRawJson, _ = json.Marshal(EthControllerNameRenewedLog)
+)
+
+var EthControllerNameRenewedLog = types.Log{
+	Address: common.HexToAddress(ControllerAddress),
+	Topics: []common.Hash{
+		common.HexToHash(ControllerNameRenewedSignature),
+		registrarLabelHash,
+	},
+	Data:        hexutil.MustDecode(TemporaryControllerNameData),
+	BlockNumber: uint64(TemporaryControllerNameRenewedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryControllerNameRenewedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var ControllerNameRenewedEntity = controller_name_renewed.NameRenewedEntity{
+	Name:             controllerName,
+	Label:            registrarLabelHash,
+	Cost:             controllerCost,
+	Expires:          expires,
+	LogIndex:         EthControllerNameRenewedLog.Index,
+	TransactionIndex: EthControllerNameRenewedLog.TxIndex,
+	Raw:              EthControllerNameRenewedLog,
+}
+
+var ControllerNameRenewedModel = controller_name_renewed.NameRenewedModel{
+	Name:             controllerName,
+	LabelHash:        registrarLabelHash.Hex(),
+	Cost:             controllerCost.String(),
+	Expires:          expires.String(),
+	LogIndex:         EthControllerNameRenewedLog.Index,
+	TransactionIndex: EthControllerNameRenewedLog.TxIndex,
+	Raw:              controllerNameRenewedRawJson,
+}
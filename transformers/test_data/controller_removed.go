@@ -0,0 +1,66 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/controller_removed"
+)
+
+const (
+	TemporaryControllerRemovedBlockNumber = int64(26)
+	TemporaryControllerRemovedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	controllerRemovedRawJson, _ = json.Marshal(EthControllerRemovedLog)
+)
+
+var EthControllerRemovedLog = types.Log{
+	Address: common.HexToAddress(BaseRegistrarAddress),
+	Topics: []common.Hash{
+		common.HexToHash(ControllerRemovedSignature),
+		common.HexToHash("0x000000000000000000000000283af0b28c62c092c9727f1ee09c02ca627eb7f5"),
+	},
+	Data:        []byte{},
+	BlockNumber: uint64(TemporaryControllerRemovedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryControllerRemovedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var ControllerRemovedEntity = controller_removed.ControllerRemovedEntity{
+	Controller:       registrarController,
+	LogIndex:         EthControllerRemovedLog.Index,
+	TransactionIndex: EthControllerRemovedLog.TxIndex,
+	Raw:              EthControllerRemovedLog,
+}
+
+var ControllerRemovedModel = controller_removed.ControllerRemovedModel{
+	Controller:       registrarController.Hex(),
+	LogIndex:         EthControllerRemovedLog.Index,
+	TransactionIndex: EthControllerRemovedLog.TxIndex,
+	Raw:              controllerRemovedRawJson,
+}
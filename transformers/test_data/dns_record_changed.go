@@ -0,0 +1,86 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_record_changed"
+)
+
+const (
+	TemporaryDNSRecordChangedBlockNumber = int64(26)
+	TemporaryDNSRecordChangedData        = "0x0000000000000000000000000000000000000000000000000000000000000060000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000a0000000000000000000000000000000000000000000000000000000000000000f0161076578616d706c6503636f6d000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001d0161076578616d706c6503636f6d000001000100000e10000401020304000000"
+	TemporaryDNSRecordChangedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	dnsRecordChangedRawJson, _ = json.Marshal(EthDNSRecordChangedLog)
+	// a.example.com. in wire format, and its RRset: a.example.com. 3600 IN A 1.2.3.4
+	dnsName   = hexutil.MustDecode("0x0161076578616d706c6503636f6d00")
+	dnsRecord = hexutil.MustDecode("0x0161076578616d706c6503636f6d000001000100000e10000401020304")
+)
+
+var EthDNSRecordChangedLog = types.Log{
+	Address: common.HexToAddress(ResolverAddress),
+	Topics: []common.Hash{
+		common.HexToHash(DNSRecordChangedSignature),
+		common.HexToHash("0x4554480000000000000000000000000000000000000000000000000000000000"),
+	},
+	Data:        hexutil.MustDecode(TemporaryDNSRecordChangedData),
+	BlockNumber: uint64(TemporaryDNSRecordChangedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryDNSRecordChangedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var DNSRecordChangedEntity = dns_record_changed.DNSRecordChangedEntity{
+	Resolver:         common.HexToAddress(ResolverAddress),
+	Node:             node,
+	Name:             dnsName,
+	Resource:         1,
+	Record:           dnsRecord,
+	LogIndex:         EthDNSRecordChangedLog.Index,
+	TransactionIndex: EthDNSRecordChangedLog.TxIndex,
+	Raw:              EthDNSRecordChangedLog,
+}
+
+var DNSRecordChangedModel = dns_record_changed.DNSRecordChangedModel{
+	Resolver:         ResolverAddress,
+	Node:             node.Hex(),
+	Name:             "a.example.com.",
+	Resource:         "1",
+	Record:           hexutil.Encode(dnsRecord),
+	LogIndex:         EthDNSRecordChangedLog.Index,
+	TransactionIndex: EthDNSRecordChangedLog.TxIndex,
+	Raw:              dnsRecordChangedRawJson,
+	ResourceRecords: []dns_record_changed.ResourceRecordModel{{
+		Name:  "a.example.com.",
+		Type:  1,
+		Class: 1,
+		TTL:   3600,
+		Rdata: "0x01020304",
+	}},
+}
@@ -0,0 +1,74 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_record_deleted"
+)
+
+const (
+	TemporaryDNSRecordDeletedBlockNumber = int64(26)
+	TemporaryDNSRecordDeletedData        = "0x00000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000f0161076578616d706c6503636f6d000000000000000000000000000000000000"
+	TemporaryDNSRecordDeletedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	dnsRecordDeletedRawJson, _ = json.Marshal(EthDNSRecordDeletedLog)
+)
+
+var EthDNSRecordDeletedLog = types.Log{
+	Address: common.HexToAddress(ResolverAddress),
+	Topics: []common.Hash{
+		common.HexToHash(DNSRecordDeletedSignature),
+		common.HexToHash("0x4554480000000000000000000000000000000000000000000000000000000000"),
+	},
+	Data:        hexutil.MustDecode(TemporaryDNSRecordDeletedData),
+	BlockNumber: uint64(TemporaryDNSRecordDeletedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryDNSRecordDeletedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var DNSRecordDeletedEntity = dns_record_deleted.DNSRecordDeletedEntity{
+	Resolver:         common.HexToAddress(ResolverAddress),
+	Node:             node,
+	Name:             dnsName,
+	Resource:         1,
+	LogIndex:         EthDNSRecordDeletedLog.Index,
+	TransactionIndex: EthDNSRecordDeletedLog.TxIndex,
+	Raw:              EthDNSRecordDeletedLog,
+}
+
+var DNSRecordDeletedModel = dns_record_deleted.DNSRecordDeletedModel{
+	Resolver:         ResolverAddress,
+	Node:             node.Hex(),
+	Name:             "a.example.com.",
+	Resource:         "1",
+	LogIndex:         EthDNSRecordDeletedLog.Index,
+	TransactionIndex: EthDNSRecordDeletedLog.TxIndex,
+	Raw:              dnsRecordDeletedRawJson,
+}
@@ -0,0 +1,75 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/resolver/dns_zonehash_changed"
+)
+
+const (
+	TemporaryDNSZonehashChangedBlockNumber = int64(26)
+	TemporaryDNSZonehashChangedData        = "0x0000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000006000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000026e301017012201687de19f1516b9e560ab8655faa678e3a023ebff43494ac06a36581aafc957e0000000000000000000000000000000000000000000000000000"
+	TemporaryDNSZonehashChangedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	dnsZonehashChangedRawJson, _ = json.Marshal(EthDNSZonehashChangedLog)
+	zonehash                     = hexutil.MustDecode("0xe301017012201687de19f1516b9e560ab8655faa678e3a023ebff43494ac06a36581aafc957e")
+)
+
+var EthDNSZonehashChangedLog = types.Log{
+	Address: common.HexToAddress(ResolverAddress),
+	Topics: []common.Hash{
+		common.HexToHash(DNSZonehashChangedSignature),
+		common.HexToHash("0x4554480000000000000000000000000000000000000000000000000000000000"),
+	},
+	Data:        hexutil.MustDecode(TemporaryDNSZonehashChangedData),
+	BlockNumber: uint64(TemporaryDNSZonehashChangedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryDNSZonehashChangedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var DNSZonehashChangedEntity = dns_zonehash_changed.DNSZonehashChangedEntity{
+	Resolver:         common.HexToAddress(ResolverAddress),
+	Node:             node,
+	LastZonehash:     []byte{},
+	Zonehash:         zonehash,
+	LogIndex:         EthDNSZonehashChangedLog.Index,
+	TransactionIndex: EthDNSZonehashChangedLog.TxIndex,
+	Raw:              EthDNSZonehashChangedLog,
+}
+
+var DNSZonehashChangedModel = dns_zonehash_changed.DNSZonehashChangedModel{
+	Resolver:         ResolverAddress,
+	Node:             node.Hex(),
+	LastZonehash:     "0x",
+	Zonehash:         hexutil.Encode(zonehash),
+	LogIndex:         EthDNSZonehashChangedLog.Index,
+	TransactionIndex: EthDNSZonehashChangedLog.TxIndex,
+	Raw:              dnsZonehashChangedRawJson,
+}
@@ -72,5 +72,6 @@ var HashInvalidatedModel = hash_invalidated.HashInvalidatedModel{
 	RegistrationDate: registrationDate.String(),
 	LogIndex:         EthHashInvalidatedLog.Index,
 	TransactionIndex: EthHashInvalidatedLog.TxIndex,
+	TransactionHash:  EthHashInvalidatedLog.TxHash.Hex(),
 	Raw:              hashInvalidatedRawJson,
 }
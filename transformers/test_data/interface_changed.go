@@ -0,0 +1,78 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/resolver/interface_changed"
+)
+
+const (
+	TemporaryInterfaceChangedBlockNumber = int64(26)
+	TemporaryInterfaceChangedData        = "0x0000000000000000000000004976fb03c32e5b8cfe2b6ccb31c09ba78ebaba41"
+	TemporaryInterfaceChangedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	interfaceChangedRawJson, _ = json.Marshal(EthInterfaceChangedLog)
+	// The EIP-165 id of the multicoin addr(bytes32,uint256) method
+	interfaceID = [4]byte{0xf1, 0xcb, 0x7e, 0x06}
+	implementer = common.HexToAddress("0x4976fb03C32e5B8cfe2b6cCB31c09Ba78EBaBa41")
+)
+
+var EthInterfaceChangedLog = types.Log{
+	Address: common.HexToAddress(ResolverAddress),
+	Topics: []common.Hash{
+		common.HexToHash(InterfaceChangedSignature),
+		common.HexToHash("0x4554480000000000000000000000000000000000000000000000000000000000"),
+		common.HexToHash("0xf1cb7e0600000000000000000000000000000000000000000000000000000000"),
+	},
+	Data:        hexutil.MustDecode(TemporaryInterfaceChangedData),
+	BlockNumber: uint64(TemporaryInterfaceChangedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryInterfaceChangedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var InterfaceChangedEntity = interface_changed.InterfaceChangedEntity{
+	Resolver:         common.HexToAddress(ResolverAddress),
+	Node:             node,
+	InterfaceID:      interfaceID,
+	Implementer:      implementer,
+	LogIndex:         EthInterfaceChangedLog.Index,
+	TransactionIndex: EthInterfaceChangedLog.TxIndex,
+	Raw:              EthInterfaceChangedLog,
+}
+
+var InterfaceChangedModel = interface_changed.InterfaceChangedModel{
+	Resolver:         ResolverAddress,
+	Node:             node.Hex(),
+	InterfaceID:      "0xf1cb7e06",
+	Implementer:      implementer.Hex(),
+	LogIndex:         EthInterfaceChangedLog.Index,
+	TransactionIndex: EthInterfaceChangedLog.TxIndex,
+	Raw:              interfaceChangedRawJson,
+}
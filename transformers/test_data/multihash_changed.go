@@ -30,12 +30,14 @@ import (
 
 const (
 	TemporaryMultihashChangedBlockNumber = int64(26)
-	TemporaryMultihashChangedData        = "0x00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000026e40101701b206c41b0e4e24593f5155277e2a1ec45545db24d424974eb71173421523db0e2a60000000000000000000000000000000000000000000000000000"
+	TemporaryMultihashChangedData        = "0x00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000022122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f000000000000000000000000000000000000000000000000000000000000"
 	TemporaryMultihashChangedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
 )
 
 var (
 	multihashChangedRawJson, _ = json.Marshal(EthMultihashChangedLog)
+	// The sha2-256 multihash of the dag-pb IPFS content QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4
+	targetMultihash = hexutil.MustDecode("0x122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f")
 )
 
 var EthMultihashChangedLog = types.Log{
@@ -56,7 +58,7 @@ var EthMultihashChangedLog = types.Log{
 var MultihashChangedEntity = multihash_changed.MultihashChangedEntity{
 	Resolver:         common.HexToAddress(ResolverAddress),
 	Node:             node,
-	Hash:             targetHash,
+	Hash:             targetMultihash,
 	LogIndex:         EthMultihashChangedLog.Index,
 	TransactionIndex: EthMultihashChangedLog.TxIndex,
 	Raw:              EthMultihashChangedLog,
@@ -65,7 +67,9 @@ var MultihashChangedEntity = multihash_changed.MultihashChangedEntity{
 var MultihashChangedModel = multihash_changed.MultihashChangedModel{
 	Resolver:         ResolverAddress,
 	Node:             node.Hex(),
-	Hash:             targetHash,
+	Hash:             targetMultihash,
+	Protocol:         "ipfs",
+	URI:              "ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4",
 	LogIndex:         EthMultihashChangedLog.Index,
 	TransactionIndex: EthMultihashChangedLog.TxIndex,
 	Raw:              multihashChangedRawJson,
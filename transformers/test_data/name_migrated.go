@@ -0,0 +1,72 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/name_migrated"
+)
+
+const (
+	TemporaryNameMigratedBlockNumber = int64(26)
+	TemporaryNameMigratedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	nameMigratedRawJson, _ = json.Marshal(EthNameMigratedLog)
+)
+
+var EthNameMigratedLog = types.Log{
+	Address: common.HexToAddress(BaseRegistrarAddress),
+	Topics: []common.Hash{
+		common.HexToHash(NameMigratedSignature),
+		registrarLabelHash,
+		common.HexToHash("0x0000000000000000000000000000d8b4147eda80fec7122ae16da2479cbd7ffb"),
+	},
+	Data:        hexutil.MustDecode(TemporaryRegistrarExpiresData),
+	BlockNumber: uint64(TemporaryNameMigratedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryNameMigratedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var NameMigratedEntity = name_migrated.NameMigratedEntity{
+	Id:               registrarTokenId,
+	Owner:            owner,
+	Expires:          expires,
+	LogIndex:         EthNameMigratedLog.Index,
+	TransactionIndex: EthNameMigratedLog.TxIndex,
+	Raw:              EthNameMigratedLog,
+}
+
+var NameMigratedModel = name_migrated.NameMigratedModel{
+	LabelHash:        registrarLabelHash.Hex(),
+	Owner:            owner.Hex(),
+	Expires:          expires.String(),
+	LogIndex:         EthNameMigratedLog.Index,
+	TransactionIndex: EthNameMigratedLog.TxIndex,
+	Raw:              nameMigratedRawJson,
+}
@@ -0,0 +1,80 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/name_registered"
+)
+
+const (
+	TemporaryRegistrarExpiresData      = "0x000000000000000000000000000000000000000000000000000000005f5e1000"
+	TemporaryNameRegisteredBlockNumber = int64(26)
+	TemporaryNameRegisteredTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	nameRegisteredRawJson, _ = json.Marshal(EthNameRegisteredLog)
+	// The ERC-721 token id of a .eth name is its label hash as a uint256
+	registrarLabelHash  = common.HexToHash("0xaf2caa1c2ca1d027f1ac823b529d0a67cd144264b2789fa2ea4d63a67c7103cc") // keccak256("vitalik")
+	registrarTokenId    = registrarLabelHash.Big()
+	registrarController = common.HexToAddress("0x283Af0B28c62C092C9727F1Ee09c02CA627EB7F5")
+	registrarRecipient  = common.HexToAddress("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5")
+	expires             = big.NewInt(1600000000)
+)
+
+var EthNameRegisteredLog = types.Log{
+	Address: common.HexToAddress(BaseRegistrarAddress),
+	Topics: []common.Hash{
+		common.HexToHash(NameRegisteredSignature),
+		registrarLabelHash,
+		common.HexToHash("0x0000000000000000000000000000d8b4147eda80fec7122ae16da2479cbd7ffb"),
+	},
+	Data:        hexutil.MustDecode(TemporaryRegistrarExpiresData),
+	BlockNumber: uint64(TemporaryNameRegisteredBlockNumber),
+	TxHash:      common.HexToHash(TemporaryNameRegisteredTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var NameRegisteredEntity = name_registered.NameRegisteredEntity{
+	Id:               registrarTokenId,
+	Owner:            owner,
+	Expires:          expires,
+	LogIndex:         EthNameRegisteredLog.Index,
+	TransactionIndex: EthNameRegisteredLog.TxIndex,
+	Raw:              EthNameRegisteredLog,
+}
+
+var NameRegisteredModel = name_registered.NameRegisteredModel{
+	LabelHash:        registrarLabelHash.Hex(),
+	Owner:            owner.Hex(),
+	Expires:          expires.String(),
+	LogIndex:         EthNameRegisteredLog.Index,
+	TransactionIndex: EthNameRegisteredLog.TxIndex,
+	Raw:              nameRegisteredRawJson,
+}
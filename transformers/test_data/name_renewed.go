@@ -0,0 +1,69 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/name_renewed"
+)
+
+const (
+	TemporaryNameRenewedBlockNumber = int64(26)
+	TemporaryNameRenewedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	nameRenewedRawJson, _ = json.Marshal(EthNameRenewedLog)
+)
+
+var EthNameRenewedLog = types.Log{
+	Address: common.HexToAddress(BaseRegistrarAddress),
+	Topics: []common.Hash{
+		common.HexToHash(NameRenewedSignature),
+		registrarLabelHash,
+	},
+	Data:        hexutil.MustDecode(TemporaryRegistrarExpiresData),
+	BlockNumber: uint64(TemporaryNameRenewedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryNameRenewedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var NameRenewedEntity = name_renewed.NameRenewedEntity{
+	Id:               registrarTokenId,
+	Expires:          expires,
+	LogIndex:         EthNameRenewedLog.Index,
+	TransactionIndex: EthNameRenewedLog.TxIndex,
+	Raw:              EthNameRenewedLog,
+}
+
+var NameRenewedModel = name_renewed.NameRenewedModel{
+	LabelHash:        registrarLabelHash.Hex(),
+	Expires:          expires.String(),
+	LogIndex:         EthNameRenewedLog.Index,
+	TransactionIndex: EthNameRenewedLog.TxIndex,
+	Raw:              nameRenewedRawJson,
+}
@@ -0,0 +1,66 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/controller/new_price_oracle"
+)
+
+const (
+	TemporaryNewPriceOracleBlockNumber = int64(26)
+	TemporaryNewPriceOracleTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	newPriceOracleRawJson, _ = json.Marshal(EthNewPriceOracleLog)
+)
+
+var EthNewPriceOracleLog = types.Log{
+	Address: common.HexToAddress(ControllerAddress),
+	Topics: []common.Hash{
+		common.HexToHash(NewPriceOracleSignature),
+		common.HexToHash("0x000000000000000000000000b9d374d0fe3d8341155663fae31b7beae0ae233a"),
+	},
+	Data:        []byte{},
+	BlockNumber: uint64(TemporaryNewPriceOracleBlockNumber),
+	TxHash:      common.HexToHash(TemporaryNewPriceOracleTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var NewPriceOracleEntity = new_price_oracle.NewPriceOracleEntity{
+	Oracle:           priceOracle,
+	LogIndex:         EthNewPriceOracleLog.Index,
+	TransactionIndex: EthNewPriceOracleLog.TxIndex,
+	Raw:              EthNewPriceOracleLog,
+}
+
+var NewPriceOracleModel = new_price_oracle.NewPriceOracleModel{
+	Oracle:           priceOracle.Hex(),
+	LogIndex:         EthNewPriceOracleLog.Index,
+	TransactionIndex: EthNewPriceOracleLog.TxIndex,
+	Raw:              newPriceOracleRawJson,
+}
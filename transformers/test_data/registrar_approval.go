@@ -0,0 +1,72 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/base_registrar/registrar_approval"
+)
+
+const (
+	TemporaryRegistrarApprovalBlockNumber = int64(26)
+	TemporaryRegistrarApprovalTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	registrarApprovalRawJson, _ = json.Marshal(EthRegistrarApprovalLog)
+)
+
+var EthRegistrarApprovalLog = types.Log{
+	Address: common.HexToAddress(BaseRegistrarAddress),
+	Topics: []common.Hash{
+		common.HexToHash(RegistrarApprovalSignature),
+		common.HexToHash("0x0000000000000000000000000000d8b4147eda80fec7122ae16da2479cbd7ffb"),
+		common.HexToHash("0x000000000000000000000000ffd1ac3e8818adcbe5c597ea076e8d3210b45df5"),
+		registrarLabelHash,
+	},
+	Data:        []byte{},
+	BlockNumber: uint64(TemporaryRegistrarApprovalBlockNumber),
+	TxHash:      common.HexToHash(TemporaryRegistrarApprovalTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var RegistrarApprovalEntity = registrar_approval.RegistrarApprovalEntity{
+	Owner:            owner,
+	Approved:         registrarRecipient,
+	TokenId:          registrarTokenId,
+	LogIndex:         EthRegistrarApprovalLog.Index,
+	TransactionIndex: EthRegistrarApprovalLog.TxIndex,
+	Raw:              EthRegistrarApprovalLog,
+}
+
+var RegistrarApprovalModel = registrar_approval.RegistrarApprovalModel{
+	Owner:            owner.Hex(),
+	Approved:         registrarRecipient.Hex(),
+	LabelHash:        registrarLabelHash.Hex(),
+	LogIndex:         EthRegistrarApprovalLog.Index,
+	TransactionIndex: EthRegistrarApprovalLog.TxIndex,
+	Raw:              registrarApprovalRawJson,
+}
@@ -0,0 +1,71 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/reverse_registrar/reverse_claimed"
+)
+
+const (
+	TemporaryReverseClaimedBlockNumber = int64(26)
+	TemporaryReverseClaimedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	reverseClaimedRawJson, _ = json.Marshal(EthReverseClaimedLog)
+	reverseClaimant          = common.HexToAddress("0xfFD1Ac3e8818AdCbe5C597ea076E8D3210B45df5")
+	claimedReverseNode       = common.HexToHash("0xc064784cc17d497072842ddd7f42163fb0869db593d84febdf21412021cf025c")
+)
+
+var EthReverseClaimedLog = types.Log{
+	Address: common.HexToAddress(ReverseRegistrarAddress),
+	Topics: []common.Hash{
+		common.HexToHash(ReverseClaimedSignature),
+		common.HexToHash("0x000000000000000000000000ffd1ac3e8818adcbe5c597ea076e8d3210b45df5"),
+		claimedReverseNode,
+	},
+	Data:        []byte{},
+	BlockNumber: uint64(TemporaryReverseClaimedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryReverseClaimedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var ReverseClaimedEntity = reverse_claimed.ReverseClaimedEntity{
+	Addr:             reverseClaimant,
+	Node:             claimedReverseNode,
+	LogIndex:         EthReverseClaimedLog.Index,
+	TransactionIndex: EthReverseClaimedLog.TxIndex,
+	Raw:              EthReverseClaimedLog,
+}
+
+var ReverseClaimedModel = reverse_claimed.ReverseClaimedModel{
+	Addr:             reverseClaimant.Hex(),
+	Node:             claimedReverseNode.Hex(),
+	LogIndex:         EthReverseClaimedLog.Index,
+	TransactionIndex: EthReverseClaimedLog.TxIndex,
+	Raw:              reverseClaimedRawJson,
+}
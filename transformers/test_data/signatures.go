@@ -20,14 +20,21 @@ import "github.com/vulcanize/vulcanizedb/pkg/contract_watcher/shared/helpers"
 
 var (
 	// Resolver
-	AddrChangedSignature        = helpers.GenerateSignature("AddrChanged(bytes32,address)")
-	ContentChangedSignature     = helpers.GenerateSignature("ContentChanged(bytes32,bytes32)")
-	NameChangedSignature        = helpers.GenerateSignature("NameChanged(bytes32,string)")
-	AbiChangedSignature         = helpers.GenerateSignature("ABIChanged(bytes32,uint256)")
-	PubkeyChangedSignature      = helpers.GenerateSignature("PubkeyChanged(bytes32,bytes32,bytes32)")
-	TextChangedSignature        = helpers.GenerateSignature("TextChanged(bytes32,string,string)")
-	MultihashChangedSignature   = helpers.GenerateSignature("MultihashChanged(bytes32,bytes)")
-	ContenthashChangedSignature = helpers.GenerateSignature("ContenthashChanged(bytes32,bytes)")
+	AddrChangedSignature          = helpers.GenerateSignature("AddrChanged(bytes32,address)")
+	ContentChangedSignature       = helpers.GenerateSignature("ContentChanged(bytes32,bytes32)")
+	NameChangedSignature          = helpers.GenerateSignature("NameChanged(bytes32,string)")
+	AbiChangedSignature           = helpers.GenerateSignature("ABIChanged(bytes32,uint256)")
+	PubkeyChangedSignature        = helpers.GenerateSignature("PubkeyChanged(bytes32,bytes32,bytes32)")
+	TextChangedSignature          = helpers.GenerateSignature("TextChanged(bytes32,string,string)")
+	MultihashChangedSignature     = helpers.GenerateSignature("MultihashChanged(bytes32,bytes)")
+	ContenthashChangedSignature   = helpers.GenerateSignature("ContenthashChanged(bytes32,bytes)")
+	AddressChangedSignature       = helpers.GenerateSignature("AddressChanged(bytes32,uint256,bytes)")
+	InterfaceChangedSignature     = helpers.GenerateSignature("InterfaceChanged(bytes32,bytes4,address)")
+	AuthorisationChangedSignature = helpers.GenerateSignature("AuthorisationChanged(bytes32,address,address,bool)")
+	DNSRecordChangedSignature     = helpers.GenerateSignature("DNSRecordChanged(bytes32,bytes,uint16,bytes)")
+	DNSRecordDeletedSignature     = helpers.GenerateSignature("DNSRecordDeleted(bytes32,bytes,uint16)")
+	DNSZonehashChangedSignature   = helpers.GenerateSignature("DNSZonehashChanged(bytes32,bytes,bytes)")
+	VersionChangedSignature       = helpers.GenerateSignature("VersionChanged(bytes32,uint64)")
 	// Registry
 	NewOwnerSignature    = helpers.GenerateSignature("NewOwner(bytes32,bytes32,address)")
 	TransferSignature    = helpers.GenerateSignature("Transfer(bytes32,address)")
@@ -40,4 +47,18 @@ var (
 	HashRegisteredSignature  = helpers.GenerateSignature("HashRegistered(bytes32,address,uint,uint)")
 	HashReleasedSignature    = helpers.GenerateSignature("HashReleased(bytes32,uint)")
 	HashInvalidatedSignature = helpers.GenerateSignature("HashInvalidated(bytes32,string,uint,uint)")
+	// BaseRegistrar
+	NameMigratedSignature      = helpers.GenerateSignature("NameMigrated(uint256,address,uint256)")
+	NameRegisteredSignature    = helpers.GenerateSignature("NameRegistered(uint256,address,uint256)")
+	NameRenewedSignature       = helpers.GenerateSignature("NameRenewed(uint256,uint256)")
+	ControllerAddedSignature   = helpers.GenerateSignature("ControllerAdded(address)")
+	ControllerRemovedSignature = helpers.GenerateSignature("ControllerRemoved(address)")
+	RegistrarTransferSignature = helpers.GenerateSignature("Transfer(address,address,uint256)")
+	RegistrarApprovalSignature = helpers.GenerateSignature("Approval(address,address,uint256)")
+	// ETHRegistrarController
+	ControllerNameRegisteredSignature = helpers.GenerateSignature("NameRegistered(string,bytes32,address,uint256,uint256)")
+	ControllerNameRenewedSignature    = helpers.GenerateSignature("NameRenewed(string,bytes32,uint256,uint256)")
+	NewPriceOracleSignature           = helpers.GenerateSignature("NewPriceOracle(address)")
+	// ReverseRegistrar
+	ReverseClaimedSignature = helpers.GenerateSignature("ReverseClaimed(address,bytes32)")
 )
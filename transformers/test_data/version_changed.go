@@ -0,0 +1,72 @@
+// VulcanizeDB
+// Copyright © 2019 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package test_data
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+
+	"github.com/vulcanize/ens_transformers/transformers/resolver/version_changed"
+)
+
+const (
+	TemporaryVersionChangedBlockNumber = int64(26)
+	TemporaryVersionChangedData        = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	TemporaryVersionChangedTransaction = "0x5c698f13940a2153440c6d19660878bc90219d9298fdcf37365aa8d88d40fc42"
+)
+
+var (
+	versionChangedRawJson, _ = json.Marshal(EthVersionChangedLog)
+)
+
+var EthVersionChangedLog = types.Log{
+	Address: common.HexToAddress(ResolverAddress),
+	Topics: []common.Hash{
+		common.HexToHash(VersionChangedSignature),
+		common.HexToHash("0x4554480000000000000000000000000000000000000000000000000000000000"),
+	},
+	Data:        hexutil.MustDecode(TemporaryVersionChangedData),
+	BlockNumber: uint64(TemporaryVersionChangedBlockNumber),
+	TxHash:      common.HexToHash(TemporaryVersionChangedTransaction),
+	TxIndex:     111,
+	BlockHash:   fakes.FakeHash,
+	Index:       7,
+	Removed:     false,
+}
+
+var VersionChangedEntity = version_changed.VersionChangedEntity{
+	Resolver:         common.HexToAddress(ResolverAddress),
+	Node:             node,
+	NewVersion:       1,
+	LogIndex:         EthVersionChangedLog.Index,
+	TransactionIndex: EthVersionChangedLog.TxIndex,
+	Raw:              EthVersionChangedLog,
+}
+
+var VersionChangedModel = version_changed.VersionChangedModel{
+	Resolver:         ResolverAddress,
+	Node:             node.Hex(),
+	NewVersion:       "1",
+	LogIndex:         EthVersionChangedLog.Index,
+	TransactionIndex: EthVersionChangedLog.TxIndex,
+	Raw:              versionChangedRawJson,
+}